@@ -31,8 +31,20 @@ func main() {
 	}
 
 	// Initialize scanner
+	cfg := scanner.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	scanner.Configure(cfg)
+	scanner.SetCredentialProvider(scanner.NewMemoryCredentials(cfg.GitCredentials))
 	scanner.Initialize()
 
+	// Start scan workers, then queue the scans restored from a previous run
+	scanner.StartWorkers(cfg.MaxConcurrentScans)
+	if err := scanner.RestoreScans(); err != nil {
+		log.Fatalf("Failed to restore scans: %v", err)
+	}
+
 	// Create router
 	r := gin.Default()
 
@@ -43,8 +55,27 @@ func main() {
 
 	// Scan endpoints
 	r.POST("/scan", handlers.ScanRepository)
+	r.POST("/scan/validate", handlers.ValidateScan)
+	r.POST("/scan/export", handlers.ExportRepository)
+	r.POST("/scan/preview", handlers.PreviewRepository)
+	r.POST("/scan/batch", handlers.ScanBatch)
+	r.GET("/scan/batch/:id", handlers.GetBatchStatus)
+	r.POST("/scan/batch/:id/retry", handlers.RetryBatch)
+	r.POST("/scan/compare", handlers.CompareRepositories)
+	r.GET("/scan/compare/:id", handlers.GetComparison)
 	r.GET("/scan/:id", handlers.GetScanStatus)
 	r.GET("/scan/:id/endpoints", handlers.GetEndpoints)
+	r.GET("/scan/:id/methods/:method", handlers.GetEndpointsByMethod)
+	r.GET("/scan/:id/tree", handlers.GetEndpointTree)
+	r.GET("/scan/:id/languages", handlers.GetLanguageReport)
+	r.GET("/scan/:id/download", handlers.DownloadScan)
+	r.POST("/scan/:id/rescan", handlers.RescanRepository)
+
+	// Admin endpoints
+	r.DELETE("/scan/:id/data", handlers.RequireAdmin(cfg.AdminToken), handlers.PurgeScanData)
+	r.GET("/admin/credentials", handlers.RequireAdmin(cfg.AdminToken), handlers.ListCredentials)
+	r.PUT("/admin/credentials/:host", handlers.RequireAdmin(cfg.AdminToken), handlers.RotateCredential)
+	r.DELETE("/admin/credentials/:host", handlers.RequireAdmin(cfg.AdminToken), handlers.RemoveCredential)
 
 	// Start server
 	log.Printf(`
@@ -0,0 +1,71 @@
+// Package handlers - Admin authentication and credential rotation
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autodoc/scanner/internal/scanner"
+)
+
+// RequireAdmin rejects requests without "Authorization: Bearer <token>".
+// An empty token disables the routes it guards.
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin endpoints are disabled"})
+			return
+		}
+
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CredentialRequest is the body of PUT /admin/credentials/:host
+type CredentialRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ListCredentials handles GET /admin/credentials. Only hosts are returned.
+func ListCredentials(c *gin.Context) {
+	hosts, err := scanner.CredentialHosts()
+	if err != nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"hosts": hosts})
+}
+
+// RotateCredential handles PUT /admin/credentials/:host
+func RotateCredential(c *gin.Context) {
+	var req CredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+	setCredential(c, req.Token)
+}
+
+// RemoveCredential handles DELETE /admin/credentials/:host
+func RemoveCredential(c *gin.Context) {
+	setCredential(c, "")
+}
+
+// setCredential stores or removes the token for the :host parameter
+func setCredential(c *gin.Context, token string) {
+	host := strings.ToLower(c.Param("host"))
+	if err := scanner.RotateCredential(host, token); err != nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
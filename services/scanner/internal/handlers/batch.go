@@ -0,0 +1,73 @@
+// Package handlers - Batch scan handlers
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/autodoc/scanner/internal/scanner"
+)
+
+// BatchRequest represents several repository scans submitted together
+type BatchRequest struct {
+	Scans []ScanRequest `json:"scans" binding:"required,min=1,dive"`
+}
+
+// ScanBatch queues a scan for each repository in the batch. The batch is
+// refused as a whole if any of its scans would be.
+func ScanBatch(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scans must list at least one scan, each with a URL"})
+		return
+	}
+
+	jobs := make([]scanner.ScanJob, 0, len(req.Scans))
+	for i, scan := range req.Scans {
+		job, code, err := newScanJob(scan)
+		if err != nil {
+			c.JSON(code, gin.H{"error": fmt.Sprintf("scans[%d]: %v", i, err)})
+			return
+		}
+		jobs = append(jobs, job)
+	}
+
+	batch := scanner.EnqueueBatch(uuid.New().String(), jobs)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"batch_id": batch.ID,
+		"scan_ids": batch.ScanIDs,
+		"status":   "queued",
+		"message":  "Batch started, check status at /scan/batch/" + batch.ID,
+	})
+}
+
+// GetBatchStatus returns a batch with the status of each of its scans
+func GetBatchStatus(c *gin.Context) {
+	status, err := scanner.GetBatch(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// RetryBatch re-queues only the failed scans of a batch
+func RetryBatch(c *gin.Context) {
+	batchID := c.Param("id")
+
+	retried, err := scanner.RetryBatch(batchID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"batch_id": batchID,
+		"retried":  retried,
+	})
+}
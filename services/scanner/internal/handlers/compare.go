@@ -0,0 +1,60 @@
+// Package handlers - Repository comparison handlers
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/autodoc/scanner/internal/scanner"
+)
+
+// CompareRequest names the two repositories or branches to scan and diff
+type CompareRequest struct {
+	Base ScanRequest `json:"base" binding:"required"`
+	Head ScanRequest `json:"head" binding:"required"`
+}
+
+// CompareRepositories queues scans of both sides; the diff is available
+// from the comparison once both complete
+func CompareRepositories(c *gin.Context) {
+	var req CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "base and head must each have a URL"})
+		return
+	}
+
+	base, code, err := newScanJob(req.Base)
+	if err != nil {
+		c.JSON(code, gin.H{"error": "base: " + err.Error()})
+		return
+	}
+	head, code, err := newScanJob(req.Head)
+	if err != nil {
+		c.JSON(code, gin.H{"error": "head: " + err.Error()})
+		return
+	}
+
+	cmp := scanner.EnqueueComparison(uuid.New().String(), base, head)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"compare_id":   cmp.ID,
+		"base_scan_id": cmp.BaseScanID,
+		"head_scan_id": cmp.HeadScanID,
+		"status":       "queued",
+		"message":      "Comparison started, check the diff at /scan/compare/" + cmp.ID,
+	})
+}
+
+// GetComparison returns a comparison's progress, with the added, removed
+// and changed endpoints once both scans have completed
+func GetComparison(c *gin.Context) {
+	status, err := scanner.GetComparison(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comparison not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/ugorji/go/codec"
+
+	"github.com/autodoc/scanner/internal/scanner"
+)
+
+// endpointsResponse mirrors the body of GET /scan/:id/endpoints
+type endpointsResponse struct {
+	ScanID    string             `json:"scan_id"`
+	Count     int                `json:"count"`
+	Endpoints []scanner.Endpoint `json:"endpoints"`
+}
+
+// scanFixture scans a repository holding main.py with the given source
+// under scanID
+func scanFixture(t *testing.T, scanID, source string) {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "main.py"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wt, _ := repo.Worktree()
+	wt.Add("main.py")
+	if _, err := wt.Commit("fixture", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner.StartScan(scanner.ScanJob{ScanID: scanID, URL: repoDir})
+}
+
+// TestGetEndpointsMsgPack verifies MessagePack responses carry the same data as JSON
+func TestGetEndpointsMsgPack(t *testing.T) {
+	scanID := "msgpack-scan"
+	scanFixture(t, scanID, "from fastapi import FastAPI\napp = FastAPI()\n\n@app.get(\"/users\")\ndef list_users():\n    return []\n\n@app.post(\"/users\")\ndef create_user(user: UserCreate):\n    return user\n")
+
+	r := gin.New()
+	r.GET("/scan/:id/endpoints", GetEndpoints)
+	get := func(accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/scan/"+scanID+"/endpoints", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET endpoints (Accept %q) = %d: %s", accept, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	jsonResp := get("")
+	if ct := jsonResp.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("default Content-Type = %q, want JSON", ct)
+	}
+	var fromJSON endpointsResponse
+	if err := json.Unmarshal(jsonResp.Body.Bytes(), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	packResp := get("application/msgpack")
+	if ct := packResp.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/msgpack") {
+		t.Errorf("msgpack Content-Type = %q", ct)
+	}
+	var fromPack endpointsResponse
+	if err := codec.NewDecoderBytes(packResp.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&fromPack); err != nil {
+		t.Fatalf("decoding msgpack: %v", err)
+	}
+
+	if fromJSON.Count != 2 {
+		t.Fatalf("JSON count = %d, want 2", fromJSON.Count)
+	}
+	if !reflect.DeepEqual(fromPack, fromJSON) {
+		t.Errorf("msgpack response differs from JSON:\nmsgpack: %+v\njson:    %+v", fromPack, fromJSON)
+	}
+}
+
+// TestGetEndpointsResultSize verifies the size header matches the full body
+// and count_only answers with the count alone, still sized for the full body
+func TestGetEndpointsResultSize(t *testing.T) {
+	scanID := "result-size-scan"
+	scanFixture(t, scanID, "from fastapi import FastAPI\napp = FastAPI()\n\n@app.get(\"/users\")\ndef list_users():\n    return []\n\n@app.post(\"/users\")\ndef create_user(user: UserCreate):\n    return user\n")
+
+	r := gin.New()
+	r.GET("/scan/:id/endpoints", GetEndpoints)
+	get := func(url, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", url, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	for _, accept := range []string{"", "application/msgpack"} {
+		full := get("/scan/"+scanID+"/endpoints", accept)
+		if got, want := full.Header().Get(ResultSizeHeader), strconv.Itoa(full.Body.Len()); got != want {
+			t.Errorf("Accept %q: %s = %s, want body length %s", accept, ResultSizeHeader, got, want)
+		}
+
+		countOnly := get("/scan/"+scanID+"/endpoints?count_only=true", accept)
+		if got := countOnly.Header().Get(ResultSizeHeader); got != strconv.Itoa(full.Body.Len()) {
+			t.Errorf("Accept %q: count_only %s = %s, want the full body length %d", accept, ResultSizeHeader, got, full.Body.Len())
+		}
+		if countOnly.Body.Len() >= full.Body.Len() {
+			t.Errorf("Accept %q: count_only body is %d bytes, want fewer than the full %d", accept, countOnly.Body.Len(), full.Body.Len())
+		}
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(get("/scan/"+scanID+"/endpoints?count_only=true", "").Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(body, map[string]any{"scan_id": scanID, "count": float64(2)}) {
+		t.Errorf("count_only body = %v, want scan_id and count 2 only", body)
+	}
+}
+
+// TestGetEndpointsGroupByVersion verifies ?group_by=version buckets a
+// mixed-version scan's endpoints and other groupings are rejected
+func TestGetEndpointsGroupByVersion(t *testing.T) {
+	scanID := "group-by-version-scan"
+	scanFixture(t, scanID, "from fastapi import FastAPI\napp = FastAPI()\n\n@app.get(\"/v1/users\")\ndef list_users_v1():\n    return []\n\n@app.get(\"/v2/users\")\ndef list_users_v2():\n    return []\n\n@app.get(\"/health\")\ndef health():\n    return \"ok\"\n")
+
+	r := gin.New()
+	r.GET("/scan/:id/endpoints", GetEndpoints)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scan/"+scanID+"/endpoints?group_by=version", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET ?group_by=version = %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Count  int                           `json:"count"`
+		Groups map[string][]scanner.Endpoint `json:"groups"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Count != 3 {
+		t.Errorf("count = %d, want 3", body.Count)
+	}
+	for version, path := range map[string]string{"v1": "/v1/users", "v2": "/v2/users", scanner.UnversionedGroup: "/health"} {
+		if eps := body.Groups[version]; len(eps) != 1 || eps[0].Path != path {
+			t.Errorf("group %s = %+v, want only %s", version, eps, path)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scan/"+scanID+"/endpoints?group_by=tag", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET ?group_by=tag = %d, want 400", w.Code)
+	}
+}
+
+// TestGetEndpointsSignature verifies the signature header verifies the
+// result as a client decodes it, and not once an endpoint is altered
+func TestGetEndpointsSignature(t *testing.T) {
+	cfg := scanner.DefaultConfig()
+	cfg.ResultSigningKey = "secret"
+	scanner.Configure(cfg)
+	t.Cleanup(func() { scanner.Configure(scanner.DefaultConfig()) })
+
+	scanID := "signed-endpoints-scan"
+	scanFixture(t, scanID, "from fastapi import FastAPI\napp = FastAPI()\n\n@app.get(\"/users\")\ndef list_users():\n    return []\n")
+
+	r := gin.New()
+	r.GET("/scan/:id/endpoints", GetEndpoints)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scan/"+scanID+"/endpoints", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET endpoints = %d: %s", w.Code, w.Body.String())
+	}
+	signature := w.Header().Get(ResultSignatureHeader)
+	if signature == "" {
+		t.Fatalf("no %s header", ResultSignatureHeader)
+	}
+
+	var body endpointsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	status, _ := scanner.GetStatus(scanID)
+	result := scanner.SignedResult{ScanID: scanID, Status: status.Status, Commit: status.Commit, Endpoints: body.Endpoints}
+	if !scanner.VerifyResult(result, "secret", signature) {
+		t.Error("signature does not verify the decoded result")
+	}
+	result.Endpoints[0].Path = "/admin"
+	if scanner.VerifyResult(result, "secret", signature) {
+		t.Error("signature verified an altered endpoint")
+	}
+}
+
+// TestGetEndpointsByMethod verifies only endpoints with the path's method are
+// returned and unknown methods are refused
+func TestGetEndpointsByMethod(t *testing.T) {
+	scanID := "methods-scan"
+	scanFixture(t, scanID, `from fastapi import FastAPI
+app = FastAPI()
+
+@app.get("/users")
+def list_users():
+    return []
+
+@app.post("/users")
+def create_user():
+    return {}
+
+@app.delete("/users/{user_id}")
+def delete_user(user_id: int):
+    return None
+
+@app.post("/orders")
+def create_order():
+    return {}
+`)
+
+	r := gin.New()
+	r.GET("/scan/:id/methods/:method", GetEndpointsByMethod)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	for _, method := range []string{"POST", "post"} {
+		w := get("/scan/" + scanID + "/methods/" + method)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /methods/%s = %d: %s", method, w.Code, w.Body.String())
+		}
+		var resp endpointsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Count != 2 || len(resp.Endpoints) != 2 {
+			t.Fatalf("/methods/%s count = %d, want 2: %+v", method, resp.Count, resp.Endpoints)
+		}
+		for _, ep := range resp.Endpoints {
+			if ep.Method != "POST" {
+				t.Errorf("/methods/%s returned %s %s", method, ep.Method, ep.Path)
+			}
+		}
+	}
+
+	if w := get("/scan/" + scanID + "/methods/FETCH"); w.Code != http.StatusBadRequest {
+		t.Errorf("GET /methods/FETCH = %d, want 400", w.Code)
+	}
+	if w := get("/scan/missing/methods/GET"); w.Code != http.StatusNotFound {
+		t.Errorf("GET missing scan = %d, want 404", w.Code)
+	}
+}
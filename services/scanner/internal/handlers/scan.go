@@ -2,9 +2,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
 	"github.com/google/uuid"
 
 	"github.com/autodoc/scanner/internal/scanner"
@@ -12,9 +20,18 @@ import (
 
 // ScanRequest represents a repository scan request
 type ScanRequest struct {
-	URL    string `json:"url" binding:"required"`
-	Branch string `json:"branch"`
-	Token  string `json:"token"`
+	URL      string `json:"url" binding:"required"`
+	Branch   string `json:"branch"`
+	Commit   string `json:"commit"`       // optional SHA, full or abbreviated
+	Callback string `json:"callback_url"` // optional; receives signed status callbacks
+	Token    string `json:"token"`
+	Priority string `json:"priority"` // high, normal (default) or low
+
+	MinConfidence float64  `json:"min_confidence"` // drop endpoints scoring lower (0-1)
+	AllBranches   bool     `json:"all_branches"`   // scan every branch head, listing the branches of each endpoint
+	DropPaths     []string `json:"drop_paths"`     // path globs dropped from the results; the server's DROP_PATHS when omitted
+
+	CallbackTemplate string `json:"callback_template"` // optional text/template for callback bodies
 }
 
 // ScanRepository handles repository scan requests
@@ -25,21 +42,132 @@ func ScanRepository(c *gin.Context) {
 		return
 	}
 
-	// Generate scan ID
-	scanID := uuid.New().String()
+	job, code, err := newScanJob(req)
+	if err != nil {
+		c.JSON(code, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Start scan in background goroutine
-	go func() {
-		scanner.StartScan(scanID, req.URL, req.Branch, req.Token)
-	}()
+	// Queue scan for the worker pool
+	scanner.Enqueue(job)
 
 	c.JSON(http.StatusAccepted, gin.H{
-		"scan_id": scanID,
+		"scan_id": job.ScanID,
 		"status":  "queued",
-		"message": "Scan started, check status at /scan/" + scanID,
+		"message": "Scan started, check status at /scan/" + job.ScanID,
 	})
 }
 
+// newScanJob validates a scan request and builds its job under a new scan
+// ID, returning the HTTP status to answer with when the request is refused
+func newScanJob(req ScanRequest) (scanner.ScanJob, int, error) {
+	if !scanner.HostAllowed(req.URL) {
+		return scanner.ScanJob{}, http.StatusForbidden, errors.New("Repository host is not in the allowlist")
+	}
+
+	priority, err := scanner.ParsePriority(req.Priority)
+	if err != nil {
+		return scanner.ScanJob{}, http.StatusBadRequest, err
+	}
+	if err := scanner.ValidateMinConfidence(req.MinConfidence); err != nil {
+		return scanner.ScanJob{}, http.StatusBadRequest, err
+	}
+	if req.AllBranches && req.Commit != "" {
+		return scanner.ScanJob{}, http.StatusBadRequest, errors.New("commit and all_branches cannot be combined")
+	}
+	if req.Callback != "" {
+		if err := scanner.ValidateCallbackURL(req.Callback); err != nil {
+			return scanner.ScanJob{}, http.StatusBadRequest, err
+		}
+	}
+	var callbackTemplate *template.Template
+	if req.CallbackTemplate != "" {
+		if callbackTemplate, err = scanner.ParseCallbackTemplate(req.CallbackTemplate); err != nil {
+			return scanner.ScanJob{}, http.StatusBadRequest, err
+		}
+	}
+
+	return scanner.ScanJob{
+		ScanID:   uuid.New().String(),
+		URL:      req.URL,
+		Branch:   req.Branch,
+		Commit:   req.Commit,
+		Token:    req.Token,
+		Priority: priority,
+
+		MinConfidence:    req.MinConfidence,
+		AllBranches:      req.AllBranches,
+		DropPaths:        req.DropPaths,
+		CallbackURL:      req.Callback,
+		CallbackTemplate: callbackTemplate,
+	}, http.StatusOK, nil
+}
+
+// ExportRepository scans a repository synchronously, streaming its endpoints
+// as NDJSON while they are extracted instead of storing the scan
+func ExportRepository(c *gin.Context) {
+	var req ScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "URL is required"})
+		return
+	}
+
+	if !scanner.HostAllowed(req.URL) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Repository host is not in the allowlist"})
+		return
+	}
+
+	if err := scanner.ValidateMinConfidence(req.MinConfidence); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	eps := make(chan scanner.Endpoint)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- scanner.StreamScan(req.URL, req.Branch, req.Token, req.MinConfidence, req.DropPaths, eps)
+	}()
+
+	c.Header("Content-Type", "application/x-ndjson")
+	written, _ := scanner.WriteNDJSON(c.Writer, eps)
+
+	if err := <-errc; err != nil {
+		if written == 0 && !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			if errors.Is(err, scanner.ErrExportPostProcessed) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Streaming export is unavailable while a post-process command is configured; use POST /scan"})
+				return
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		// Headers are gone; the last line reports the failure
+		json.NewEncoder(c.Writer).Encode(gin.H{"error": err.Error()})
+	}
+}
+
+// PreviewRepository clones a repository and reports its code and candidate
+// file counts by language and framework, without extracting endpoints
+func PreviewRepository(c *gin.Context) {
+	var req ScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "URL is required"})
+		return
+	}
+
+	if !scanner.HostAllowed(req.URL) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Repository host is not in the allowlist"})
+		return
+	}
+
+	preview, err := scanner.PreviewRepository(req.URL, req.Branch, req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
 // GetScanStatus returns the status of a scan
 func GetScanStatus(c *gin.Context) {
 	scanID := c.Param("id")
@@ -53,19 +181,271 @@ func GetScanStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
-// GetEndpoints returns the detected endpoints from a scan
+// GetEndpoints returns the detected endpoints from a scan, as MessagePack
+// when the client sends Accept: application/msgpack. With ?group_by=version
+// they're bucketed by API version instead of listed.
 func GetEndpoints(c *gin.Context) {
 	scanID := c.Param("id")
 
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != "version" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown group_by %q (expected version)", groupBy)})
+		return
+	}
+
 	endpoints, err := scanner.GetEndpoints(scanID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	full := negotiateRender(c, gin.H{
 		"scan_id":   scanID,
 		"count":     len(endpoints),
 		"endpoints": endpoints,
 	})
+	// Clients sizing a large result before downloading it get the exact
+	// length of the full body, also with count_only
+	if size, err := renderedSize(full); err == nil {
+		c.Header(ResultSizeHeader, strconv.FormatInt(size, 10))
+	}
+	// Signed results let clients check them with scanner.VerifyResult
+	if status, err := scanner.GetStatus(scanID); err == nil && status.Signature != "" {
+		c.Header(ResultSignatureHeader, status.Signature)
+	}
+
+	if countOnly, _ := strconv.ParseBool(c.Query("count_only")); countOnly {
+		c.Render(http.StatusOK, negotiateRender(c, gin.H{
+			"scan_id": scanID,
+			"count":   len(endpoints),
+		}))
+		return
+	}
+	if groupBy == "version" {
+		c.Render(http.StatusOK, negotiateRender(c, gin.H{
+			"scan_id": scanID,
+			"count":   len(endpoints),
+			"groups":  scanner.GroupByVersion(endpoints),
+		}))
+		return
+	}
+	c.Render(http.StatusOK, full)
+}
+
+// Headers of GET /scan/:id/endpoints
+const (
+	ResultSizeHeader      = "X-Result-Size"      // byte size of the full body
+	ResultSignatureHeader = "X-Result-Signature" // signature of the scan result, when RESULT_SIGNING_KEY is set
+)
+
+// negotiateRender renders MessagePack for ingesters that ask for it, JSON otherwise
+func negotiateRender(c *gin.Context, data any) render.Render {
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEMSGPACK2, binding.MIMEMSGPACK) {
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		return render.MsgPack{Data: data}
+	default:
+		return render.JSON{Data: data}
+	}
+}
+
+// renderedSize returns the number of bytes r writes, counted by rendering
+// it once to a writer that discards them
+func renderedSize(r render.Render) (int64, error) {
+	var w sizeWriter
+	err := r.Render(&w)
+	return w.n, err
+}
+
+// sizeWriter is an http.ResponseWriter counting the body bytes written to it
+type sizeWriter struct {
+	header http.Header
+	n      int64
+}
+
+func (w *sizeWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = http.Header{}
+	}
+	return w.header
+}
+
+func (w *sizeWriter) Write(b []byte) (int, error) {
+	w.n += int64(len(b))
+	return len(b), nil
+}
+
+func (w *sizeWriter) WriteHeader(int) {}
+
+// GetEndpointsByMethod returns the endpoints from a scan with the method in
+// the path, e.g. /scan/:id/methods/POST
+func GetEndpointsByMethod(c *gin.Context) {
+	scanID := c.Param("id")
+	method := c.Param("method")
+
+	if !scanner.KnownMethod(method) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown method %q", method)})
+		return
+	}
+
+	endpoints, err := scanner.GetEndpoints(scanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+
+	matched := scanner.FilterByMethod(endpoints, method)
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id":   scanID,
+		"method":    strings.ToUpper(method),
+		"count":     len(matched),
+		"endpoints": matched,
+	})
+}
+
+// GetEndpointTree returns a scan's endpoints as a hierarchical path tree
+func GetEndpointTree(c *gin.Context) {
+	scanID := c.Param("id")
+
+	endpoints, err := scanner.GetEndpoints(scanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id": scanID,
+		"tree":    scanner.BuildPathTree(endpoints),
+	})
+}
+
+// GetLanguageReport returns a scan's endpoints by language, flagging paths
+// implemented in more than one language
+func GetLanguageReport(c *gin.Context) {
+	scanID := c.Param("id")
+
+	endpoints, err := scanner.GetEndpoints(scanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id": scanID,
+		"report":  scanner.BuildLanguageReport(endpoints),
+	})
+}
+
+// DownloadScan streams a zip of every export format for a completed scan
+func DownloadScan(c *gin.Context) {
+	scanID := c.Param("id")
+
+	status, err := scanner.GetStatus(scanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+	if status.Status != "completed" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Scan is not completed", "status": status.Status})
+		return
+	}
+
+	eps, _ := scanner.GetEndpoints(scanID)
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=scan-%s.zip", scanID))
+	c.Status(http.StatusOK)
+	if err := scanner.WriteExportBundle(c.Writer, status, eps); err != nil {
+		// Headers are already sent; abort so the truncated archive is detectable
+		c.Error(err)
+		c.Abort()
+	}
+}
+
+// RescanRepository queues re-extraction of a finished scan's cached checkout
+func RescanRepository(c *gin.Context) {
+	scanID := c.Param("id")
+
+	status, err := scanner.Rescan(scanID)
+	if errors.Is(err, scanner.ErrScanNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+	if errors.Is(err, scanner.ErrScanInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Scan is still running, try again once it finishes"})
+		return
+	}
+	if errors.Is(err, scanner.ErrNoCachedTree) {
+		c.JSON(http.StatusConflict, gin.H{"error": "No cached checkout available, start a new scan"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// PurgeScanData deletes a scan's status, endpoints and cached checkout
+func PurgeScanData(c *gin.Context) {
+	scanID := c.Param("id")
+
+	err := scanner.PurgeScan(scanID)
+	if errors.Is(err, scanner.ErrScanNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+	if errors.Is(err, scanner.ErrScanInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Scan is still running, try again once it finishes"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ValidateRequest asks to compare a completed scan with an OpenAPI document
+type ValidateRequest struct {
+	ScanID string          `json:"scan_id" binding:"required"`
+	Spec   json.RawMessage `json:"spec" binding:"required"` // JSON object, or a JSON/YAML string
+}
+
+// ValidateScan reports drift between a scan's endpoints and an OpenAPI document
+func ValidateScan(c *gin.Context) {
+	var req ValidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scan_id and spec are required"})
+		return
+	}
+
+	status, err := scanner.GetStatus(req.ScanID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		return
+	}
+	if status.Status != "completed" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Scan is not completed", "status": status.Status})
+		return
+	}
+
+	// A string holds the document text (e.g. YAML); anything else is the document itself
+	spec := []byte(req.Spec)
+	var text string
+	if err := json.Unmarshal(req.Spec, &text); err == nil {
+		spec = []byte(text)
+	}
+
+	eps, _ := scanner.GetEndpoints(req.ScanID)
+	report, err := scanner.ValidateAgainstSpec(eps, spec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id": req.ScanID,
+		"report":  report,
+	})
 }
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/autodoc/scanner/internal/scanner"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// TestScanRepositoryHostAllowlist verifies disallowed hosts are refused before any clone
+func TestScanRepositoryHostAllowlist(t *testing.T) {
+	cfg := scanner.DefaultConfig()
+	cfg.GitHostAllowlist = []string{"github.com"}
+	scanner.Configure(cfg)
+	t.Cleanup(func() { scanner.Configure(scanner.DefaultConfig()) })
+
+	r := gin.New()
+	r.POST("/scan", ScanRepository)
+
+	for _, url := range []string{
+		"https://internal.example.com/org/repo",
+		"http://169.254.169.254/latest/meta-data",
+		"git@gitlab.com:org/repo.git",
+		"/etc",
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/scan", strings.NewReader(`{"url": "`+url+`"}`))
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("POST /scan %s = %d, want 403", url, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "allowlist") {
+			t.Errorf("POST /scan %s body = %s, want allowlist error", url, w.Body.String())
+		}
+	}
+
+	if !scanner.HostAllowed("https://GitHub.com/org/repo") {
+		t.Error("allowlisted host refused")
+	}
+}
+
+// TestExportRepositoryPostProcess verifies the streaming export is refused
+// while a post-process command is configured, since it couldn't be applied
+func TestExportRepositoryPostProcess(t *testing.T) {
+	cfg := scanner.DefaultConfig()
+	cfg.PostProcessCommand = "redact-endpoints"
+	scanner.Configure(cfg)
+	t.Cleanup(func() { scanner.Configure(scanner.DefaultConfig()) })
+
+	r := gin.New()
+	r.POST("/scan/export", ExportRepository)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/scan/export", strings.NewReader(`{"url": "https://github.com/org/repo"}`))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("POST /scan/export = %d, want 409", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "post-process") {
+		t.Errorf("POST /scan/export body = %s, want the post-process error", w.Body.String())
+	}
+}
@@ -0,0 +1,102 @@
+// Package scanner - Per-scan resource accounting
+package scanner
+
+import (
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ScanResources reports what a scan cost, for capacity planning
+type ScanResources struct {
+	// Checkout size on disk after cloning: the only temp disk space a scan
+	// uses, since extraction reads in place and exports are streamed
+	CloneSizeBytes int64  `json:"clone_size_bytes"`
+	PeakHeapBytes  uint64 `json:"peak_heap_bytes"` // approximate heap growth during extraction
+}
+
+// dirSize returns the total size of regular files under dir
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+// heapSampler tracks heap growth above a baseline at stage boundaries.
+// The heap is process-wide, so concurrent scans make this approximate.
+type heapSampler struct {
+	mu       sync.Mutex
+	baseline uint64
+	peak     uint64
+}
+
+// newHeapSampler records the current heap as the baseline
+func newHeapSampler() *heapSampler {
+	return &heapSampler{baseline: heapAlloc()}
+}
+
+// sample records the current heap growth if it's the largest seen
+func (h *heapSampler) sample() {
+	current := heapAlloc()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if current > h.baseline && current-h.baseline > h.peak {
+		h.peak = current - h.baseline
+	}
+}
+
+// Peak returns the largest heap growth observed
+func (h *heapSampler) Peak() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.peak
+}
+
+// heapAlloc returns the bytes of allocated heap objects
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// recordCloneSize stores the checkout size for a scan
+func recordCloneSize(scanID string, size int64) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	res := resourcesFor(scanID)
+	res.CloneSizeBytes = size
+}
+
+// recordPeakHeap stores the heap growth observed while extracting
+func recordPeakHeap(scanID string, peak uint64) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	res := resourcesFor(scanID)
+	if peak > res.PeakHeapBytes {
+		res.PeakHeapBytes = peak
+	}
+}
+
+// resourcesFor returns the scan's resource record, creating it if needed.
+// Callers must hold mu.
+func resourcesFor(scanID string) *ScanResources {
+	status := scans[scanID]
+	if status.Resources == nil {
+		status.Resources = &ScanResources{}
+	}
+	return status.Resources
+}
@@ -0,0 +1,84 @@
+// Package scanner - Human-readable action names derived from method and path
+package scanner
+
+import "strings"
+
+// actionVerbs name what each method does to the resource it targets. GET
+// on a collection (a path not ending in a parameter) lists it instead.
+var actionVerbs = map[string]string{
+	"GET":    "Get",
+	"HEAD":   "Check",
+	"POST":   "Create",
+	"PUT":    "Update",
+	"PATCH":  "Update",
+	"DELETE": "Delete",
+}
+
+// applyActionNames sets each endpoint's ActionName, e.g. "Get user" for
+// GET /users/{id}. Procedures, client calls and methods without a common
+// verb are left unnamed.
+func applyActionNames(found []Endpoint) {
+	for i := range found {
+		if found[i].Kind != "" {
+			continue
+		}
+		found[i].ActionName = actionName(found[i].Method, found[i].Path)
+	}
+}
+
+// actionName derives the action for a method on a path: GET /users is
+// "List users", GET /users/{id} "Get user", POST /users "Create user"
+func actionName(method, path string) string {
+	method = strings.ToUpper(method)
+	verb, ok := actionVerbs[method]
+	if !ok {
+		return ""
+	}
+
+	resource, collection := pathResource(path)
+	if resource == "" {
+		return verb
+	}
+	if method == "GET" && collection {
+		return "List " + resource
+	}
+	return verb + " " + singular(resource)
+}
+
+// pathResource returns the last path segment naming a resource, in words,
+// and whether the path ends at it rather than at a parameter beneath it
+func pathResource(path string) (string, bool) {
+	resource, collection := "", false
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "", strings.EqualFold(segment, "api"),
+			versionSegmentPattern.MatchString(segment), strings.ContainsAny(segment, "*("):
+			continue
+		case pathParamPattern.MatchString(segment):
+			collection = false
+			continue
+		}
+		resource = strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(segment))
+		collection = true
+	}
+	return resource, collection
+}
+
+// singular is a best-effort English singular of a resource's last word:
+// users -> user, categories -> category, addresses -> address
+func singular(resource string) string {
+	head, word := "", resource
+	if i := strings.LastIndex(resource, " "); i >= 0 {
+		head, word = resource[:i+1], resource[i+1:]
+	}
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		word = strings.TrimSuffix(word, "s")
+	}
+	return head + word
+}
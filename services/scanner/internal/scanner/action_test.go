@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"testing"
+)
+
+// TestActionName verifies the action derived for the common verbs
+func TestActionName(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/users/{id}", "Get user"},
+		{"GET", "/api/v1/users", "List users"},
+		{"POST", "/users", "Create user"},
+		{"PUT", "/users/:id", "Update user"},
+		{"PATCH", "/users/<int:user_id>", "Update user"},
+		{"DELETE", "/users/{id}", "Delete user"},
+		{"HEAD", "/users/{id}", "Check user"},
+		{"get", "/users/{id}/addresses", "List addresses"},
+		{"POST", "/users/{id}/addresses", "Create address"},
+		{"DELETE", "/product-categories/{id}", "Delete product category"},
+		{"GET", "/", "Get"},
+		{"OPTIONS", "/users", ""},
+	}
+
+	for _, tt := range tests {
+		if got := actionName(tt.method, tt.path); got != tt.want {
+			t.Errorf("actionName(%q, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestActionNamesOffByDefault verifies ActionName is only set when enabled
+func TestActionNamesOffByDefault(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	code := `@app.get("/users/{user_id}")
+def get_user(user_id: int):
+    pass
+`
+	if ep := ScanFile("app.py", code)[0]; ep.ActionName != "" {
+		t.Errorf("ActionName = %q by default, want empty", ep.ActionName)
+	}
+
+	config.ActionNames = true
+	if ep := ScanFile("app.py", code)[0]; ep.ActionName != "Get user" {
+		t.Errorf("ActionName = %q, want %q", ep.ActionName, "Get user")
+	}
+}
@@ -0,0 +1,189 @@
+// Package scanner - Routes declared by AWS API Gateway OpenAPI extensions
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// apiGatewayIntegrationKey attaches an operation to its backend
+	apiGatewayIntegrationKey = "x-amazon-apigateway-integration"
+	// apiGatewayAnyMethodKey is the catch-all operation for every method
+	apiGatewayAnyMethodKey = "x-amazon-apigateway-any-method"
+	// apiGatewayExtensionPrefix starts every API Gateway extension key
+	apiGatewayExtensionPrefix = "x-amazon-apigateway-"
+)
+
+// apiGatewayOperationKeys are the operation keys of an API Gateway path item
+var apiGatewayOperationKeys = append(openAPIMethods[:len(openAPIMethods):len(openAPIMethods)], apiGatewayAnyMethodKey)
+
+// extractAPIGatewayRoutes reads the operations of an API Gateway OpenAPI
+// document, with the integration each forwards to. Paths of a Swagger 2.0
+// export are reported under its basePath. Documents that fail to parse are
+// logged and skipped.
+//
+// The paths object and path items may be $refs, into the document or, with
+// load, into other files of the repository. Operations of a referenced path
+// item are reported on the line of its path. Paths whose refs can't be
+// resolved are logged and skipped.
+//
+// OpenAPI 3.1 webhooks and the callbacks of operations are reported too,
+// with kind webhook or callback, as the requests the API sends. OpenAPI 3
+// documents without API Gateway extensions only report those: their paths
+// are served by code the other extractors read. Swagger 2.0 documents are
+// legacy APIs' only description, so their paths are always reported.
+func extractAPIGatewayRoutes(filePath, content string, load FileLoader) []Endpoint {
+	doc, err := parseSpecDocument([]byte(content))
+	if err != nil {
+		log.Printf("⚠️  Skipping API Gateway spec %s: %v", filePath, err)
+		return nil
+	}
+	refs := newSpecResolver(filePath, doc, load)
+	pathsValue, pathsBase, err := refs.deref(doc["paths"], filePath)
+	if err != nil {
+		log.Printf("⚠️  Skipping API Gateway spec %s: paths: %v", filePath, err)
+		return nil
+	}
+	paths, _ := pathsValue.(map[string]any)
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+	base := specBasePath(doc)
+
+	root := filepath.ToSlash(filePath)
+	lines := sourceLines(content)
+	gateway := strings.Contains(content, apiGatewayExtensionPrefix)
+	withPaths := gateway || isSwagger2(doc)
+	var found []Endpoint
+	for _, path := range keys {
+		value, itemBase, err := refs.deref(paths[path], pathsBase)
+		if err != nil {
+			log.Printf("⚠️  %s: skipping %s: %v", filePath, path, err)
+			continue
+		}
+		item, _ := value.(map[string]any)
+		// Method keys are only looked up in this file for inline items
+		inline := pathsBase == root && !isSpecRef(paths[path])
+		pathLine := specKeyLine(lines, path, 0)
+		if pathsBase != root {
+			pathLine = specKeyLine(lines, "paths", 0)
+		}
+		for _, key := range apiGatewayOperationKeys {
+			op, ok := item[key].(map[string]any)
+			if !ok {
+				continue
+			}
+			method := strings.ToUpper(key)
+			if key == apiGatewayAnyMethodKey {
+				method = "ANY"
+			}
+			line := pathLine
+			if pathLine > 0 && inline {
+				if n := specKeyLine(lines, key, pathLine); n > 0 {
+					line = n
+				}
+			}
+			ep := Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, line),
+				Path:       joinRoutePath(base, path),
+				Method:     method,
+				FilePath:   filePath,
+				LineNumber: line,
+			}
+			if gateway {
+				ep.Tags = []string{GatewayTag}
+			} else {
+				ep.Tags = []string{extractTag(filePath, ep.Path)}
+			}
+			ep.Summary, _ = op["summary"].(string)
+			ep.Description, _ = op["description"].(string)
+			ep.InputModel, ep.ResponseType = specOperationModels(op)
+			ep.ValidatedInput = ep.InputModel != ""
+			if integration, ok := op[apiGatewayIntegrationKey].(map[string]any); ok {
+				ep.Upstream = integrationURI(integration["uri"])
+				ep.Integration, _ = integration["type"].(string)
+				ep.Integration = strings.ToLower(ep.Integration)
+			}
+			if withPaths {
+				found = append(found, ep)
+			}
+			found = append(found, specCallbacks(filePath, op, refs, itemBase, lines, line, inline && line != pathLine)...)
+		}
+	}
+	return append(found, specWebhooks(filePath, doc, refs, lines)...)
+}
+
+// isSpecRef reports whether v is a {$ref: ...} object
+func isSpecRef(v any) bool {
+	obj, _ := v.(map[string]any)
+	_, ok := obj["$ref"].(string)
+	return ok
+}
+
+// integrationURI reads an integration's uri, which CloudFormation templates
+// often build with an intrinsic function: {"Fn::Sub": "arn:aws:apigateway:..."}.
+// References are kept as ${Name} placeholders, as Fn::Sub writes them.
+func integrationURI(uri any) string {
+	switch v := uri.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if ref, ok := v["Ref"].(string); ok {
+			return "${" + ref + "}"
+		}
+		switch sub := v["Fn::Sub"].(type) {
+		case string:
+			return sub
+		case []any:
+			// Fn::Sub with variables: [template, {vars}]
+			if len(sub) > 0 {
+				return integrationURI(sub[0])
+			}
+		}
+		// Fn::Join: [separator, [parts]]
+		if join, ok := v["Fn::Join"].([]any); ok && len(join) == 2 {
+			sep, _ := join[0].(string)
+			parts, _ := join[1].([]any)
+			joined := make([]string, len(parts))
+			for i, part := range parts {
+				joined[i] = integrationURI(part)
+			}
+			return strings.Join(joined, sep)
+		}
+	}
+	return ""
+}
+
+// specKeyLine returns the 1-based line declaring key in a JSON or YAML
+// document at or after line index from, or 0 when it can't be found
+func specKeyLine(lines []string, key string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if declaresKey(lines[i], key) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// declaresKey reports whether line starts with key, optionally quoted,
+// followed by a colon
+func declaresKey(line, key string) bool {
+	rest := strings.TrimLeft(line, " \t\n\f\r")
+	if !strings.HasPrefix(rest, key) && (strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'")) {
+		rest = rest[1:]
+	}
+	rest, ok := strings.CutPrefix(rest, key)
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'") {
+		rest = rest[1:]
+	}
+	return strings.HasPrefix(strings.TrimLeft(rest, " \t\n\f\r"), ":")
+}
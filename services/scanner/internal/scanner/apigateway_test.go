@@ -0,0 +1,218 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAPIGatewayRoutes verifies operations are read from an API Gateway
+// OpenAPI document with their integration, including the any-method catch-all
+func TestAPIGatewayRoutes(t *testing.T) {
+	content := `openapi: "3.0.1"
+info:
+  title: orders-api
+paths:
+  /orders:
+    get:
+      summary: List orders
+      x-amazon-apigateway-integration:
+        type: AWS_PROXY
+        httpMethod: POST
+        uri: !Sub arn:aws:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${ListOrders.Arn}/invocations
+    post:
+      x-amazon-apigateway-integration:
+        type: http_proxy
+        httpMethod: POST
+        uri: https://orders.internal.example.com/orders
+  /legacy/{proxy+}:
+    x-amazon-apigateway-any-method:
+      parameters:
+        - name: proxy
+          in: path
+          required: true
+      x-amazon-apigateway-integration:
+        type: HTTP_PROXY
+        httpMethod: ANY
+        uri:
+          Fn::Join:
+            - ""
+            - - "https://"
+              - Ref: LegacyHost
+              - "/{proxy}"
+`
+	if !hasAPIIndicators("infra/openapi.yaml", content) {
+		t.Fatal("API Gateway spec rejected by the pre-filter")
+	}
+
+	endpoints := ScanFile("infra/openapi.yaml", content)
+	want := []struct {
+		method, path, upstream, integration string
+		line                                int
+	}{
+		{"GET", "/orders", "arn:aws:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${ListOrders.Arn}/invocations", "aws_proxy", 6},
+		{"POST", "/orders", "https://orders.internal.example.com/orders", "http_proxy", 12},
+		{"ANY", "/legacy/{proxy+}", "https://${LegacyHost}/{proxy}", "http_proxy", 18},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.LineNumber != w.line {
+			t.Errorf("endpoint %d = %s %s (line %d), want %s %s (line %d)", i, ep.Method, ep.Path, ep.LineNumber, w.method, w.path, w.line)
+		}
+		if ep.Upstream != w.upstream || ep.Integration != w.integration {
+			t.Errorf("endpoint %d integration = %s %s, want %s %s", i, ep.Integration, ep.Upstream, w.integration, w.upstream)
+		}
+		if len(ep.Tags) != 1 || ep.Tags[0] != GatewayTag {
+			t.Errorf("endpoint %d tags = %v, want [%s]", i, ep.Tags, GatewayTag)
+		}
+	}
+	if endpoints[0].Summary != "List orders" {
+		t.Errorf("Summary = %q, want List orders", endpoints[0].Summary)
+	}
+
+	// Specs without the API Gateway extensions are left alone
+	plain := "openapi: 3.0.1\npaths:\n  /orders:\n    get: {}\n"
+	if hasAPIIndicators("docs/openapi.yaml", plain) {
+		t.Error("plain OpenAPI document accepted by the pre-filter")
+	}
+}
+
+// TestAPIGatewaySwagger2 verifies a Swagger 2.0 export is read under its
+// basePath, with body and response schemas named from its definitions
+func TestAPIGatewaySwagger2(t *testing.T) {
+	content := `{
+  "swagger": "2.0",
+  "info": {"title": "orders-api"},
+  "basePath": "/v1",
+  "paths": {
+    "/orders": {
+      "get": {
+        "responses": {
+          "200": {"schema": {"type": "array", "items": {"$ref": "#/definitions/Order"}}}
+        },
+        "x-amazon-apigateway-integration": {"type": "aws_proxy", "uri": "arn:aws:lambda:list"}
+      },
+      "post": {
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/NewOrder"}}
+        ],
+        "responses": {
+          "201": {"schema": {"$ref": "#/definitions/Order"}}
+        },
+        "x-amazon-apigateway-integration": {"type": "aws_proxy", "uri": "arn:aws:lambda:create"}
+      }
+    }
+  },
+  "definitions": {
+    "Order": {"type": "object"},
+    "NewOrder": {"type": "object"}
+  }
+}
+`
+	endpoints := ScanFile("infra/swagger.json", content)
+	want := []struct {
+		method, path, input, response string
+	}{
+		{"GET", "/v1/orders", "", "Order[]"},
+		{"POST", "/v1/orders", "NewOrder", "Order"},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path {
+			t.Errorf("endpoint %d = %s %s, want %s %s", i, ep.Method, ep.Path, w.method, w.path)
+		}
+		if ep.InputModel != w.input || ep.ResponseType != w.response {
+			t.Errorf("%s %s models = %q -> %q, want %q -> %q", ep.Method, ep.Path, ep.InputModel, ep.ResponseType, w.input, w.response)
+		}
+	}
+}
+
+// TestPlainSwagger2 verifies a Swagger 2.0 document without API Gateway
+// extensions passes the pre-filter and reports its paths under basePath
+func TestPlainSwagger2(t *testing.T) {
+	content := `swagger: '2.0'
+info:
+  title: legacy-api
+basePath: /api/v2
+paths:
+  /users:
+    get:
+      summary: List users
+      responses:
+        '200':
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/User'
+  /users/{id}:
+    delete:
+      summary: Delete a user
+definitions:
+  User:
+    type: object
+`
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "swagger.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := getLikelyAPIFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("getLikelyAPIFiles() = %v, want the Swagger 2.0 spec", files)
+	}
+
+	endpoints := ScanFile("swagger.yaml", content)
+	want := []struct{ method, path, response string }{
+		{"GET", "/api/v2/users", "User[]"},
+		{"DELETE", "/api/v2/users/{id}", ""},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.ResponseType != w.response {
+			t.Errorf("endpoint %d = %s %s -> %q, want %s %s -> %q", i, ep.Method, ep.Path, ep.ResponseType, w.method, w.path, w.response)
+		}
+		if containsString(ep.Tags, GatewayTag) {
+			t.Errorf("%s %s tagged %s without API Gateway extensions", ep.Method, ep.Path, GatewayTag)
+		}
+	}
+}
+
+// TestSpecKeyLine verifies keys are found bare, quoted or indented, and not
+// as a prefix of a longer key
+func TestSpecKeyLine(t *testing.T) {
+	lines := []string{
+		`{`,
+		`  "/users/{id}": {`,
+		`    'get' :`,
+		`  /orders:`,
+		`  paths_extra: 1`,
+		`  paths: {}`,
+	}
+	for _, tc := range []struct {
+		key  string
+		from int
+		want int
+	}{
+		{"/users/{id}", 0, 2},
+		{"get", 0, 3},
+		{"/orders", 0, 4},
+		{"paths", 0, 6},
+		{"/users/{id}", 2, 0},
+		{"post", 0, 0},
+	} {
+		if got := specKeyLine(lines, tc.key, tc.from); got != tc.want {
+			t.Errorf("specKeyLine(%q, %d) = %d, want %d", tc.key, tc.from, got, tc.want)
+		}
+	}
+}
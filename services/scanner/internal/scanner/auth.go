@@ -0,0 +1,143 @@
+// Package scanner - Authentication schemes of secured routes
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AuthScheme describes how callers authenticate to a route, in the terms of
+// an OpenAPI security scheme
+type AuthScheme struct {
+	Type         string `json:"type"`                    // http, apiKey or oauth2
+	Scheme       string `json:"scheme,omitempty"`        // bearer or basic, for http
+	BearerFormat string `json:"bearer_format,omitempty"` // JWT, when tokens are JWTs
+	In           string `json:"in,omitempty"`            // where an apiKey is sent: header
+	Name         string `json:"name,omitempty"`          // apiKey header name, e.g. X-API-Key
+	TokenURL     string `json:"token_url,omitempty"`     // oauth2 password flow token endpoint
+}
+
+// Authentication scheme patterns, checked in order
+var (
+	// FastAPI: OAuth2PasswordBearer(tokenUrl="token")
+	oauth2PasswordPattern = regexp.MustCompile(`OAuth2PasswordBearer\s*\(\s*tokenUrl\s*=\s*["']([^"']*)["']`)
+	// FastAPI: APIKeyHeader(name="X-API-Key"), or a key read from the request:
+	// req.headers['x-api-key'], r.Header.Get("X-API-Key"), [FromHeader(Name = "Api-Key")]
+	apiKeyHeaderPattern = regexp.MustCompile(`APIKeyHeader\s*\(\s*(?:name\s*=\s*)?["']([^"']+)["']|(?i)["']((?:x-)?api[-_]?key)["']`)
+	// HTTPBasic(), "Basic " credentials, express-basic-auth
+	basicAuthPattern = regexp.MustCompile(`\bHTTPBasic\s*\(|["']Basic\s|\bbasicAuth\s*\(|\bBasicAuth\s*\(`)
+	// HTTPBearer(), "Bearer " tokens, passport-jwt, Nest AuthGuard('jwt'), Spring resource servers
+	bearerAuthPattern = regexp.MustCompile(`\bHTTPBearer\s*\(|["'` + "`" + `]Bearer\s|passport\.authenticate\s*\(\s*["']jwt|AuthGuard\s*\(\s*["']jwt|\bjwt\.verify\s*\(|\bJwtBearer|\boauth2ResourceServer\b`)
+	jwtPattern        = regexp.MustCompile(`(?i)\bjwt`)
+
+	// Markers of a route requiring authentication: auth dependencies and
+	// middleware (Depends(get_current_user), authenticate, checkJwt),
+	// guards and attributes ([Authorize], @UseGuards, @PreAuthorize)
+	securedRoutePattern = regexp.MustCompile(`\b(?:Depends|Security)\s*\(\s*(?:\w+\.)*\w*(?i:auth|current_user|token|api_key|apikey|verify|scheme)|` +
+		`\b(?:authenticate\w*|auth|requireAuth\w*|isAuthenticated|ensureAuth\w*|verify\w*Token|checkJwt|jwtAuth\w*|authMiddleware|requireApiKey|apiKeyAuth)\b\s*[,)]|` +
+		`passport\.authenticate\s*\(|@UseGuards\s*\(|\[Authorize\b|@(?:PreAuthorize|Secured|RolesAllowed)\b`)
+)
+
+// applyAuthSchemes sets Auth on secured routes. A scheme used in the route
+// itself wins; otherwise a secured route takes the scheme the file sets up,
+// such as a module-level OAuth2PasswordBearer or a token-checking middleware.
+func applyAuthSchemes(ext string, found []Endpoint, lines []string) {
+	var fileScheme *AuthScheme
+	checked := false
+	for i := range found {
+		ep := &found[i]
+		if ep.Kind == KindClientCall || ep.LineNumber < 1 || ep.LineNumber > len(lines) {
+			continue
+		}
+		source := routeSource(ext, lines, ep.LineNumber-1)
+		if scheme := detectAuthScheme(source); scheme != nil {
+			ep.Auth = scheme
+			continue
+		}
+		if len(ep.RequiredScopes) == 0 && !securedRoutePattern.MatchString(source) {
+			continue
+		}
+		if !checked {
+			fileScheme = detectAuthScheme(strings.Join(lines, "\n"))
+			checked = true
+		}
+		if fileScheme != nil {
+			scheme := *fileScheme
+			ep.Auth = &scheme
+		}
+	}
+}
+
+// routeSource returns a route's annotations, declaration and handler body
+func routeSource(ext string, lines []string, idx int) string {
+	first := idx
+	for first > 0 && idx-first < maxDecoratorLines {
+		code := strings.TrimSpace(lines[first-1])
+		if !strings.HasPrefix(code, "@") && !strings.HasPrefix(code, "[") {
+			break
+		}
+		first--
+	}
+	_, end := handlerSpan(ext, lines, idx)
+	return strings.Join(lines[first:end+1], "\n")
+}
+
+// detectAuthScheme returns the first authentication scheme used in source
+func detectAuthScheme(source string) *AuthScheme {
+	if m := oauth2PasswordPattern.FindStringSubmatch(source); m != nil {
+		return &AuthScheme{Type: "oauth2", TokenURL: m[1]}
+	}
+	if m := apiKeyHeaderPattern.FindStringSubmatch(source); m != nil {
+		return &AuthScheme{Type: "apiKey", In: "header", Name: m[1] + m[2]}
+	}
+	if basicAuthPattern.MatchString(source) {
+		return &AuthScheme{Type: "http", Scheme: "basic"}
+	}
+	if bearerAuthPattern.MatchString(source) {
+		scheme := &AuthScheme{Type: "http", Scheme: "bearer"}
+		if jwtPattern.MatchString(source) {
+			scheme.BearerFormat = "JWT"
+		}
+		return scheme
+	}
+	return nil
+}
+
+// securitySchemeName names a scheme under components.securitySchemes. API
+// keys are named after their header so several can coexist.
+func securitySchemeName(s *AuthScheme) string {
+	switch {
+	case s.Type == "oauth2":
+		return "oauth2"
+	case s.Type == "apiKey":
+		return s.Name
+	case s.Scheme == "basic":
+		return "basicAuth"
+	}
+	return "bearerAuth"
+}
+
+// securitySchemeObject renders a scheme as an OpenAPI security scheme object,
+// listing scopes for OAuth2
+func securitySchemeObject(s *AuthScheme, scopes []string) map[string]any {
+	obj := map[string]any{"type": s.Type}
+	switch s.Type {
+	case "http":
+		obj["scheme"] = s.Scheme
+		if s.BearerFormat != "" {
+			obj["bearerFormat"] = s.BearerFormat
+		}
+	case "apiKey":
+		obj["in"] = s.In
+		obj["name"] = s.Name
+	case "oauth2":
+		described := make(map[string]any, len(scopes))
+		for _, scope := range scopes {
+			described[scope] = ""
+		}
+		obj["flows"] = map[string]any{
+			"password": map[string]any{"tokenUrl": s.TokenURL, "scopes": described},
+		}
+	}
+	return obj
+}
@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAuthSchemes verifies secured routes take the authentication scheme
+// their file sets up, and open routes stay unsecured
+func TestAuthSchemes(t *testing.T) {
+	express := `const jwt = require('jsonwebtoken');
+
+function authenticate(req, res, next) {
+  const token = req.headers.authorization?.replace('Bearer ', '');
+  req.user = jwt.verify(token, process.env.SECRET);
+  next();
+}
+
+app.get('/orders', authenticate, listOrders);
+app.get('/health', health);
+`
+	fastAPI := `oauth2_scheme = OAuth2PasswordBearer(tokenUrl="auth/token")
+
+@app.get("/items")
+async def list_items(user: User = Security(get_current_user, scopes=["items:read"])):
+    return []
+
+@app.get("/me")
+async def me(token: str = Depends(oauth2_scheme)):
+    return decode(token)
+
+@app.get("/status")
+async def status():
+    return {}
+`
+	gin := `func main() {
+	r := gin.Default()
+	r.GET("/reports", func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	})
+	r.GET("/ping", ping)
+}
+`
+	bearer := &AuthScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+	oauth2 := &AuthScheme{Type: "oauth2", TokenURL: "auth/token"}
+	apiKey := &AuthScheme{Type: "apiKey", In: "header", Name: "X-API-Key"}
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     []*AuthScheme
+	}{
+		{"Express", "routes.js", express, []*AuthScheme{bearer, nil}},
+		{"FastAPI", "main.py", fastAPI, []*AuthScheme{oauth2, oauth2, nil}},
+		{"Gin", "main.go", gin, []*AuthScheme{apiKey, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !reflect.DeepEqual(endpoints[i].Auth, want) {
+					t.Errorf("%s Auth = %+v, want %+v", endpoints[i].Path, endpoints[i].Auth, want)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenAPISecuritySchemes verifies a bearer-secured route exports a
+// security scheme and a requirement referencing it
+func TestOpenAPISecuritySchemes(t *testing.T) {
+	eps := []Endpoint{
+		{ID: "orders", Path: "/orders", Method: "GET", Auth: &AuthScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}},
+		{ID: "items", Path: "/items", Method: "GET", RequiredScopes: []string{"items:read"}, Auth: &AuthScheme{Type: "oauth2", TokenURL: "token"}},
+		{ID: "health", Path: "/health", Method: "GET"},
+	}
+	doc := BuildOpenAPI(&ScanStatus{ID: "scan", URL: "https://github.com/org/shop"}, eps)
+
+	schemes := doc["components"].(map[string]any)["securitySchemes"].(map[string]any)
+	wantBearer := map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}
+	if !reflect.DeepEqual(schemes["bearerAuth"], wantBearer) {
+		t.Errorf("bearerAuth = %v, want %v", schemes["bearerAuth"], wantBearer)
+	}
+	wantOAuth2 := map[string]any{"type": "oauth2", "flows": map[string]any{
+		"password": map[string]any{"tokenUrl": "token", "scopes": map[string]any{"items:read": ""}},
+	}}
+	if !reflect.DeepEqual(schemes["oauth2"], wantOAuth2) {
+		t.Errorf("oauth2 = %v, want %v", schemes["oauth2"], wantOAuth2)
+	}
+
+	paths := doc["paths"].(map[string]any)
+	security := func(path string) any {
+		return paths[path].(map[string]any)["get"].(map[string]any)["security"]
+	}
+	if got, want := security("/orders"), []map[string][]string{{"bearerAuth": {}}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("/orders security = %v, want %v", got, want)
+	}
+	if got, want := security("/items"), []map[string][]string{{"oauth2": {"items:read"}}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("/items security = %v, want %v", got, want)
+	}
+	if got := security("/health"); got != nil {
+		t.Errorf("/health security = %v, want none", got)
+	}
+}
@@ -0,0 +1,136 @@
+// Package scanner - Batches of scans submitted together
+package scanner
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// Batch groups scans submitted in one request so they can be tracked and
+// retried together
+type Batch struct {
+	ID        string    `json:"id"`
+	ScanIDs   []string  `json:"scan_ids"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// The submitted jobs by scan ID, kept for retries. A job's clone token
+	// is dropped once its scan completes, or with the job when it's purged.
+	jobs map[string]ScanJob
+}
+
+// BatchStatus is a batch with the current status of each of its scans
+type BatchStatus struct {
+	Batch
+	Counts map[string]int `json:"counts"` // scans by status
+	Scans  []*ScanStatus  `json:"scans"`
+}
+
+var batches = make(map[string]*Batch)
+
+// ErrBatchNotFound is returned when no batch exists for an ID
+var ErrBatchNotFound = errors.New("batch not found")
+
+// EnqueueBatch records the jobs as one batch and queues each of them
+func EnqueueBatch(batchID string, jobs []ScanJob) *Batch {
+	batch := &Batch{ID: batchID, CreatedAt: time.Now(), jobs: make(map[string]ScanJob, len(jobs))}
+	for _, job := range jobs {
+		batch.ScanIDs = append(batch.ScanIDs, job.ScanID)
+		batch.jobs[job.ScanID] = job
+	}
+	mu.Lock()
+	batches[batchID] = batch
+	mu.Unlock()
+
+	for _, job := range jobs {
+		Enqueue(job)
+	}
+	return batch
+}
+
+// GetBatch returns a batch with the status of each of its scans
+func GetBatch(batchID string) (*BatchStatus, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	batch, exists := batches[batchID]
+	if !exists {
+		return nil, ErrBatchNotFound
+	}
+	status := &BatchStatus{Batch: *batch, Counts: make(map[string]int)}
+	for _, id := range batch.ScanIDs {
+		if scan, ok := scans[id]; ok {
+			status.Scans = append(status.Scans, scan)
+			status.Counts[scan.Status]++
+		}
+	}
+	return status, nil
+}
+
+// RetryBatch re-queues the batch's failed scans under their original IDs,
+// leaving every other scan untouched, and returns the IDs it re-queued.
+// Each failed scan is marked queued before the lock is released, so
+// concurrent retries don't queue it twice. Failed scans still hold their
+// clone tokens, so private repositories are retried with them.
+func RetryBatch(batchID string) ([]string, error) {
+	mu.Lock()
+	batch, exists := batches[batchID]
+	if !exists {
+		mu.Unlock()
+		return nil, ErrBatchNotFound
+	}
+	var failed []ScanJob
+	for _, id := range batch.ScanIDs {
+		if scan, ok := scans[id]; ok && scan.Status == "failed" {
+			scan.Status = "queued"
+			failed = append(failed, batch.jobs[id])
+		}
+	}
+	mu.Unlock()
+
+	retried := []string{}
+	for _, job := range failed {
+		log.Printf("🔁 Retrying scan %s of batch %s", job.ScanID, batchID)
+		Enqueue(job)
+		retried = append(retried, job.ScanID)
+	}
+	return retried, nil
+}
+
+// releaseBatchTokens drops the clone token a batch holds for a scan once
+// it has completed. A failed scan's token is kept for RetryBatch.
+func releaseBatchTokens(scanID string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if scan, ok := scans[scanID]; !ok || scan.Status != "completed" {
+		return
+	}
+	for _, batch := range batches {
+		if job, ok := batch.jobs[scanID]; ok {
+			job.Token = ""
+			batch.jobs[scanID] = job
+		}
+	}
+}
+
+// forgetBatchScan removes a purged scan from its batches, deleting batches
+// left empty. Callers must hold mu.
+func forgetBatchScan(scanID string) {
+	for batchID, batch := range batches {
+		if _, ok := batch.jobs[scanID]; !ok {
+			continue
+		}
+		delete(batch.jobs, scanID)
+		ids := batch.ScanIDs[:0:0]
+		for _, id := range batch.ScanIDs {
+			if id != scanID {
+				ids = append(ids, id)
+			}
+		}
+		batch.ScanIDs = ids
+		if len(ids) == 0 {
+			delete(batches, batchID)
+		}
+	}
+}
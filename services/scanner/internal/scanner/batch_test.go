@@ -0,0 +1,185 @@
+package scanner
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRetryBatch verifies only a batch's failed scans are re-queued, under
+// their original IDs, and completed scans are left as they were
+func TestRetryBatch(t *testing.T) {
+	ran := make(chan string, 4)
+	prevQueue := scanQueue
+	scanQueue = NewScanQueue(1, func(job ScanJob) { ran <- job.ScanID })
+	t.Cleanup(func() { scanQueue = prevQueue })
+
+	batch := EnqueueBatch("batch-retry", []ScanJob{
+		{ScanID: "batch-ok", URL: "https://github.com/org/ok"},
+		{ScanID: "batch-flaky", URL: "https://github.com/org/flaky"},
+	})
+	for range batch.ScanIDs {
+		select {
+		case <-ran:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for batch scans")
+		}
+	}
+
+	// One child completes, the other fails transiently
+	mu.Lock()
+	scans["batch-ok"].Status = "completed"
+	endpoints["batch-ok"] = []Endpoint{{Path: "/users", Method: "GET"}}
+	scans["batch-flaky"].Status = "failed"
+	scans["batch-flaky"].Error = "Failed to clone repository: connection reset"
+	mu.Unlock()
+
+	retried, err := RetryBatch("batch-retry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"batch-flaky"}; !reflect.DeepEqual(retried, want) {
+		t.Fatalf("RetryBatch() = %v, want %v", retried, want)
+	}
+	select {
+	case id := <-ran:
+		if id != "batch-flaky" {
+			t.Errorf("re-ran %s, want batch-flaky", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the retried scan")
+	}
+
+	status, err := GetBatch("batch-retry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.Counts; got["completed"] != 1 || got["queued"] != 1 {
+		t.Errorf("Counts = %v, want 1 completed and 1 queued", got)
+	}
+	if flaky, _ := GetStatus("batch-flaky"); flaky.Error != "" {
+		t.Errorf("retried scan kept its error %q", flaky.Error)
+	}
+	if eps, _ := GetEndpoints("batch-ok"); len(eps) != 1 {
+		t.Errorf("completed scan has %d endpoints after retry, want 1", len(eps))
+	}
+
+	// Nothing failed, nothing to retry
+	if retried, _ := RetryBatch("batch-retry"); len(retried) != 0 {
+		t.Errorf("second RetryBatch() = %v, want none", retried)
+	}
+	if _, err := RetryBatch("missing"); err != ErrBatchNotFound {
+		t.Errorf("RetryBatch(missing) error = %v, want ErrBatchNotFound", err)
+	}
+}
+
+// TestRetryBatchConcurrent verifies concurrent retries queue each failed
+// scan once
+func TestRetryBatchConcurrent(t *testing.T) {
+	ran := make(chan string, 8)
+	prevQueue := scanQueue
+	scanQueue = NewScanQueue(1, func(job ScanJob) { ran <- job.ScanID })
+	t.Cleanup(func() { scanQueue = prevQueue })
+
+	EnqueueBatch("batch-race", []ScanJob{
+		{ScanID: "batch-race-a", URL: "https://github.com/org/a"},
+		{ScanID: "batch-race-b", URL: "https://github.com/org/b"},
+	})
+	for range 2 {
+		<-ran
+	}
+	mu.Lock()
+	scans["batch-race-a"].Status = "failed"
+	scans["batch-race-b"].Status = "failed"
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	results := make([][]string, 4)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = RetryBatch("batch-race")
+		}()
+	}
+	wg.Wait()
+
+	var all []string
+	for _, retried := range results {
+		all = append(all, retried...)
+	}
+	sort.Strings(all)
+	if want := []string{"batch-race-a", "batch-race-b"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("concurrent retries queued %v, want each scan once: %v", all, want)
+	}
+}
+
+// TestBatchTokensReleased verifies a batch drops its clone tokens once all
+// TestBatchTokensReleased verifies a batch drops a scan's clone token once
+// it completes, keeps a failed scan's token for its retry, and is deleted
+// when its scans are purged
+func TestBatchTokensReleased(t *testing.T) {
+	ran := make(chan ScanJob, 4)
+	prevQueue := scanQueue
+	scanQueue = NewScanQueue(1, func(job ScanJob) { ran <- job })
+	t.Cleanup(func() { scanQueue = prevQueue })
+
+	EnqueueBatch("batch-tokens", []ScanJob{
+		{ScanID: "batch-tokens-a", URL: "https://github.com/org/a", Token: "ghp_a"},
+		{ScanID: "batch-tokens-b", URL: "https://github.com/org/b", Token: "ghp_b"},
+	})
+	for range 2 {
+		<-ran
+	}
+	tokens := func() map[string]string {
+		mu.RLock()
+		defer mu.RUnlock()
+		held := make(map[string]string)
+		for id, job := range batches["batch-tokens"].jobs {
+			if job.Token != "" {
+				held[id] = job.Token
+			}
+		}
+		return held
+	}
+	finish := func(scanID, status string) {
+		mu.Lock()
+		scans[scanID].Status = status
+		mu.Unlock()
+		releaseBatchTokens(scanID)
+	}
+
+	finish("batch-tokens-a", "completed")
+	finish("batch-tokens-b", "failed")
+	if held := tokens(); len(held) != 1 || held["batch-tokens-b"] != "ghp_b" {
+		t.Errorf("tokens held = %v, want only the failed scan's", held)
+	}
+
+	// The failed private scan is retried with its token
+	if _, err := RetryBatch("batch-tokens"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case job := <-ran:
+		if job.ScanID != "batch-tokens-b" || job.Token != "ghp_b" {
+			t.Errorf("retried job = %s with token %q, want batch-tokens-b with ghp_b", job.ScanID, job.Token)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the retry")
+	}
+	finish("batch-tokens-b", "completed")
+	if held := tokens(); len(held) != 0 {
+		t.Errorf("tokens held after every scan completed = %v, want none", held)
+	}
+
+	for _, id := range []string{"batch-tokens-a", "batch-tokens-b"} {
+		if err := PurgeScan(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := GetBatch("batch-tokens"); err != ErrBatchNotFound {
+		t.Errorf("GetBatch() after purging its scans = %v, want ErrBatchNotFound", err)
+	}
+}
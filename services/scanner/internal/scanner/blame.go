@@ -0,0 +1,47 @@
+// Package scanner - Last modification of endpoint declarations from git blame
+package scanner
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// attachBlame sets LastModified and LastAuthor on each endpoint from the
+// commit that last changed its declaration line, blaming each file once
+// against the checkout's HEAD. Checkouts without git history, and files or
+// lines blame can't attribute, are left as they are.
+func attachBlame(rootDir string, found []Endpoint) {
+	repo, err := git.PlainOpen(rootDir)
+	if err != nil {
+		return
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return
+	}
+
+	blamed := make(map[string]*git.BlameResult)
+	for i := range found {
+		ep := &found[i]
+		result, ok := blamed[ep.FilePath]
+		if !ok {
+			if result, err = git.Blame(commit, filepath.ToSlash(ep.FilePath)); err != nil {
+				log.Printf("⚠️  Skipping blame of %s: %v", ep.FilePath, err)
+			}
+			blamed[ep.FilePath] = result
+		}
+		if result == nil || ep.LineNumber < 1 || ep.LineNumber > len(result.Lines) {
+			continue
+		}
+		line := result.Lines[ep.LineNumber-1]
+		date := line.Date
+		ep.LastModified = &date
+		ep.LastAuthor = line.AuthorName
+	}
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TestAttachBlame verifies each endpoint carries the date and author of the
+// commit that last changed its declaration line
+func TestAttachBlame(t *testing.T) {
+	repoDir, repo := newFixtureRepo(t, map[string]string{"app.py": `from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/users")
+def list_users():
+    return []
+`})
+
+	// A second author adds a route below the first
+	later := time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC)
+	if err := os.WriteFile(filepath.Join(repoDir, "app.py"), []byte(`from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/users")
+def list_users():
+    return []
+
+@app.post("/users")
+def create_user():
+    return {}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("app.py"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Commit("add create", &git.CommitOptions{
+		Author: &object.Signature{Name: "Second Author", Email: "second@example.com", When: later},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.WithBlame = true
+
+	scanID := "blame-test"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+	endpoints[scanID] = []Endpoint{}
+	mu.Unlock()
+	scanCheckout(scanID, repoDir)
+
+	eps, _ := GetEndpoints(scanID)
+	want := map[string]struct {
+		author string
+		date   time.Time
+	}{
+		"GET":  {"Fixture", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		"POST": {"Second Author", later},
+	}
+	if len(eps) != len(want) {
+		t.Fatalf("found %d endpoints, want %d: %+v", len(eps), len(want), eps)
+	}
+	for _, ep := range eps {
+		w := want[ep.Method]
+		if ep.LastAuthor != w.author {
+			t.Errorf("%s %s LastAuthor = %q, want %q", ep.Method, ep.Path, ep.LastAuthor, w.author)
+		}
+		if ep.LastModified == nil || !ep.LastModified.Equal(w.date) {
+			t.Errorf("%s %s LastModified = %v, want %v", ep.Method, ep.Path, ep.LastModified, w.date)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package scanner - Scanning every branch head of a repository in one pass
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// remoteBranchPrefix holds the branch heads fetched by a clone
+const remoteBranchPrefix = "refs/remotes/" + git.DefaultRemoteName + "/"
+
+// branchHead is a remote branch and the commit at its head
+type branchHead struct {
+	name string
+	hash plumbing.Hash
+}
+
+// branchHeads lists the remote branch heads of a clone, the branch checked
+// out first and the others by name
+func branchHeads(repo *git.Repository, current string) ([]branchHead, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	var heads []branchHead
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name, ok := strings.CutPrefix(ref.Name().String(), remoteBranchPrefix)
+		if ok && name != "HEAD" && ref.Type() == plumbing.HashReference {
+			heads = append(heads, branchHead{name: name, hash: ref.Hash()})
+		}
+		return nil
+	})
+	sort.SliceStable(heads, func(i, j int) bool {
+		if (heads[i].name == current) != (heads[j].name == current) {
+			return heads[i].name == current
+		}
+		return heads[i].name < heads[j].name
+	})
+	return heads, err
+}
+
+// extractBranches checks out each remote branch head of the clone at rootDir
+// in turn and extracts its endpoints. A route found on several branches is
+// reported once, as first found, listing every branch declaring it; its
+// examples, docs and blame come from that branch's checkout. The file cap
+// applies to the code files of all branches together. The clone is left on
+// the branch it was cloned at.
+func extractBranches(scanID, rootDir string, minConfidence float64) ([]Endpoint, int, error) {
+	repo, err := git.PlainOpen(rootDir)
+	if err != nil {
+		return nil, 0, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, 0, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, 0, err
+	}
+	heads, err := branchHeads(repo, head.Name().Short())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer wt.Checkout(&git.CheckoutOptions{Branch: head.Name(), Force: true})
+
+	var merged []Endpoint
+	seen := make(map[string]int) // index in merged by method, path and file
+	var branches []string
+	var totalFiles, processedFiles int
+	for _, branch := range heads {
+		log.Printf("🌿 Scanning branch %s (%s)", branch.name, branch.hash)
+		if err := wt.Checkout(&git.CheckoutOptions{Hash: branch.hash, Force: true}); err != nil {
+			return merged, processedFiles, fmt.Errorf("failed to check out branch %s: %w", branch.name, err)
+		}
+
+		files, err := getCodeFiles(rootDir)
+		if err != nil {
+			return merged, processedFiles, err
+		}
+		if totalFiles += len(files); totalFiles > MaxFilesToScan {
+			return merged, processedFiles, fmt.Errorf("%w across branches at %s", ErrMaxFiles, branch.name)
+		}
+		apiFiles, err := getLikelyAPIFiles(rootDir)
+		if err != nil {
+			return merged, processedFiles, err
+		}
+
+		found, n, err := extractEndpoints(rootDir, apiFiles, minConfidence)
+		processedFiles += n
+		var fresh []Endpoint
+		for _, ep := range found {
+			key := ep.Method + " " + canonicalPath(ep.Path) + " " + ep.FilePath
+			if i, ok := seen[key]; ok {
+				if i < len(merged) {
+					merged[i].Branches = append(merged[i].Branches, branch.name)
+				} else {
+					fresh[i-len(merged)].Branches = append(fresh[i-len(merged)].Branches, branch.name)
+				}
+				continue
+			}
+			ep.Branches = []string{branch.name}
+			seen[key] = len(merged) + len(fresh)
+			fresh = append(fresh, ep)
+		}
+		// Examples, docs and blame of the routes first found here come
+		// from this branch's copy of their files
+		attachFileContext(rootDir, files, fresh)
+		merged = append(merged, fresh...)
+		if err != nil {
+			return merged, processedFiles, err
+		}
+		branches = append(branches, branch.name)
+	}
+
+	mu.Lock()
+	scans[scanID].Branches = branches
+	mu.Unlock()
+	return merged, processedFiles, nil
+}
@@ -0,0 +1,156 @@
+// Package scanner - Clone cache for re-extraction without re-cloning
+package scanner
+
+import (
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNoCachedTree is returned when a scan's checkout is no longer cached
+var ErrNoCachedTree = errors.New("no cached checkout available for scan")
+
+// cachedClone is a checkout kept on disk after its scan finished
+type cachedClone struct {
+	Dir      string
+	Commit   string
+	CachedAt time.Time
+}
+
+var (
+	cloneCache = make(map[string]cachedClone)
+	cacheOrder []string       // scan IDs, oldest first
+	cacheRefs  map[string]int // checkouts in use by a rescan, which eviction skips
+	cacheMu    sync.Mutex
+)
+
+// cacheClone keeps a checkout for scanID, evicting the oldest beyond the limit
+func cacheClone(scanID, dir, commit string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if _, exists := cloneCache[scanID]; !exists {
+		cacheOrder = append(cacheOrder, scanID)
+	}
+	cloneCache[scanID] = cachedClone{Dir: dir, Commit: commit, CachedAt: time.Now()}
+	evictClones()
+}
+
+// evictClones removes the oldest checkouts until the cache is within its
+// limit. Checkouts in use are neither evicted nor counted against it.
+// Callers must hold cacheMu.
+func evictClones() {
+	for i := 0; len(cacheOrder)-len(cacheRefs) > config.MaxCachedClones && i < len(cacheOrder); {
+		oldest := cacheOrder[i]
+		if cacheRefs[oldest] > 0 {
+			i++
+			continue
+		}
+		cacheOrder = append(cacheOrder[:i:i], cacheOrder[i+1:]...)
+		os.RemoveAll(cloneCache[oldest].Dir)
+		delete(cloneCache, oldest)
+		log.Printf("🗑️  Evicted cached checkout for scan %s", oldest)
+	}
+}
+
+// cachedCloneFor returns the cached checkout for scanID, if still on disk
+func cachedCloneFor(scanID string) (cachedClone, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return cachedCloneLocked(scanID)
+}
+
+// cachedCloneLocked is cachedCloneFor for callers holding cacheMu
+func cachedCloneLocked(scanID string) (cachedClone, bool) {
+	clone, exists := cloneCache[scanID]
+	if !exists {
+		return cachedClone{}, false
+	}
+	if _, err := os.Stat(clone.Dir); err != nil {
+		return cachedClone{}, false
+	}
+	return clone, true
+}
+
+// acquireClone returns the cached checkout for scanID and keeps it from
+// eviction until releaseClone
+func acquireClone(scanID string) (cachedClone, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	clone, ok := cachedCloneLocked(scanID)
+	if ok {
+		if cacheRefs == nil {
+			cacheRefs = make(map[string]int)
+		}
+		cacheRefs[scanID]++
+	}
+	return clone, ok
+}
+
+// releaseClone lets a checkout taken by acquireClone be evicted again
+func releaseClone(scanID string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if cacheRefs[scanID]--; cacheRefs[scanID] <= 0 {
+		delete(cacheRefs, scanID)
+	}
+	evictClones()
+}
+
+// Rescan queues re-extraction of a scan's cached checkout, updating its
+// endpoints in place under the same scan ID once a worker runs it. Scans
+// still queued or running are refused with ErrScanInProgress. The checkout
+// is kept from eviction until the rescan finishes.
+func Rescan(scanID string) (*ScanStatus, error) {
+	mu.Lock()
+	status, exists := scans[scanID]
+	if !exists {
+		mu.Unlock()
+		return nil, ErrScanNotFound
+	}
+	if status.Status == "queued" || status.Status == "scanning" {
+		mu.Unlock()
+		return nil, ErrScanInProgress
+	}
+	clone, cached := acquireClone(scanID)
+	if !cached {
+		mu.Unlock()
+		return nil, ErrNoCachedTree
+	}
+	status.Status = "queued"
+	status.Error = ""
+	status.ErrorCategory = ""
+	status.Signature = ""
+	status.Partial = false
+	status.CompletedAt = nil
+	status.StartedAt = time.Now()
+	queued := *status
+	mu.Unlock()
+	saveScanState(scanID, clone.Dir)
+
+	scanQueue.Push(ScanJob{ScanID: scanID, Priority: Priority(queued.Priority), Rescan: true})
+	return &queued, nil
+}
+
+// runRescan re-runs Stage 1 and 2 against the checkout Rescan acquired
+func runRescan(scanID string) {
+	defer releaseClone(scanID)
+
+	clone, ok := cachedCloneFor(scanID)
+	if !ok {
+		failScan(scanID, ErrorCategoryInternal, ErrNoCachedTree.Error(), nil)
+		return
+	}
+	mu.Lock()
+	scans[scanID].Status = "scanning"
+	scans[scanID].StartedAt = time.Now()
+	mu.Unlock()
+	notifyCallback(scanID, CallbackStarted)
+
+	log.Printf("🔁 Re-extracting scan %s from cached checkout %s", scanID, clone.Dir)
+	scanCheckout(scanID, clone.Dir)
+}
@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRescanFromCachedTree verifies re-extraction picks up changes in the cached checkout
+func TestRescanFromCachedTree(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(rootDir, "users.py"), []byte(pythonFastAPI), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	scanID := "rescan-test"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+	endpoints[scanID] = []Endpoint{}
+	mu.Unlock()
+
+	scanCheckout(scanID, rootDir)
+	cacheClone(scanID, rootDir, "abc123")
+
+	if eps, _ := GetEndpoints(scanID); len(eps) != 2 {
+		t.Fatalf("initial scan found %d endpoints, want 2", len(eps))
+	}
+
+	// Simulate improved coverage: a new route file appears in the cached tree
+	if err := os.WriteFile(filepath.Join(rootDir, "app.js"), []byte(jsFastify), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ran := make(chan string, 1)
+	prevQueue := scanQueue
+	scanQueue = NewScanQueue(1, func(job ScanJob) {
+		runJob(job)
+		ran <- job.ScanID
+	})
+	t.Cleanup(func() { scanQueue = prevQueue })
+
+	status, err := Rescan(scanID)
+	if err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+	if status.ID != scanID || status.Status != "queued" {
+		t.Errorf("Rescan() status = %+v, want scan %s queued", status, scanID)
+	}
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the rescan")
+	}
+	if status, _ := GetStatus(scanID); status.Status != "completed" || status.Endpoints != 4 {
+		t.Errorf("status = %+v, want completed with 4 endpoints", status)
+	}
+	if eps, _ := GetEndpoints(scanID); len(eps) != 4 {
+		t.Errorf("rescan found %d endpoints, want 4", len(eps))
+	}
+}
+
+// TestRescanWithoutCache verifies rescans fail cleanly without a cached tree
+func TestRescanWithoutCache(t *testing.T) {
+	scanID := "rescan-uncached"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "completed"}
+	mu.Unlock()
+
+	if _, err := Rescan(scanID); !errors.Is(err, ErrNoCachedTree) {
+		t.Errorf("Rescan() error = %v, want ErrNoCachedTree", err)
+	}
+	if _, err := Rescan("does-not-exist"); !errors.Is(err, ErrScanNotFound) {
+		t.Errorf("Rescan() error = %v, want ErrScanNotFound", err)
+	}
+}
+
+// TestRescanInProgress verifies scans still queued or running aren't rescanned
+func TestRescanInProgress(t *testing.T) {
+	scanID := "rescan-running"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning"}
+	mu.Unlock()
+	cacheClone(scanID, t.TempDir(), "abc123")
+
+	if _, err := Rescan(scanID); !errors.Is(err, ErrScanInProgress) {
+		t.Errorf("Rescan() error = %v, want ErrScanInProgress", err)
+	}
+}
+
+// TestCacheEvictionSkipsAcquired verifies a checkout in use by a rescan
+// outlives the cache limit until it is released
+func TestCacheEvictionSkipsAcquired(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.MaxCachedClones = 1
+	cacheMu.Lock()
+	prevCache, prevOrder := cloneCache, cacheOrder
+	cloneCache, cacheOrder = make(map[string]cachedClone), nil
+	cacheMu.Unlock()
+	t.Cleanup(func() {
+		cacheMu.Lock()
+		cloneCache, cacheOrder = prevCache, prevOrder
+		cacheMu.Unlock()
+	})
+
+	inUse, other := t.TempDir(), t.TempDir()
+	cacheClone("evict-in-use", inUse, "a")
+	if _, ok := acquireClone("evict-in-use"); !ok {
+		t.Fatal("acquireClone() found no checkout")
+	}
+	cacheClone("evict-other", other, "b")
+
+	if _, err := os.Stat(inUse); err != nil {
+		t.Errorf("acquired checkout was evicted: %v", err)
+	}
+	releaseClone("evict-in-use")
+	if _, ok := cachedCloneFor("evict-in-use"); ok {
+		t.Error("released checkout beyond the limit is still cached")
+	}
+	if _, ok := cachedCloneFor("evict-other"); !ok {
+		t.Error("newest checkout was evicted")
+	}
+}
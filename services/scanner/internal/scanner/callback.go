@@ -0,0 +1,281 @@
+// Package scanner - Signed status callbacks to the scan requester
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Callback events, selected with Config.CallbackEvents
+const (
+	CallbackStarted   = "started"   // the scan moved to scanning
+	CallbackCompleted = "completed" // the scan finished
+	CallbackFailed    = "failed"    // the scan failed
+)
+
+// DefaultCallbackEvents are delivered when nothing is configured
+var DefaultCallbackEvents = []string{CallbackCompleted, CallbackFailed}
+
+// Callback delivery bounds
+const (
+	callbackAttempts = 3
+	callbackTimeout  = 10 * time.Second
+)
+
+// Callback template bounds
+const (
+	maxCallbackTemplateLen = 4 << 10  // template text
+	maxCallbackBodyBytes   = 64 << 10 // rendered body
+)
+
+// callbackBackoff is the wait before the first redelivery, doubling after
+// each failure (overridable in tests)
+var callbackBackoff = time.Second
+
+// callbackClient delivers callbacks, waiting out 429s like provider calls do
+// and refusing internal addresses
+var callbackClient = &http.Client{
+	Transport: newRateLimitTransport(newCallbackTransport()),
+	Timeout:   callbackTimeout,
+}
+
+// CallbackPayload is the JSON body POSTed to a scan's callback URL
+type CallbackPayload struct {
+	Event string     `json:"event"`
+	Scan  ScanStatus `json:"scan"`
+}
+
+// callbackTemplateFuncs are available to callback templates: {{json .Scan.URL}}
+// writes a value as JSON, quoting and escaping strings
+var callbackTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseCallbackTemplate parses a text/template shaping callback bodies. The
+// template receives a CallbackPayload and must render JSON; it is tried
+// against a sample payload so field typos are rejected at registration.
+// Templates are bounded so one can't exhaust the server: their length, no
+// template/define/block actions, no nested range loops or ranges over
+// numbers, and a rendered body of at most 64 KiB.
+func ParseCallbackTemplate(text string) (*template.Template, error) {
+	if len(text) > maxCallbackTemplateLen {
+		return nil, fmt.Errorf("invalid callback_template: longer than %d bytes", maxCallbackTemplateLen)
+	}
+	tmpl, err := template.New("callback").Funcs(callbackTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid callback_template: %w", err)
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, fmt.Errorf("invalid callback_template: define and block actions aren't allowed")
+	}
+	if tmpl.Tree != nil {
+		if err := checkCallbackNodes(tmpl.Tree.Root, false); err != nil {
+			return nil, fmt.Errorf("invalid callback_template: %w", err)
+		}
+	}
+	now := time.Now()
+	sample := CallbackPayload{
+		Event: CallbackCompleted,
+		Scan: ScanStatus{
+			ID: "sample", Status: "completed", URL: "https://example.com/org/repo.git",
+			StartedAt: now, CompletedAt: &now, Resources: &ScanResources{}, CORS: &CORSPolicy{},
+		},
+	}
+	body, err := renderCallback(sample, tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid callback_template: %w", err)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("invalid callback_template: must render JSON")
+	}
+	return tmpl, nil
+}
+
+// checkCallbackNodes rejects the actions whose cost isn't bounded by the
+// payload: template calls, and range loops nested or over a number
+func checkCallbackNodes(node parse.Node, inRange bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkCallbackNodes(child, inRange); err != nil {
+				return err
+			}
+		}
+	case *parse.TemplateNode:
+		return fmt.Errorf("template actions aren't allowed")
+	case *parse.RangeNode:
+		if inRange {
+			return fmt.Errorf("nested range actions aren't allowed")
+		}
+		for _, cmd := range n.Pipe.Cmds {
+			for _, arg := range cmd.Args {
+				if _, ok := arg.(*parse.NumberNode); ok {
+					return fmt.Errorf("range over a number isn't allowed")
+				}
+			}
+		}
+		if err := checkCallbackNodes(n.List, true); err != nil {
+			return err
+		}
+		return checkCallbackNodes(n.ElseList, inRange)
+	case *parse.IfNode:
+		if err := checkCallbackNodes(n.List, inRange); err != nil {
+			return err
+		}
+		return checkCallbackNodes(n.ElseList, inRange)
+	case *parse.WithNode:
+		if err := checkCallbackNodes(n.List, inRange); err != nil {
+			return err
+		}
+		return checkCallbackNodes(n.ElseList, inRange)
+	}
+	return nil
+}
+
+// renderCallback builds a callback body: the scan's template when it has
+// one, otherwise the full CallbackPayload. Templates stop with an error
+// once the body passes maxCallbackBodyBytes.
+func renderCallback(payload CallbackPayload, tmpl *template.Template) ([]byte, error) {
+	if tmpl == nil {
+		return json.Marshal(payload)
+	}
+	w := &cappedWriter{max: maxCallbackBodyBytes}
+	if err := tmpl.Execute(w, payload); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// errCallbackBodyTooLarge stops a template rendering past the body cap
+var errCallbackBodyTooLarge = fmt.Errorf("callback body exceeds %d bytes", maxCallbackBodyBytes)
+
+// cappedWriter buffers up to max bytes, failing writes beyond
+type cappedWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.max {
+		return 0, errCallbackBodyTooLarge
+	}
+	return w.buf.Write(p)
+}
+
+// ValidateCallbackURL checks a requested callback URL is absolute http(s)
+// and doesn't name localhost or an internal address, unless CALLBACK_ALLOW_HOSTS
+// lists it. Names resolving to internal addresses are refused at delivery.
+func ValidateCallbackURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("callback_url must be an absolute http(s) URL")
+	}
+	host := u.Hostname()
+	if callbackHostAllowed(host, config.CallbackAllowHosts) {
+		return nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	internal := host == "localhost" || strings.HasSuffix(host, ".localhost")
+	if ip, err := netip.ParseAddr(host); err == nil {
+		internal = internalAddr(ip) && !callbackAddrAllowed(ip, config.CallbackAllowHosts)
+	}
+	if internal {
+		return fmt.Errorf("callback_url must not point at a loopback, private or link-local address")
+	}
+	return nil
+}
+
+// notifyCallback sends event for a scan to its callback URL, if it has one
+// and the event is enabled. Delivery happens in the background.
+func notifyCallback(scanID, event string) {
+	if !containsString(config.CallbackEvents, event) {
+		return
+	}
+	mu.RLock()
+	status, exists := scans[scanID]
+	if !exists || status.CallbackURL == "" {
+		mu.RUnlock()
+		return
+	}
+	payload := CallbackPayload{Event: event, Scan: *status}
+	tmpl := status.CallbackTemplate
+	mu.RUnlock()
+
+	body, err := renderCallback(payload, tmpl)
+	if err != nil {
+		// Templates are checked at registration; fall back to the full status
+		log.Printf("⚠️  Callback template failed for scan %s: %v", scanID, err)
+		if body, err = renderCallback(payload, nil); err != nil {
+			return
+		}
+	}
+	go deliverCallback(payload.Scan.CallbackURL, event, body, config.CallbackSecret, config.CallbackAllowHosts)
+}
+
+// signCallback returns the X-Scanner-Signature value for a body
+func signCallback(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverCallback POSTs a callback body, retrying network errors and
+// server errors a bounded number of times. Internal addresses are refused
+// unless allow lists them.
+func deliverCallback(callbackURL, event string, body []byte, secret string, allow []string) error {
+	ctx := context.WithValue(context.Background(), callbackAllowKey{}, allow)
+	var lastErr error
+	wait := callbackBackoff
+	for attempt := 1; attempt <= callbackAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(wait)
+			wait *= 2
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Scanner-Event", event)
+		if secret != "" {
+			req.Header.Set("X-Scanner-Signature", signCallback(body, secret))
+		}
+
+		resp, err := callbackClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("callback returned %s", resp.Status)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			break // the receiver rejected it; retrying won't help
+		}
+	}
+
+	log.Printf("⚠️  Callback %s to %s failed: %v", event, callbackURL, lastErr)
+	return lastErr
+}
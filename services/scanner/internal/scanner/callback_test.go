@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+// receivedCallback is one request seen by a test callback receiver
+type receivedCallback struct {
+	payload   CallbackPayload
+	event     string
+	signature string
+	body      []byte
+}
+
+// newCallbackReceiver records callbacks, answering the first fail responses with 503
+func newCallbackReceiver(t *testing.T, fail int) (*httptest.Server, <-chan receivedCallback) {
+	received := make(chan receivedCallback, 10)
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var payload CallbackPayload
+		json.Unmarshal(body, &payload)
+		received <- receivedCallback{payload, r.Header.Get("X-Scanner-Event"), r.Header.Get("X-Scanner-Signature"), body}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+// TestStartedCallback verifies the started event carries the initial scanning status
+func TestStartedCallback(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.CallbackEvents = []string{CallbackStarted}
+	config.CallbackSecret = "s3cret"
+	config.CallbackAllowHosts = []string{"127.0.0.1"}
+
+	server, received := newCallbackReceiver(t, 0)
+	repoDir, _ := newFixtureRepo(t, map[string]string{"routes/users.py": pythonFastAPI})
+
+	StartScan(ScanJob{ScanID: "callback-started", URL: repoDir, CallbackURL: server.URL})
+
+	select {
+	case got := <-received:
+		if got.event != CallbackStarted || got.payload.Event != CallbackStarted {
+			t.Errorf("event = %q / %q, want started", got.event, got.payload.Event)
+		}
+		if got.payload.Scan.ID != "callback-started" || got.payload.Scan.Status != "scanning" || got.payload.Scan.CompletedAt != nil {
+			t.Errorf("scan = %+v, want the initial scanning status", got.payload.Scan)
+		}
+		if want := signCallback(got.body, "s3cret"); got.signature != want {
+			t.Errorf("signature = %q, want %q", got.signature, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no started callback received")
+	}
+
+	// completed isn't enabled
+	select {
+	case got := <-received:
+		t.Errorf("unexpected %s callback", got.event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestCallbackRetry verifies server errors are retried with the same signed body
+func TestCallbackRetry(t *testing.T) {
+	prevBackoff := callbackBackoff
+	callbackBackoff = time.Millisecond
+	t.Cleanup(func() { callbackBackoff = prevBackoff })
+
+	server, received := newCallbackReceiver(t, 2)
+	if err := deliverCallback(server.URL, CallbackCompleted, []byte(`{"event":"completed"}`), "k", []string{"127.0.0.1"}); err != nil {
+		t.Fatalf("deliverCallback() = %v, want success on the third attempt", err)
+	}
+	if got := <-received; got.signature != signCallback([]byte(`{"event":"completed"}`), "k") {
+		t.Errorf("signature = %q on retry", got.signature)
+	}
+
+	failing, _ := newCallbackReceiver(t, callbackAttempts)
+	if err := deliverCallback(failing.URL, CallbackFailed, []byte(`{}`), "", []string{"127.0.0.0/8"}); err == nil {
+		t.Error("deliverCallback() = nil, want an error after exhausting attempts")
+	}
+}
+
+// TestValidateCallbackURL verifies only absolute http(s) URLs to outside
+// hosts, or allowed internal ones, are accepted
+func TestValidateCallbackURL(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.CallbackAllowHosts = []string{"hooks.internal", "10.1.0.0/16"}
+
+	for url, ok := range map[string]bool{
+		"https://orchestrator.example.com/hooks/scan": true,
+		"http://localhost:9000/cb":                    false,
+		"http://127.0.0.1:9000/cb":                    false,
+		"http://[::1]/cb":                             false,
+		"http://169.254.169.254/latest/meta-data/":    false,
+		"http://192.168.1.10/cb":                      false,
+		"http://hooks.internal/cb":                    true,
+		"http://10.1.2.3/cb":                          true,
+		"http://10.2.0.1/cb":                          false,
+		"ftp://example.com/cb":                        false,
+		"/relative/path":                              false,
+		"not a url":                                   false,
+	} {
+		if err := ValidateCallbackURL(url); (err == nil) != ok {
+			t.Errorf("ValidateCallbackURL(%q) = %v, want ok=%v", url, err, ok)
+		}
+	}
+}
+
+// TestCallbackTemplate verifies a custom template shapes the callback body
+func TestCallbackTemplate(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.CallbackEvents = []string{CallbackCompleted}
+	config.CallbackSecret = "s3cret"
+	config.CallbackAllowHosts = []string{"127.0.0.1"}
+
+	tmpl, err := ParseCallbackTemplate(`{"scan_id": {{json .Scan.ID}}, "state": {{json .Event}}, "endpoints": {{.Scan.Endpoints}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, received := newCallbackReceiver(t, 0)
+	repoDir, _ := newFixtureRepo(t, map[string]string{"routes/users.py": pythonFastAPI})
+	StartScan(ScanJob{ScanID: "callback-template", URL: repoDir, CallbackURL: server.URL, CallbackTemplate: tmpl})
+
+	select {
+	case got := <-received:
+		var body map[string]any
+		if err := json.Unmarshal(got.body, &body); err != nil {
+			t.Fatalf("body %s is not JSON: %v", got.body, err)
+		}
+		want := map[string]any{"scan_id": "callback-template", "state": "completed", "endpoints": float64(2)}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("body = %v, want %v", body, want)
+		}
+		if want := signCallback(got.body, "s3cret"); got.signature != want {
+			t.Errorf("signature = %q, want the rendered body's %q", got.signature, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no completed callback received")
+	}
+}
+
+// TestParseCallbackTemplateRejects verifies bad templates fail at registration
+func TestParseCallbackTemplateRejects(t *testing.T) {
+	for _, text := range []string{
+		`{"id": {{json .Scan.ID}`,                                       // syntax
+		`{"id": {{json .Scan.Nope}}}`,                                   // unknown field
+		`id={{.Scan.ID}}`,                                               // not JSON
+		`{{define "a"}}{{.}}{{.}}{{end}}{"id": {{json .Scan.ID}}}`,      // define
+		`{"id": {{template "callback" .}}}`,                             // template call
+		`{"n": [{{range .Scan.Branches}}{{range .}}1,{{end}}{{end}}0]}`, // nested range
+		`{"n": [{{range 1000000000}}{{end}}0]}`,                         // range over a number
+		`{"n": "` + strings.Repeat("x", maxCallbackTemplateLen) + `"}`,  // too long
+	} {
+		if _, err := ParseCallbackTemplate(text); err == nil {
+			t.Errorf("ParseCallbackTemplate(%q) succeeded", text)
+		}
+	}
+}
+
+// TestRenderCallbackCapped verifies a template rendering past the body cap
+// fails rather than buffering it
+func TestRenderCallbackCapped(t *testing.T) {
+	tmpl, err := template.New("callback").Parse(`{{range .Scan.Branches}}{{.}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := CallbackPayload{Scan: ScanStatus{Branches: make([]string, maxCallbackBodyBytes/1024+1)}}
+	for i := range payload.Scan.Branches {
+		payload.Scan.Branches[i] = strings.Repeat("b", 1024)
+	}
+	if _, err := renderCallback(payload, tmpl); !errors.Is(err, errCallbackBodyTooLarge) {
+		t.Errorf("renderCallback() = %v, want %v", err, errCallbackBodyTooLarge)
+	}
+}
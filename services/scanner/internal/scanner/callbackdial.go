@@ -0,0 +1,98 @@
+// Package scanner - Keeping scan callbacks off internal networks
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// callbackAllowKey carries a delivery's CALLBACK_ALLOW_HOSTS to the dialer
+type callbackAllowKey struct{}
+
+// callbackDialer connects to callback receivers
+var callbackDialer = &net.Dialer{Timeout: callbackTimeout, KeepAlive: 30 * time.Second}
+
+// newCallbackTransport dials callback receivers through dialCallback. No
+// proxy is used: through one, the receiver's address couldn't be checked.
+func newCallbackTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialCallback
+	return t
+}
+
+// dialCallback refuses loopback, private and link-local addresses, cloud
+// metadata at 169.254.169.254 included. The check runs on the address
+// actually connected to, so a host re-resolving to one after validation is
+// refused too. Hosts and ranges on the request's allowlist are exempt.
+func dialCallback(ctx context.Context, network, addr string) (net.Conn, error) {
+	allow, _ := ctx.Value(callbackAllowKey{}).([]string)
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if callbackHostAllowed(host, allow) {
+		return callbackDialer.DialContext(ctx, network, addr)
+	}
+
+	d := *callbackDialer
+	d.Control = func(_, address string, _ syscall.RawConn) error {
+		return checkCallbackAddr(address, allow)
+	}
+	return d.DialContext(ctx, network, addr)
+}
+
+// checkCallbackAddr fails for an internal ip:port not on the allowlist
+func checkCallbackAddr(address string, allow []string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("callback to %s refused: not an IP address", host)
+	}
+	if internalAddr(ip) && !callbackAddrAllowed(ip, allow) {
+		return fmt.Errorf("callback to %s refused: loopback, private or link-local address", ip)
+	}
+	return nil
+}
+
+// internalAddr reports whether ip is loopback, private (RFC 1918, fc00::/7),
+// link-local or unspecified
+func internalAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+// callbackHostAllowed reports whether a host name is on the allowlist
+func callbackHostAllowed(host string, allow []string) bool {
+	for _, entry := range allow {
+		if strings.EqualFold(entry, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// callbackAddrAllowed reports whether ip is on the allowlist, as an address
+// or within a CIDR range
+func callbackAddrAllowed(ip netip.Addr, allow []string) bool {
+	ip = ip.Unmap()
+	for _, entry := range allow {
+		if prefix, err := netip.ParsePrefix(entry); err == nil && prefix.Contains(ip) {
+			return true
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil && addr.Unmap() == ip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCallbackRefusesInternalAddresses verifies deliveries to internal
+// addresses fail at connect time unless the host or range is allowed
+func TestCallbackRefusesInternalAddresses(t *testing.T) {
+	prevBackoff := callbackBackoff
+	callbackBackoff = time.Millisecond
+	t.Cleanup(func() { callbackBackoff = prevBackoff })
+
+	server, received := newCallbackReceiver(t, 0)
+	err := deliverCallback(server.URL, CallbackCompleted, []byte(`{}`), "", nil)
+	if err == nil || !strings.Contains(err.Error(), "refused") {
+		t.Fatalf("deliverCallback() to %s = %v, want the connection refused", server.URL, err)
+	}
+	select {
+	case <-received:
+		t.Fatal("callback reached a loopback receiver")
+	default:
+	}
+
+	// localhost resolves to loopback: refused by address, allowed by name
+	local := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	if err := deliverCallback(local, CallbackCompleted, []byte(`{}`), "", []string{"10.0.0.0/8"}); err == nil {
+		t.Error("deliverCallback() to localhost = nil, want refused")
+	}
+	if err := deliverCallback(local, CallbackCompleted, []byte(`{}`), "", []string{"localhost"}); err != nil {
+		t.Errorf("deliverCallback() to allowed localhost = %v", err)
+	}
+}
+
+// TestCheckCallbackAddr verifies which connected addresses are refused
+func TestCheckCallbackAddr(t *testing.T) {
+	allow := []string{"10.20.0.0/16", "fd00::1"}
+	for address, ok := range map[string]bool{
+		"93.184.216.34:443":     true,
+		"[2606:4700::1111]:443": true,
+		"127.0.0.1:80":          false,
+		"169.254.169.254:80":    false,
+		"172.16.5.4:80":         false,
+		"[::1]:80":              false,
+		"[fe80::1]:80":          false,
+		"[::ffff:10.0.0.1]:80":  false,
+		"0.0.0.0:80":            false,
+		"10.20.3.4:80":          true,
+		"[fd00::1]:80":          true,
+	} {
+		if err := checkCallbackAddr(address, allow); (err == nil) != ok {
+			t.Errorf("checkCallbackAddr(%q) = %v, want ok=%v", address, err, ok)
+		}
+	}
+}
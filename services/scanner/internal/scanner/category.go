@@ -0,0 +1,41 @@
+// Package scanner - Infra vs business endpoint classification
+package scanner
+
+import (
+	"strings"
+)
+
+// Endpoint categories
+const (
+	CategoryInfra    = "infra"    // health checks, metrics and other plumbing
+	CategoryBusiness = "business" // everything else
+)
+
+// DefaultInfraPaths are the paths treated as infrastructure when nothing is
+// configured. Each entry also covers the paths beneath it.
+var DefaultInfraPaths = []string{
+	"/health", "/healthz", "/livez", "/readyz", "/ready", "/live", "/ping",
+	"/metrics", "/favicon.ico", "/robots.txt", "/debug/pprof",
+}
+
+// classifyEndpoint returns the category of a route path
+func classifyEndpoint(path string, infraPaths []string) string {
+	p := strings.ToLower(strings.TrimSuffix(path, "/"))
+	for _, infra := range infraPaths {
+		infra = strings.ToLower(strings.TrimSuffix(infra, "/"))
+		if infra == "" {
+			continue
+		}
+		if p == infra || strings.HasPrefix(p, infra+"/") {
+			return CategoryInfra
+		}
+	}
+	return CategoryBusiness
+}
+
+// classifyEndpoints sets the category of each endpoint
+func classifyEndpoints(found []Endpoint, infraPaths []string) {
+	for i := range found {
+		found[i].Category = classifyEndpoint(found[i].Path, infraPaths)
+	}
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"testing"
+)
+
+// TestClassifyEndpoint verifies infra paths are separated from business routes
+func TestClassifyEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/health", CategoryInfra},
+		{"/health/", CategoryInfra},
+		{"/health/live", CategoryInfra},
+		{"/metrics", CategoryInfra},
+		{"/favicon.ico", CategoryInfra},
+		{"/users", CategoryBusiness},
+		{"/healthcare/patients", CategoryBusiness},
+		{"/api/v1/orders", CategoryBusiness},
+	}
+
+	for _, tt := range tests {
+		if got := classifyEndpoint(tt.path, DefaultInfraPaths); got != tt.want {
+			t.Errorf("classifyEndpoint(%q) = %q; want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestClassifyEndpointConfigured verifies the infra list comes from config
+func TestClassifyEndpointConfigured(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	config.InfraPaths = parseCommaList(" /internal , /status,")
+	found := ScanFile("app/routes.py", `@app.get("/internal/stats")
+def stats():
+    pass
+
+@app.get("/health")
+def health():
+    pass
+
+@app.get("/users")
+def users():
+    pass
+`)
+	if len(found) != 3 {
+		t.Fatalf("found %d endpoints; want 3", len(found))
+	}
+	want := []string{CategoryInfra, CategoryBusiness, CategoryBusiness}
+	for i, ep := range found {
+		if ep.Category != want[i] {
+			t.Errorf("%s category = %q; want %q", ep.Path, ep.Category, want[i])
+		}
+	}
+}
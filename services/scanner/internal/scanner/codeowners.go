@@ -0,0 +1,111 @@
+// Package scanner - Endpoint ownership from CODEOWNERS
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeownersLocations are where CODEOWNERS is looked up, in GitHub's order
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// codeownersRule is one CODEOWNERS line: a path pattern and its owners
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// codeowners is a parsed CODEOWNERS file. The last matching rule wins.
+type codeowners []codeownersRule
+
+// loadCodeowners reads the repository's CODEOWNERS, or returns nil if it has none
+func loadCodeowners(rootDir string) codeowners {
+	for _, loc := range codeownersLocations {
+		content, err := os.ReadFile(filepath.Join(rootDir, filepath.FromSlash(loc)))
+		if err == nil {
+			return parseCodeowners(string(content))
+		}
+	}
+	return nil
+}
+
+// parseCodeowners parses CODEOWNERS lines: "pattern @owner @org/team email".
+// Comments, blank lines and GitLab [Section] headers are skipped.
+func parseCodeowners(content string) codeowners {
+	var rules codeowners
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(strings.ReplaceAll(line, `\ `, "\x00"))
+		pattern := strings.ReplaceAll(fields[0], "\x00", " ")
+		pattern = strings.TrimPrefix(pattern, `\`) // \#file escapes a leading #
+		re, err := regexp.Compile(codeownersRegexp(pattern))
+		if err != nil {
+			continue
+		}
+		rule := codeownersRule{pattern: re}
+		if len(fields) > 1 {
+			rule.owners = fields[1:]
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// codeownersRegexp translates a CODEOWNERS (gitignore-style) pattern.
+// Patterns with a slash before their end are anchored at the repository
+// root; others match at any depth. A match on a directory covers all files
+// below it, except for dir/* which only covers the directory's own files.
+func codeownersRegexp(pattern string) string {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	shallow := strings.HasSuffix(pattern, "/*")
+	pattern = strings.Trim(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if shallow {
+		b.WriteString("$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return b.String()
+}
+
+// ownersOf returns the owners of a repository-relative path: those of the
+// last matching rule, which may be none
+func (c codeowners) ownersOf(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i].pattern.MatchString(path) {
+			return c[i].owners
+		}
+	}
+	return nil
+}
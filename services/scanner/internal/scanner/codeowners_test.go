@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestCodeownersMatching verifies gitignore-style patterns and last-match-wins
+func TestCodeownersMatching(t *testing.T) {
+	rules := parseCodeowners(`# Default owners
+*                       @org/platform
+*.py                    @org/python
+/services/billing/      @org/billing @alice
+docs/*                  docs@example.com
+**/internal             @org/security
+services/billing/legacy/
+`)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@org/platform"}},
+		{"api/users.py", []string{"@org/python"}},
+		{"services/billing/invoices.py", []string{"@org/billing", "@alice"}},
+		{"other/services/billing/x.go", []string{"@org/platform"}}, // anchored
+		{"docs/api.md", []string{"docs@example.com"}},
+		{"docs/guides/setup.md", []string{"@org/platform"}}, // dir/* is shallow
+		{"pkg/internal/auth.go", []string{"@org/security"}},
+		{"services/billing/legacy/old.py", nil}, // last rule has no owners
+	}
+	for _, tt := range tests {
+		if got := rules.ownersOf(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ownersOf(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestEndpointOwners verifies scanned endpoints carry their file's owners
+func TestEndpointOwners(t *testing.T) {
+	repoDir, _ := newFixtureRepo(t, map[string]string{
+		".github/CODEOWNERS": "* @org/platform\n/routes/ @org/api-team\n/routes/billing.py @org/billing\n",
+		"routes/users.py":    pythonFastAPI,
+		"routes/billing.py":  "from fastapi import FastAPI\napp = FastAPI()\n\n@app.post(\"/invoices\")\ndef create_invoice():\n    return {}\n",
+		"server.js":          "const express = require('express')\nconst app = express()\napp.get('/health', (req, res) => res.send('ok'))\n",
+	})
+
+	scanID := "codeowners-scan"
+	StartScan(ScanJob{ScanID: scanID, URL: repoDir})
+	eps, err := GetEndpoints(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"routes/users.py":   {"@org/api-team"},
+		"routes/billing.py": {"@org/billing"},
+		"server.js":         {"@org/platform"},
+	}
+	if len(eps) == 0 {
+		t.Fatal("no endpoints found")
+	}
+	for _, ep := range eps {
+		if !reflect.DeepEqual(ep.Owners, want[ep.FilePath]) {
+			t.Errorf("%s %s owners = %v, want %v", ep.FilePath, ep.Path, ep.Owners, want[ep.FilePath])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package scanner - Routes declared inside comments
+package scanner
+
+import "strings"
+
+// commentSyntax describes how a language writes comments and strings.
+// Comment markers inside strings don't start comments.
+type commentSyntax struct {
+	line       []string // line comment markers
+	blockStart string   // block comment delimiters
+	blockEnd   string
+	docStrings bool   // triple-quoted strings count as comments (Python docstrings, Elixir heredocs)
+	quotes     string // string delimiters; backtick strings may span lines
+}
+
+var cStyleComments = commentSyntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`"}
+
+// commentSyntaxes by file extension
+var commentSyntaxes = map[string]commentSyntax{
+	".go": cStyleComments, ".java": cStyleComments, ".cs": cStyleComments,
+	".js": cStyleComments, ".ts": cStyleComments, ".jsx": cStyleComments, ".tsx": cStyleComments,
+	".vue": cStyleComments, ".svelte": cStyleComments,
+	".php": {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'"},
+	".py":  {line: []string{"#"}, docStrings: true, quotes: "\"'"},
+	".ex":  {line: []string{"#"}, docStrings: true, quotes: "\"'"},
+	".exs": {line: []string{"#"}, docStrings: true, quotes: "\"'"},
+}
+
+// commentedLines reports which lines hold nothing but comments, such as a
+// route commented out with // or left inside /* ... */. It returns nil for
+// languages without a known comment syntax.
+func commentedLines(ext string, lines []string) []bool {
+	syntax, ok := commentSyntaxes[ext]
+	if !ok {
+		return nil
+	}
+	commented := make([]bool, len(lines))
+	closing, inComment := "", false // the open block comment or string, and which
+	for i, line := range lines {
+		code, comment := false, false
+		for j := 0; j < len(line); {
+			rest := line[j:]
+			if closing != "" {
+				if inComment {
+					comment = true
+				} else {
+					code = true
+				}
+				switch {
+				case !inComment && rest[0] == '\\':
+					j += 2
+				case strings.HasPrefix(rest, closing):
+					j += len(closing)
+					closing = ""
+				default:
+					j++
+				}
+				continue
+			}
+			if rest[0] == ' ' || rest[0] == '\t' {
+				j++
+				continue
+			}
+			if lineComment(syntax, rest) {
+				comment = true
+				break
+			}
+			switch {
+			case syntax.blockStart != "" && strings.HasPrefix(rest, syntax.blockStart):
+				closing, inComment = syntax.blockEnd, true
+			case syntax.docStrings && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, `'''`)):
+				closing, inComment = rest[:3], true
+			case strings.IndexByte(syntax.quotes, rest[0]) >= 0:
+				closing, inComment = rest[:1], false
+				code = true
+			default:
+				code = true
+			}
+			j += max(len(closing), 1)
+		}
+		// Only backtick strings span lines
+		if !inComment && closing != "`" {
+			closing = ""
+		}
+		commented[i] = comment && !code
+	}
+	return commented
+}
+
+// lineComment reports whether s starts with a line comment marker. #[ opens
+// a PHP attribute such as #[Route('/users')], not a comment.
+func lineComment(syntax commentSyntax, s string) bool {
+	for _, marker := range syntax.line {
+		if strings.HasPrefix(s, marker) && !strings.HasPrefix(s, "#[") {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeCommentedRoutes drops routes declared on commented-out lines, or
+// flags them Commented when REPORT_COMMENTED_ROUTES is on
+func excludeCommentedRoutes(ext string, found []Endpoint, lines []string) []Endpoint {
+	commented := commentedLines(ext, lines)
+	if commented == nil {
+		return found
+	}
+	kept := found[:0]
+	for _, ep := range found {
+		if n := ep.LineNumber; n >= 1 && n <= len(commented) && commented[n-1] {
+			if !config.ReportCommented {
+				continue
+			}
+			ep.Commented = true
+		}
+		kept = append(kept, ep)
+	}
+	return kept
+}
@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestCommentedRoutes verifies routes in line and block comments are left
+// out, while comment markers inside strings don't hide a route
+func TestCommentedRoutes(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		want                []string
+	}{
+		{"Express", "routes/users.js", `const express = require('express');
+const router = express.Router();
+
+router.get('/users', listUsers);
+// router.get('/legacy', legacyUsers);
+/* router.post('/old', createOld);
+router.delete('/old/:id', deleteOld); */
+router.get('/proxy', (req, res) => fetchFrom("http://upstream/*")); // was: router.get('/v0')
+/**
+ * router.put('/draft', updateDraft);
+ */
+router.post('/users', createUser);
+`, []string{"GET /proxy", "GET /users", "POST /users"}},
+		{"Flask", "app/views.py", `from flask import Flask
+app = Flask(__name__)
+
+@app.route("/users")
+def users():
+    """List users.
+
+    @app.route("/documented")
+    """
+    return []
+
+# @app.route("/retired")
+# def retired():
+#     return []
+
+@app.route("/hash#tag")
+def tagged():
+    return []
+`, []string{"GET /hash#tag", "GET /users"}},
+		{"Gin", "cmd/main.go", `package main
+
+import "github.com/gin-gonic/gin"
+
+func main() {
+	r := gin.Default()
+	r.GET("/users", listUsers)
+	// r.GET("/legacy", legacyUsers)
+	/*
+		r.POST("/old", createOld)
+	*/
+	r.Run()
+}
+`, []string{"GET /users"}},
+		{"Gin fallback", "cmd/broken.go", `r.GET("/users", listUsers)
+// r.GET("/legacy", legacyUsers)
+/* r.POST("/old", createOld) */
+`, []string{"GET /users"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ep := range ScanFile(tt.file, tt.content) {
+				got = append(got, ep.Method+" "+ep.Path)
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ScanFile() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ScanFile() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestReportCommentedRoutes verifies commented routes are kept and flagged
+// when reporting is on
+func TestReportCommentedRoutes(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.ReportCommented = true
+
+	content := `router.get('/users', listUsers);
+// router.get('/legacy', legacyUsers);
+`
+	endpoints := ScanFile("routes/users.js", content)
+	if len(endpoints) != 2 {
+		t.Fatalf("ScanFile() found %d endpoints, want 2: %+v", len(endpoints), endpoints)
+	}
+	if endpoints[0].Commented || !endpoints[1].Commented {
+		t.Errorf("commented = %v, %v, want false, true", endpoints[0].Commented, endpoints[1].Commented)
+	}
+}
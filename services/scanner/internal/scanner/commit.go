@@ -0,0 +1,57 @@
+// Package scanner - Pinning scans to a specific commit
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrCommitNotFound is returned when a requested commit isn't in the repository
+var ErrCommitNotFound = errors.New("commit not found in repository")
+
+// checkoutCommit checks out sha (full or abbreviated) in a clone, fetching it
+// from origin when no cloned branch contains it
+func checkoutCommit(dir, sha, token string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(sha))
+	if err != nil {
+		// Commits only reachable from other refs (e.g. pull requests) can
+		// still be fetched by full SHA when the host allows it
+		if !plumbing.IsHash(sha) {
+			return fmt.Errorf("%w: %s", ErrCommitNotFound, sha)
+		}
+		log.Printf("📥 Commit %s not in clone, fetching it", sha)
+		fetchOptions := &git.FetchOptions{
+			RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec(sha + ":refs/scanner/pinned")},
+		}
+		if origin, err := repo.Remote(git.DefaultRemoteName); err == nil && len(origin.Config().URLs) > 0 {
+			if fetchOptions.Auth, err = cloneAuth(origin.Config().URLs[0], token); err != nil {
+				return fmt.Errorf("%w: %s (%v)", ErrCommitNotFound, sha, err)
+			}
+		}
+		if err := repo.Fetch(fetchOptions); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return fmt.Errorf("%w: %s (fetch failed: %v)", ErrCommitNotFound, sha, err)
+		}
+		if hash, err = repo.ResolveRevision(plumbing.Revision(sha)); err != nil {
+			return fmt.Errorf("%w: %s", ErrCommitNotFound, sha)
+		}
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return fmt.Errorf("failed to check out commit %s: %w", sha, err)
+	}
+	return nil
+}
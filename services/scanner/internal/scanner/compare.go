@@ -0,0 +1,187 @@
+// Package scanner - Comparing the endpoints of two repositories or branches
+package scanner
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Comparison pairs two scans submitted together so their endpoints can be
+// diffed once both complete, such as a fork against its upstream
+type Comparison struct {
+	ID         string    `json:"id"`
+	BaseScanID string    `json:"base_scan_id"`
+	HeadScanID string    `json:"head_scan_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ComparisonStatus is a comparison with the progress of its scans and,
+// once both have completed, the diff from base to head
+type ComparisonStatus struct {
+	Comparison
+	Status string        `json:"status"` // scanning, completed or failed
+	Error  string        `json:"error,omitempty"`
+	Base   *ScanStatus   `json:"base"`
+	Head   *ScanStatus   `json:"head"`
+	Diff   *EndpointDiff `json:"diff,omitempty"`
+}
+
+// EndpointDiff lists the endpoints head adds, removes and changes relative
+// to base, matched on method and normalized path
+type EndpointDiff struct {
+	Added   []Endpoint       `json:"added"`
+	Removed []Endpoint       `json:"removed"`
+	Changed []EndpointChange `json:"changed"`
+}
+
+// EndpointChange is a route present on both sides whose contract differs
+type EndpointChange struct {
+	Method string   `json:"method"`
+	Path   string   `json:"path"`
+	Fields []string `json:"fields"` // differing attributes, by their JSON names
+	Base   Endpoint `json:"base"`
+	Head   Endpoint `json:"head"`
+}
+
+// comparedFields are the attributes describing a route's contract. Where it
+// is declared (file, line, ID) doesn't count as a change.
+var comparedFields = []struct {
+	name  string
+	value func(Endpoint) any
+}{
+	{"summary", func(ep Endpoint) any { return ep.Summary }},
+	{"path_params", func(ep Endpoint) any { return ep.PathParams }},
+	{"query_params", func(ep Endpoint) any { return ep.QueryParams }},
+	{"response_type", func(ep Endpoint) any { return ep.ResponseType }},
+	{"response_codes", func(ep Endpoint) any { return ep.ResponseCodes }},
+	{"response_headers", func(ep Endpoint) any { return ep.ResponseHeaders }},
+	{"input_model", func(ep Endpoint) any { return ep.InputModel }},
+	{"required_scopes", func(ep Endpoint) any { return ep.RequiredScopes }},
+	{"auth", func(ep Endpoint) any { return ep.Auth }},
+	{"deprecated", func(ep Endpoint) any { return ep.Deprecated }},
+}
+
+var comparisons = make(map[string]*Comparison)
+
+// ErrComparisonNotFound is returned when no comparison exists for an ID
+var ErrComparisonNotFound = errors.New("comparison not found")
+
+// EnqueueComparison records a comparison of two scans and queues both, so
+// they share the worker pool and per-host limits with every other scan
+func EnqueueComparison(compareID string, base, head ScanJob) *Comparison {
+	Enqueue(base)
+	Enqueue(head)
+
+	cmp := &Comparison{ID: compareID, BaseScanID: base.ScanID, HeadScanID: head.ScanID, CreatedAt: time.Now()}
+	mu.Lock()
+	comparisons[compareID] = cmp
+	mu.Unlock()
+	return cmp
+}
+
+// GetComparison returns a comparison's progress, with the diff once both
+// scans have completed. It fails when either scan does.
+func GetComparison(compareID string) (*ComparisonStatus, error) {
+	mu.RLock()
+	cmp, exists := comparisons[compareID]
+	if !exists {
+		mu.RUnlock()
+		return nil, ErrComparisonNotFound
+	}
+	status := &ComparisonStatus{Comparison: *cmp, Status: "scanning"}
+	base, head := scans[cmp.BaseScanID], scans[cmp.HeadScanID]
+	if base != nil {
+		copied := *base
+		status.Base = &copied
+	}
+	if head != nil {
+		copied := *head
+		status.Head = &copied
+	}
+	baseEps, headEps := endpoints[cmp.BaseScanID], endpoints[cmp.HeadScanID]
+	mu.RUnlock()
+
+	switch {
+	case status.Base == nil || status.Head == nil:
+		status.Status = "failed"
+		status.Error = "scan data was purged"
+	case status.Base.Status == "failed":
+		status.Status = "failed"
+		status.Error = "base scan failed: " + status.Base.Error
+	case status.Head.Status == "failed":
+		status.Status = "failed"
+		status.Error = "head scan failed: " + status.Head.Error
+	case status.Base.Status == "completed" && status.Head.Status == "completed":
+		status.Status = "completed"
+		status.Diff = DiffEndpoints(baseEps, headEps)
+	}
+	return status, nil
+}
+
+// DiffEndpoints compares two scans' endpoints keyed on method and normalized
+// path, so renamed parameters ({id} vs :userId) still match. Client calls are
+// left out; the first declaration of a duplicated route is compared.
+func DiffEndpoints(base, head []Endpoint) *EndpointDiff {
+	diff := &EndpointDiff{Added: []Endpoint{}, Removed: []Endpoint{}, Changed: []EndpointChange{}}
+	baseRoutes := routesByKey(base)
+	headRoutes := routesByKey(head)
+
+	for key, h := range headRoutes {
+		b, ok := baseRoutes[key]
+		if !ok {
+			diff.Added = append(diff.Added, h)
+			continue
+		}
+		var fields []string
+		for _, field := range comparedFields {
+			if !reflect.DeepEqual(field.value(b), field.value(h)) {
+				fields = append(fields, field.name)
+			}
+		}
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, EndpointChange{Method: h.Method, Path: h.Path, Fields: fields, Base: b, Head: h})
+		}
+	}
+	for key, b := range baseRoutes {
+		if _, ok := headRoutes[key]; !ok {
+			diff.Removed = append(diff.Removed, b)
+		}
+	}
+
+	byRoute := func(eps []Endpoint) func(i, j int) bool {
+		return func(i, j int) bool {
+			if eps[i].Path != eps[j].Path {
+				return eps[i].Path < eps[j].Path
+			}
+			return eps[i].Method < eps[j].Method
+		}
+	}
+	sort.Slice(diff.Added, byRoute(diff.Added))
+	sort.Slice(diff.Removed, byRoute(diff.Removed))
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		a, b := diff.Changed[i], diff.Changed[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return diff
+}
+
+// routesByKey indexes served routes by method and normalized path
+func routesByKey(eps []Endpoint) map[string]Endpoint {
+	routes := make(map[string]Endpoint, len(eps))
+	for _, ep := range eps {
+		if ep.Kind == KindClientCall {
+			continue
+		}
+		key := strings.ToUpper(ep.Method) + " " + canonicalPath("/"+strings.TrimPrefix(ep.Path, "/"))
+		if _, seen := routes[key]; !seen {
+			routes[key] = ep
+		}
+	}
+	return routes
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCompareRepositories verifies two fixture repositories are scanned
+// through the queue and diffed into added, removed and changed endpoints
+func TestCompareRepositories(t *testing.T) {
+	prevQueue := scanQueue
+	scanQueue = NewScanQueue(2, StartScan)
+	t.Cleanup(func() { scanQueue = prevQueue })
+
+	upstream, _ := newFixtureRepo(t, map[string]string{"app.py": `from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/users")
+def list_users():
+    return []
+
+@app.get("/users/{user_id}")
+def get_user(user_id: int):
+    return {}
+
+@app.delete("/users/{user_id}")
+def delete_user(user_id: int):
+    return {}
+`})
+	fork, _ := newFixtureRepo(t, map[string]string{"app.py": `from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/users")
+def list_users():
+    """List active users"""
+    return []
+
+@app.get("/users/{id}")
+def get_user(id: int):
+    return {}
+
+@app.post("/users")
+def create_user():
+    return {}
+`})
+
+	EnqueueComparison("compare-fork", ScanJob{ScanID: "compare-upstream", URL: upstream}, ScanJob{ScanID: "compare-fork-head", URL: fork})
+
+	var status *ComparisonStatus
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var err error
+		if status, err = GetComparison("compare-fork"); err != nil {
+			t.Fatal(err)
+		}
+		if status.Status != "scanning" || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if status.Status != "completed" {
+		t.Fatalf("comparison status = %s (%s), want completed", status.Status, status.Error)
+	}
+
+	diff := status.Diff
+	if len(diff.Added) != 1 || diff.Added[0].Method != "POST" || diff.Added[0].Path != "/users" {
+		t.Errorf("Added = %+v, want POST /users", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Method != "DELETE" || diff.Removed[0].Path != "/users/{user_id}" {
+		t.Errorf("Removed = %+v, want DELETE /users/{user_id}", diff.Removed)
+	}
+	// The renamed path parameter still matches; only the new summary is a change
+	if len(diff.Changed) != 1 || diff.Changed[0].Path != "/users" || !containsString(diff.Changed[0].Fields, "summary") {
+		t.Errorf("Changed = %+v, want GET /users with a new summary", diff.Changed)
+	}
+
+	if _, err := GetComparison("missing"); err != ErrComparisonNotFound {
+		t.Errorf("GetComparison(missing) error = %v, want ErrComparisonNotFound", err)
+	}
+}
@@ -0,0 +1,167 @@
+// Package scanner - Detection of routes registered inside conditional blocks
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Lines that open a conditional block
+var (
+	braceConditionalPattern  = regexp.MustCompile(`^\s*(?:\}\s*)?(?:if\b|else\b|switch\b|case\b)`)
+	pythonConditionalPattern = regexp.MustCompile(`^\s*(?:if|elif|else)\b.*:\s*(?:#.*)?$`)
+)
+
+// envConditionPattern matches conditions on the runtime environment or a
+// feature flag: process.env.NODE_ENV !== 'production', settings.DEBUG,
+// env.IsDevelopment(), gin.Mode(), flags.isEnabled("beta")
+var envConditionPattern = regexp.MustCompile(`(?i)\b(?:NODE_ENV|APP_ENV|FLASK_ENV|RAILS_ENV|ENVIRONMENT|DEBUG)\b|` +
+	`process\.env\b|\bos\.(?:environ|getenv|Getenv)\b|\bgetenv\s*\(|Rails\.env\b|App::environment\b|\bgin\.Mode\s*\(|__DEV__|` +
+	`\.Is(?:Development|Production|Staging|Environment)\s*\(|\bis_?(?:dev|development|prod|production|staging|local)\b|` +
+	`\bfeature_?(?:flags?|enabled)\b|\b(?:is)?feature_?enabled\b|\bflags?\.\w+|\bunleash\b|\bvariation\s*\(`)
+
+// markEnvironmentRoutes flags routes registered under an environment or
+// feature flag condition
+func markEnvironmentRoutes(ext string, found []Endpoint, lines []string) {
+	for i := range found {
+		idx := found[i].LineNumber - 1
+		if idx < 0 || idx >= len(lines) {
+			continue
+		}
+		if cond, ok := environmentCondition(ext, lines, idx); ok {
+			found[i].EnvRestricted = true
+			found[i].EnvCondition = cond
+		}
+	}
+}
+
+// environmentCondition returns the innermost enclosing condition that checks
+// the environment or a feature flag. Unlike enclosingCondition, every
+// enclosing block is considered: a route inside a function that is only
+// defined in development is still environment-specific.
+func environmentCondition(ext string, lines []string, idx int) (string, bool) {
+	// A single-line guard: if (isDev) app.get(...)
+	if line := lines[idx]; strings.HasPrefix(strings.TrimSpace(line), "if") && envConditionPattern.MatchString(line) {
+		return conditionText(line), true
+	}
+	for _, line := range enclosingBlocks(ext, lines, idx) {
+		if (braceConditionalPattern.MatchString(line) || pythonConditionalPattern.MatchString(line)) && envConditionPattern.MatchString(line) {
+			return conditionText(line), true
+		}
+	}
+	return "", false
+}
+
+// enclosingBlocks returns the lines opening each block around lines[idx],
+// innermost first
+func enclosingBlocks(ext string, lines []string, idx int) []string {
+	var blocks []string
+	if ext == ".py" {
+		indent := indentation(lines[idx])
+		for i := idx - 1; i >= 0 && indent > 0; i-- {
+			code := strings.TrimSpace(lines[i])
+			if code == "" || strings.HasPrefix(code, "#") {
+				continue
+			}
+			if n := indentation(lines[i]); n < indent {
+				blocks = append(blocks, lines[i])
+				indent = n
+			}
+		}
+		return blocks
+	}
+
+	depth := 0
+	for i := idx - 1; i >= 0; i-- {
+		line := lines[i]
+		for j := len(line) - 1; j >= 0; j-- {
+			switch line[j] {
+			case '}':
+				depth++
+			case '{':
+				if depth == 0 {
+					blocks = append(blocks, line)
+				} else {
+					depth--
+				}
+			}
+		}
+	}
+	return blocks
+}
+
+// conditionText trims a block-opening line to its condition
+func conditionText(line string) string {
+	return strings.TrimRight(strings.TrimSpace(line), " {:")
+}
+
+// enclosingCondition returns the conditional line directly enclosing lines[idx].
+// Only the innermost block is considered, so a route inside a function that
+// is itself wrapped in an if is not flagged.
+func enclosingCondition(ext string, lines []string, idx int) (string, bool) {
+	if ext == ".py" {
+		return pythonEnclosingCondition(lines, idx)
+	}
+	return braceEnclosingCondition(lines, idx)
+}
+
+// braceEnclosingCondition walks backwards to the first unmatched '{'
+func braceEnclosingCondition(lines []string, idx int) (string, bool) {
+	depth := 0
+	for i := idx - 1; i >= 0; i-- {
+		line := lines[i]
+		for j := len(line) - 1; j >= 0; j-- {
+			switch line[j] {
+			case '}':
+				depth++
+			case '{':
+				if depth == 0 {
+					if braceConditionalPattern.MatchString(line) {
+						return strings.TrimSpace(line), true
+					}
+					return "", false
+				}
+				depth--
+			}
+		}
+	}
+	return "", false
+}
+
+// pythonEnclosingCondition finds the nearest less-indented line
+func pythonEnclosingCondition(lines []string, idx int) (string, bool) {
+	indent := indentation(lines[idx])
+	if indent == 0 {
+		return "", false
+	}
+
+	for i := idx - 1; i >= 0; i-- {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if indentation(line) < indent {
+			if pythonConditionalPattern.MatchString(line) {
+				return strings.TrimSpace(line), true
+			}
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// indentation counts leading whitespace, treating tabs as four spaces
+func indentation(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
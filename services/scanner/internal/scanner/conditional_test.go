@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const goConditionalRoutes = `package main
+
+import "github.com/gin-gonic/gin"
+
+func setup(r *gin.Engine, cfg Config) {
+	r.GET("/users", listUsers)
+
+	if cfg.FeatureEnabled("beta-search") {
+		r.GET("/search", search)
+	} else {
+		r.GET("/search/legacy", legacySearch)
+	}
+
+	r.POST("/orders", func(c *gin.Context) {
+		if c.Query("dry") != "" {
+			return
+		}
+	})
+}
+`
+
+// TestConditionalRoutes verifies routes registered inside conditional blocks are flagged
+func TestConditionalRoutes(t *testing.T) {
+	jsConditional := `const router = express.Router();
+
+router.get('/users', listUsers);
+
+if (flags.isEnabled('exports')) {
+    router.post('/exports', createExport);
+}
+`
+	pyConditional := `from fastapi import APIRouter
+
+router = APIRouter()
+
+@router.get("/users")
+def users():
+    return []
+
+if settings.ENABLE_ADMIN:
+    @router.delete("/admin/cache")
+    def clear_cache():
+        return None
+`
+
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     map[string]bool
+	}{
+		{"Go", "main.go", goConditionalRoutes, map[string]bool{
+			"/users": false, "/search": true, "/search/legacy": true, "/orders": false,
+		}},
+		{"JS", "routes.js", jsConditional, map[string]bool{"/users": false, "/exports": true}},
+		{"Python", "routes.py", pyConditional, map[string]bool{"/users": false, "/admin/cache": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for _, ep := range endpoints {
+				if ep.Conditional != tt.want[ep.Path] {
+					t.Errorf("%s Conditional = %v, want %v", ep.Path, ep.Conditional, tt.want[ep.Path])
+				}
+			}
+		})
+	}
+}
+
+// TestEnvRestrictedRoutes verifies routes mounted only outside production are
+// flagged with the detected condition
+func TestEnvRestrictedRoutes(t *testing.T) {
+	jsRoutes := `const router = express.Router();
+
+router.get('/users', listUsers);
+
+if (process.env.NODE_ENV !== 'production') {
+    router.post('/dev/seed', seedDatabase);
+    router.get('/dev/mail-preview', (req, res) => {
+        if (req.query.raw) {
+            return res.send(raw);
+        }
+        res.render('mail');
+    });
+}
+
+if (req.user) {
+    router.get('/me', me);
+}
+`
+	pyRoutes := `from django.conf import settings
+from django.urls import path
+
+urlpatterns = [
+    path('api/items/', views.list_items),
+]
+
+if settings.DEBUG:
+    urlpatterns += [
+        path('__debug__/', include(debug_toolbar.urls)),
+    ]
+`
+	goRoutes := `package main
+
+func routes(r *gin.Engine) {
+	r.GET("/users", listUsers)
+
+	if gin.Mode() != gin.ReleaseMode {
+		r.GET("/debug/vars", expvarHandler)
+	}
+	if os.Getenv("ENABLE_PPROF") == "1" {
+		r.GET("/debug/pprof", pprofHandler)
+	}
+	if cfg.ReadOnly {
+		r.GET("/status", status)
+	}
+}
+`
+
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     map[string]string
+	}{
+		{"JS", "routes.js", jsRoutes, map[string]string{
+			"/users":            "",
+			"/dev/seed":         "if (process.env.NODE_ENV !== 'production')",
+			"/dev/mail-preview": "if (process.env.NODE_ENV !== 'production')",
+			"/me":               "",
+		}},
+		{"Python", "urls.py", pyRoutes, map[string]string{
+			"api/items/": "", "__debug__/": "if settings.DEBUG",
+		}},
+		{"Go", "main.go", goRoutes, map[string]string{
+			"/users":       "",
+			"/debug/vars":  "if gin.Mode() != gin.ReleaseMode",
+			"/debug/pprof": `if os.Getenv("ENABLE_PPROF") == "1"`,
+			"/status":      "",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(tt.want), endpoints)
+			}
+			for _, ep := range endpoints {
+				want := tt.want[ep.Path]
+				if ep.EnvRestricted != (want != "") || ep.EnvCondition != want {
+					t.Errorf("%s EnvRestricted = %v (%q), want condition %q", ep.Path, ep.EnvRestricted, ep.EnvCondition, want)
+				}
+			}
+		})
+	}
+}
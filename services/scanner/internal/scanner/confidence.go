@@ -0,0 +1,112 @@
+// Package scanner - How sure we are that a match is a real route
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Confidence levels by how a route was found
+const (
+	confidenceDeclared   = 0.9 // decorators, annotations, AST, route macros and config files
+	confidenceStatement  = 0.8 // explicit route tables: Django path(), Laravel Route::get
+	confidenceCall       = 0.6 // method calls that merely look like routing: x.get("/path", h)
+	confidenceClientCall = 0.5 // frontend calls; real, but not served here
+)
+
+// routerReceivers are names conventionally given to routers and apps;
+// names ending in router, app or routes (userRouter) count too
+var routerReceivers = map[string]bool{
+	"app": true, "router": true, "routes": true, "route": true, "server": true, "api": true,
+	"fastify": true, "r": true, "mux": true,
+}
+
+// isRouterName reports whether a receiver is named like a router
+func isRouterName(name string) bool {
+	name = strings.ToLower(name)
+	return routerReceivers[name] || strings.HasSuffix(name, "router") || strings.HasSuffix(name, "app") || strings.HasSuffix(name, "routes")
+}
+
+// callHandlerPattern matches a call passing something after its path: ("/x", handler)
+var callHandlerPattern = regexp.MustCompile(`\(\s*["'\x60][^"'\x60]*["'\x60]\s*,`)
+
+// declaredExtensions hold routes only in declarative forms
+var declaredExtensions = map[string]bool{".go": true, ".proto": true, ".ex": true, ".exs": true}
+
+// scoreConfidence sets each endpoint's Confidence from how it was matched
+// and what surrounds it
+func scoreConfidence(ext string, found []Endpoint, lines []string) {
+	for i := range found {
+		line := ""
+		if n := found[i].LineNumber; n >= 1 && n <= len(lines) {
+			line = lines[n-1]
+		}
+		found[i].Confidence = endpointConfidence(ext, &found[i], line)
+	}
+}
+
+// endpointConfidence scores one endpoint between 0 and 1
+func endpointConfidence(ext string, ep *Endpoint, line string) float64 {
+	code := strings.TrimSpace(line)
+	var score float64
+	switch {
+	case ep.Kind == KindClientCall:
+		score = confidenceClientCall
+	case ep.Kind == KindProcedure, isEventKind(ep.Kind), containsString(ep.Tags, GatewayTag), declaredExtensions[ext],
+		strings.HasPrefix(code, "@"), strings.HasPrefix(code, "["):
+		score = confidenceDeclared
+	case jsExtensions[ext]:
+		score = confidenceCall
+		// Calls on something named like a router, passing a handler, are
+		// far likelier to be routes than cache.get("key")
+		if m := routeReceiverPattern.FindStringSubmatch(line); m != nil && isRouterName(m[1]) {
+			score += 0.2
+		}
+		if callHandlerPattern.MatchString(line) {
+			score += 0.1
+		} else {
+			score -= 0.2
+		}
+	default:
+		score = confidenceStatement
+	}
+
+	if ep.Kind != KindProcedure && !isEventKind(ep.Kind) && !strings.HasPrefix(ep.Path, "/") && ep.Path != "" && ext != ".py" {
+		score -= 0.2 // route paths are absolute outside Django's relative patterns
+	}
+	if ep.Dynamic {
+		score -= 0.2
+	}
+	if ep.RawPath != "" && ep.RawPath != ep.Path && strings.ContainsAny(ep.RawPath, `^$\(`) {
+		score -= 0.1 // regex route, parameters parsed best-effort
+	}
+	return math.Round(math.Min(math.Max(score, 0), 1)*100) / 100
+}
+
+// jsExtensions are the JavaScript/TypeScript source extensions
+var jsExtensions = map[string]bool{".js": true, ".jsx": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true}
+
+// ValidateMinConfidence checks a requested confidence threshold
+func ValidateMinConfidence(min float64) error {
+	if min < 0 || min > 1 {
+		return fmt.Errorf("min_confidence must be between 0 and 1")
+	}
+	return nil
+}
+
+// filterByConfidence drops endpoints scoring below min, returning the kept
+// endpoints and how many were dropped
+func filterByConfidence(eps []Endpoint, min float64) ([]Endpoint, int) {
+	if min <= 0 {
+		return eps, 0
+	}
+	kept := eps[:0:0]
+	for _, ep := range eps {
+		if ep.Confidence >= min {
+			kept = append(kept, ep)
+		}
+	}
+	return kept, len(eps) - len(kept)
+}
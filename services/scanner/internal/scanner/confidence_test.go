@@ -0,0 +1,60 @@
+package scanner
+
+import "testing"
+
+// TestEndpointConfidence verifies explicit routes outscore generic calls
+func TestEndpointConfidence(t *testing.T) {
+	fastapi := ScanFile("main.py", "@app.get(\"/users\")\ndef list_users():\n    return []\n")
+	express := ScanFile("server.js", "router.get('/users', listUsers)\n")
+	generic := ScanFile("cache.js", "const user = cache.get('/users')\n")
+	if len(fastapi) != 1 || len(express) != 1 || len(generic) != 1 {
+		t.Fatalf("unexpected matches: %d FastAPI, %d Express, %d generic", len(fastapi), len(express), len(generic))
+	}
+
+	if !(fastapi[0].Confidence > generic[0].Confidence) {
+		t.Errorf("FastAPI confidence %.2f not above generic .get( %.2f", fastapi[0].Confidence, generic[0].Confidence)
+	}
+	if !(express[0].Confidence > generic[0].Confidence) {
+		t.Errorf("router.get with handler %.2f not above generic .get( %.2f", express[0].Confidence, generic[0].Confidence)
+	}
+	for _, ep := range append(append(fastapi, express...), generic...) {
+		if ep.Confidence <= 0 || ep.Confidence > 1 {
+			t.Errorf("%s %s confidence %.2f out of range", ep.FilePath, ep.Path, ep.Confidence)
+		}
+	}
+}
+
+// TestMinConfidenceFilter verifies weak matches are dropped from scans
+func TestMinConfidenceFilter(t *testing.T) {
+	repoDir, _ := newFixtureRepo(t, map[string]string{
+		"main.py":  "from fastapi import FastAPI\napp = FastAPI()\n\n@app.get(\"/users\")\ndef list_users():\n    return []\n",
+		"cache.js": "const express = require('express')\nconst user = cache.get('/users')\n",
+	})
+
+	for _, tt := range []struct {
+		min  float64
+		want int
+	}{
+		{0, 2},
+		{0.7, 1},
+	} {
+		scanID := "confidence-filter"
+		StartScan(ScanJob{ScanID: scanID, URL: repoDir, MinConfidence: tt.min})
+		eps, err := GetEndpoints(scanID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(eps) != tt.want {
+			t.Errorf("min %.1f kept %d endpoints, want %d: %+v", tt.min, len(eps), tt.want, eps)
+		}
+		for _, ep := range eps {
+			if ep.Confidence < tt.min {
+				t.Errorf("min %.1f kept %s at %.2f", tt.min, ep.FilePath, ep.Confidence)
+			}
+		}
+	}
+
+	if ValidateMinConfidence(1.5) == nil || ValidateMinConfidence(-0.1) == nil {
+		t.Error("out of range thresholds accepted")
+	}
+}
@@ -0,0 +1,300 @@
+// Package scanner - Runtime configuration
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Config holds scanner settings that operators can tune per deployment
+type Config struct {
+	MaxConcurrentScans int    // worker pool size
+	MaxClonesPerHost   int    // concurrent scans per git host; 0 means unlimited
+	CloneCache         bool   // keep checkouts after a scan so they can be re-extracted
+	MaxCachedClones    int    // oldest cached checkouts are evicted beyond this
+	ContextLines       int    // lines before/after a route considered during enrichment
+	MaxLineLength      int    // longer source lines are skipped; 0 means no limit
+	TempDir            string // parent directory for clones; empty uses the system default
+	IncludeHidden      bool   // walk excluded dot-directories (.env, .vscode, ...), except .git
+	StateDir           string // scans are saved here to survive restarts; in memory only when empty
+	ResumeInterrupted  bool   // re-extract scans interrupted by a restart instead of failing them
+
+	HostCloneLimits map[string]int // per-host overrides of MaxClonesPerHost
+
+	ExtractWorkers   int            // Stage 2 workers per file extension
+	ExtensionWorkers map[string]int // per-extension overrides of ExtractWorkers, e.g. .go=1
+
+	InfraPaths  []string // paths (and their subpaths) categorised as infra
+	StripPrefix string   // leading path segment(s) removed from reported paths, e.g. /internal
+	DropPaths   []string // path globs whose endpoints are dropped from results, e.g. /actuator/**
+
+	RiskWeights     map[string]int // points per risk signal; see DefaultRiskWeights
+	TagStrategy     string         // dir (parent directory) or path (first meaningful segment)
+	TagRewrites     []TagRewrite   // canonical names for tags, applied in order after extraction
+	MethodCase      string         // upper, lower or preserve casing of Endpoint.Method
+	ParamStyle      string         // braces, colon, angle or preserve style of Endpoint.Path parameters
+	ActionNames     bool           // derive Endpoint.ActionName from method and path
+	SourceHashes    bool           // hash each handler's source into Endpoint.SourceHash
+	ExtractExamples bool           // mine test files for example payloads (expensive)
+	ExtractDocs     bool           // describe endpoints from route tables and headings in README.md/API.md
+	WithBlame       bool           // date and author of the last change to each declaration line, from git blame (expensive)
+	ReportCommented bool           // report routes inside comments as Commented rather than dropping them
+
+	DisabledLanguages map[string]bool // extractor names, lowercased, whose files are left out of scans
+
+	PostProcessCommand string // transforms endpoints JSON from stdin to stdout before a scan is stored; off when empty
+	PostProcessTimeout int    // seconds the post-process command may run
+
+	CloneUserAgent string            // overrides go-git's user agent when set
+	CloneHeaders   map[string]string // extra headers sent with every clone request
+	GitCredentials map[string]string // clone tokens by host, used when a scan brings none
+	CloneProtocol  string            // https or ssh rewrites remotes to that protocol; empty clones as given
+
+	SSHKeyPath       string // private key for SSH clones; the SSH agent is used when empty
+	SSHKeyPassphrase string
+	SSHKnownHosts    string // known_hosts file verifying SSH servers; SSH clones are refused when empty
+
+	AdminToken string // required by admin endpoints; they are disabled when empty
+
+	CallbackEvents []string // scan events POSTed to a scan's callback_url
+	CallbackSecret string   // HMAC-SHA256 key signing callbacks; unsigned when empty
+	// Callback hosts, IPs or CIDR ranges exempt from the internal address block
+	CallbackAllowHosts []string
+
+	ResultSigningKey string // HMAC-SHA256 key signing completed scan results; unsigned when empty
+
+	GitHostAllowlist []string // hosts that may be cloned; empty allows all
+}
+
+// Tag strategies
+const (
+	TagStrategyDir  = "dir"  // parent directory of the source file
+	TagStrategyPath = "path" // first path segment after api/version prefixes
+)
+
+// DefaultConfig returns the settings used when nothing is configured
+func DefaultConfig() Config {
+	return Config{
+		MaxConcurrentScans: DefaultMaxConcurrentScans,
+		ExtractWorkers:     DefaultExtractWorkers,
+		MaxClonesPerHost:   DefaultMaxClonesPerHost,
+		CloneCache:         false,
+		MaxCachedClones:    20,
+		ContextLines:       DefaultContextLines,
+		MaxLineLength:      DefaultMaxLineLength,
+		InfraPaths:         DefaultInfraPaths,
+		TagStrategy:        TagStrategyDir,
+		MethodCase:         MethodCaseUpper,
+		ParamStyle:         ParamStyleBraces,
+		RiskWeights:        DefaultRiskWeights,
+		CallbackEvents:     DefaultCallbackEvents,
+		PostProcessTimeout: DefaultPostProcessTimeout,
+	}
+}
+
+// LoadConfig reads the configuration from environment variables,
+// falling back to the defaults for anything unset or invalid
+func LoadConfig() Config {
+	cfg := DefaultConfig()
+	cfg.MaxConcurrentScans = envInt("MAX_CONCURRENT_SCANS", cfg.MaxConcurrentScans)
+	cfg.MaxClonesPerHost = envIntMin("MAX_CLONES_PER_HOST", cfg.MaxClonesPerHost, 0)
+	cfg.HostCloneLimits = parseIntPairs(os.Getenv("CLONE_HOST_LIMITS"))
+	cfg.ExtractWorkers = envInt("EXTRACT_WORKERS", cfg.ExtractWorkers)
+	cfg.ExtensionWorkers = parseIntPairs(os.Getenv("EXTRACT_WORKERS_BY_EXT"))
+	cfg.CloneCache = envBool("CLONE_CACHE", cfg.CloneCache)
+	cfg.MaxCachedClones = envInt("MAX_CACHED_CLONES", cfg.MaxCachedClones)
+	cfg.ContextLines = envIntMin("CONTEXT_LINES", cfg.ContextLines, 0)
+	cfg.MaxLineLength = envIntMin("MAX_LINE_LENGTH", cfg.MaxLineLength, 0)
+	cfg.TempDir = os.Getenv("SCANNER_TMPDIR")
+	cfg.IncludeHidden = envBool("INCLUDE_HIDDEN", cfg.IncludeHidden)
+	cfg.StateDir = os.Getenv("SCAN_STATE_DIR")
+	cfg.ResumeInterrupted = envBool("RESUME_INTERRUPTED_SCANS", cfg.ResumeInterrupted)
+	if strategy := strings.ToLower(os.Getenv("TAG_STRATEGY")); strategy == TagStrategyDir || strategy == TagStrategyPath {
+		cfg.TagStrategy = strategy
+	}
+	cfg.TagRewrites = parseTagRewrites(os.Getenv("TAG_REWRITES"))
+	switch methodCase := strings.ToLower(os.Getenv("METHOD_CASE")); methodCase {
+	case MethodCaseUpper, MethodCaseLower, MethodCasePreserve:
+		cfg.MethodCase = methodCase
+	}
+	switch style := strings.ToLower(os.Getenv("PARAM_STYLE")); style {
+	case ParamStyleBraces, ParamStyleColon, ParamStyleAngle, ParamStylePreserve:
+		cfg.ParamStyle = style
+	}
+	cfg.ActionNames = envBool("ACTION_NAMES", cfg.ActionNames)
+	cfg.SourceHashes = envBool("SOURCE_HASHES", cfg.SourceHashes)
+	cfg.ExtractExamples = envBool("EXTRACT_EXAMPLES", cfg.ExtractExamples)
+	cfg.ExtractDocs = envBool("EXTRACT_DOCS", cfg.ExtractDocs)
+	cfg.WithBlame = envBool("WITH_BLAME", cfg.WithBlame)
+	cfg.ReportCommented = envBool("REPORT_COMMENTED_ROUTES", cfg.ReportCommented)
+	for _, name := range parseCommaList(os.Getenv("DISABLED_LANGUAGES")) {
+		if cfg.DisabledLanguages == nil {
+			cfg.DisabledLanguages = make(map[string]bool)
+		}
+		cfg.DisabledLanguages[strings.ToLower(name)] = true
+	}
+	cfg.PostProcessCommand = os.Getenv("POST_PROCESS_COMMAND")
+	cfg.PostProcessTimeout = envInt("POST_PROCESS_TIMEOUT", cfg.PostProcessTimeout)
+	if paths := parseCommaList(os.Getenv("INFRA_PATHS")); len(paths) > 0 {
+		cfg.InfraPaths = paths
+	}
+	cfg.StripPrefix = os.Getenv("STRIP_PATH_PREFIX")
+	cfg.DropPaths = parseCommaList(os.Getenv("DROP_PATHS"))
+	if overrides := parseIntPairs(os.Getenv("RISK_WEIGHTS")); len(overrides) > 0 {
+		cfg.RiskWeights = make(map[string]int)
+		for signal, weight := range DefaultRiskWeights {
+			cfg.RiskWeights[signal] = weight
+		}
+		for signal, weight := range overrides {
+			cfg.RiskWeights[signal] = weight
+		}
+	}
+	cfg.CloneUserAgent = os.Getenv("GIT_USER_AGENT")
+	cfg.CloneHeaders = parseHeaderList(os.Getenv("GIT_EXTRA_HEADERS"))
+	cfg.GitCredentials = parseCredentialList(os.Getenv("GIT_CREDENTIALS"))
+	cfg.CloneProtocol = strings.ToLower(os.Getenv("CLONE_PROTOCOL"))
+	cfg.SSHKeyPath = os.Getenv("SSH_KEY_PATH")
+	cfg.SSHKeyPassphrase = os.Getenv("SSH_KEY_PASSPHRASE")
+	cfg.SSHKnownHosts = os.Getenv("SSH_KNOWN_HOSTS")
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+	if events := parseCommaList(strings.ToLower(os.Getenv("CALLBACK_EVENTS"))); len(events) > 0 {
+		cfg.CallbackEvents = events
+	}
+	cfg.CallbackSecret = os.Getenv("CALLBACK_SECRET")
+	cfg.CallbackAllowHosts = parseCommaList(strings.ToLower(os.Getenv("CALLBACK_ALLOW_HOSTS")))
+	cfg.ResultSigningKey = os.Getenv("RESULT_SIGNING_KEY")
+	cfg.GitHostAllowlist = parseCommaList(strings.ToLower(os.Getenv("GIT_HOST_ALLOWLIST")))
+	return cfg
+}
+
+// Validate checks settings that would otherwise only fail mid-scan
+func (c Config) Validate() error {
+	switch c.CloneProtocol {
+	case "", CloneProtocolHTTPS, CloneProtocolSSH:
+	default:
+		return fmt.Errorf("CLONE_PROTOCOL %q must be https or ssh", c.CloneProtocol)
+	}
+	if c.CloneProtocol == CloneProtocolSSH && c.SSHKnownHosts == "" {
+		return errors.New("CLONE_PROTOCOL=ssh requires SSH_KNOWN_HOSTS")
+	}
+	if c.TempDir != "" {
+		probe, err := os.MkdirTemp(c.TempDir, "scanner-probe-*")
+		if err != nil {
+			return fmt.Errorf("SCANNER_TMPDIR %q is not writable: %w", c.TempDir, err)
+		}
+		os.Remove(probe)
+	}
+	if c.StateDir != "" {
+		if err := os.MkdirAll(c.StateDir, 0o700); err != nil {
+			return fmt.Errorf("SCAN_STATE_DIR %q can't be created: %w", c.StateDir, err)
+		}
+	}
+	if c.PostProcessCommand != "" {
+		if _, err := exec.LookPath(c.PostProcessCommand); err != nil {
+			return fmt.Errorf("POST_PROCESS_COMMAND %q is not executable: %w", c.PostProcessCommand, err)
+		}
+	}
+	return nil
+}
+
+// config is the active configuration
+var config = DefaultConfig()
+
+// Configure replaces the active configuration
+func Configure(cfg Config) {
+	config = cfg
+	installCloneTransport(cfg)
+}
+
+// HostAllowed reports whether a repository URL may be cloned under the
+// active configuration. With an allowlist set, URLs without a host (local
+// paths) are refused.
+func HostAllowed(rawURL string) bool {
+	if len(config.GitHostAllowlist) == 0 {
+		return true
+	}
+	host := hostOf(rawURL)
+	for _, allowed := range config.GitHostAllowlist {
+		if host != "" && host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneLimit returns the concurrent scan cap for a git host
+func (c Config) cloneLimit(host string) int {
+	if limit, ok := c.HostCloneLimits[host]; ok {
+		return limit
+	}
+	return c.MaxClonesPerHost
+}
+
+// extractWorkers returns the Stage 2 worker count for a file extension,
+// given with or without its leading dot
+func (c Config) extractWorkers(ext string) int {
+	n, ok := c.ExtensionWorkers[ext]
+	if !ok {
+		n, ok = c.ExtensionWorkers[strings.TrimPrefix(ext, ".")]
+	}
+	if !ok {
+		n = c.ExtractWorkers
+	}
+	return max(n, 1)
+}
+
+// parseIntPairs parses "github.com=4,git.internal=1" into a map of
+// lowercase keys to non-negative values, e.g. per-host caps
+func parseIntPairs(s string) map[string]int {
+	pairs := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || key == "" || err != nil || n < 0 {
+			continue
+		}
+		pairs[key] = n
+	}
+	return pairs
+}
+
+// parseCommaList splits a comma-separated list, dropping empty entries
+func parseCommaList(s string) []string {
+	var paths []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// envInt reads a positive integer environment variable
+func envInt(key string, fallback int) int {
+	return envIntMin(key, fallback, 1)
+}
+
+// envIntMin reads an integer environment variable of at least min, for
+// settings where 0 means something (no window, no limit)
+func envIntMin(key string, fallback, min int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v >= min {
+		return v
+	}
+	return fallback
+}
+
+// envBool reads a boolean environment variable
+func envBool(key string, fallback bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(key))) {
+	case "1", "true", "yes", "on":
+		return true
+	case "0", "false", "no", "off":
+		return false
+	}
+	return fallback
+}
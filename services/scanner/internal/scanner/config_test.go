@@ -0,0 +1,29 @@
+package scanner
+
+import "testing"
+
+// TestLoadConfigZero verifies settings documented with a meaning for 0 keep
+// it, rather than falling back to their defaults
+func TestLoadConfigZero(t *testing.T) {
+	tests := []struct {
+		key string
+		get func(Config) int
+	}{
+		{"CONTEXT_LINES", func(c Config) int { return c.ContextLines }},
+		{"MAX_CLONES_PER_HOST", func(c Config) int { return c.MaxClonesPerHost }},
+		{"MAX_LINE_LENGTH", func(c Config) int { return c.MaxLineLength }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Setenv(tt.key, "0")
+			if got := tt.get(LoadConfig()); got != 0 {
+				t.Errorf("%s=0 loaded as %d, want 0", tt.key, got)
+			}
+
+			t.Setenv(tt.key, "-1")
+			if got, want := tt.get(LoadConfig()), tt.get(DefaultConfig()); got != want {
+				t.Errorf("%s=-1 loaded as %d, want the default %d", tt.key, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package scanner - CORS policy detection
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CORSPolicy records which origins may call an API cross-origin
+type CORSPolicy struct {
+	Origins []string `json:"origins,omitempty"` // "*" when any origin is allowed; empty when not resolvable
+	Source  string   `json:"source,omitempty"`  // file:line of a global configuration
+}
+
+// AnyOrigin is recorded when a CORS setup doesn't restrict origins
+const AnyOrigin = "*"
+
+var (
+	// Spring: @CrossOrigin, @CrossOrigin("https://a"), @CrossOrigin(origins = {...})
+	javaCrossOriginPattern = regexp.MustCompile(`@CrossOrigin\b(\s*\(.*)?`)
+	// flask-cors: @cross_origin(origins=[...])
+	pythonCrossOriginPattern = regexp.MustCompile(`@cross_origin\b(\s*\(.*)?`)
+	classDeclarationPattern  = regexp.MustCompile(`\bclass\s`)
+
+	// Express: app.get("/x", cors(), h) or cors(corsOptions)
+	jsRouteCORSPattern = regexp.MustCompile(`\bcors\s*\((.*)`)
+
+	// Global setups, keyed by extension
+	globalCORSPatterns = map[string][]*regexp.Regexp{
+		".js": {regexp.MustCompile(`\.use\s*\(\s*cors\s*\(`)},
+		".ts": {
+			regexp.MustCompile(`\.use\s*\(\s*cors\s*\(`),
+			regexp.MustCompile(`\.enableCors\s*\(`), // NestJS
+		},
+		".py": {
+			regexp.MustCompile(`add_middleware\s*\(\s*CORSMiddleware\b`), // FastAPI/Starlette
+			regexp.MustCompile(`\bCORS\s*\(\s*\w+`),                      // flask-cors
+		},
+		".java": {regexp.MustCompile(`\.allowedOrigins(?:Patterns)?\s*\(`)}, // WebMvcConfigurer.addCorsMappings
+	}
+
+	// Origins passed by keyword: origin: ..., origins = ..., allow_origins=...
+	corsOriginsKeyPattern = regexp.MustCompile(`\b(?:origins?|allow_origins|allowedOrigins(?:Patterns)?)\s*([:=(])\s*`)
+)
+
+// corsOrigins reads the allowed origins from a CORS declaration's arguments,
+// starting just after its opening parenthesis. Empty arguments, a wildcard or
+// true allow any origin; origins held in variables can't be resolved and
+// yield none.
+func corsOrigins(args string) []string {
+	var value string
+	if m := corsOriginsKeyPattern.FindStringSubmatchIndex(args); m != nil {
+		value = args[m[1]:]
+		switch {
+		case args[m[2]:m[3]] == "(":
+			value, _, _ = strings.Cut(value, ")")
+		case strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{"):
+			value = payloadLiteral(value)
+		case strings.HasPrefix(value, "true") || strings.HasPrefix(value, "True"):
+			return []string{AnyOrigin}
+		default:
+			value = quotedStringPattern.FindString(value)
+			if !strings.HasPrefix(strings.TrimSpace(args[m[1]:]), value) {
+				value = ""
+			}
+		}
+	} else {
+		// Positional: @CrossOrigin("https://a"), cors()
+		value, _, _ = strings.Cut(strings.TrimPrefix(strings.TrimSpace(args), "("), ")")
+		if strings.TrimSpace(value) == "" {
+			return []string{AnyOrigin}
+		}
+	}
+
+	var origins []string
+	for _, m := range quotedStringPattern.FindAllStringSubmatch(value, -1) {
+		origin := strings.TrimSpace(m[1])
+		if origin == AnyOrigin {
+			return []string{AnyOrigin}
+		}
+		if !containsString(origins, origin) {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// detectRouteCORS captures a CORS policy declared on a single route
+func detectRouteCORS(ext string, ctx routeContext) *CORSPolicy {
+	var m []string
+	switch ext {
+	case ".java", ".kt":
+		m = javaCrossOriginPattern.FindStringSubmatch(ctx.annotations())
+	case ".py":
+		m = pythonCrossOriginPattern.FindStringSubmatch(ctx.annotations())
+	case ".js", ".ts":
+		m = jsRouteCORSPattern.FindStringSubmatch(ctx.lines[ctx.routeIdx])
+	}
+	if m == nil {
+		return nil
+	}
+	return &CORSPolicy{Origins: corsOrigins(m[1])}
+}
+
+// applyClassCORS gives a Spring controller's class-level @CrossOrigin to
+// the routes in the file that don't declare their own
+func applyClassCORS(ext string, found []Endpoint, lines []string) {
+	if ext != ".java" && ext != ".kt" {
+		return
+	}
+	for i, line := range lines {
+		if !javaCrossOriginPattern.MatchString(line) || !annotatesClass(lines, i) {
+			continue
+		}
+		m := javaCrossOriginPattern.FindStringSubmatch(balancedLine(lines, i))
+		policy := &CORSPolicy{Origins: corsOrigins(m[1])}
+		for j := range found {
+			if found[j].CORS == nil && found[j].LineNumber > i {
+				found[j].CORS = policy
+			}
+		}
+		return
+	}
+}
+
+// annotatesClass reports whether the annotation at lines[i] belongs to a class
+func annotatesClass(lines []string, i int) bool {
+	for j := i + 1; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" || strings.HasPrefix(trimmed, "@") || strings.HasPrefix(trimmed, ")") {
+			continue
+		}
+		return classDeclarationPattern.MatchString(trimmed)
+	}
+	return false
+}
+
+// detectGlobalCORS finds an application-wide CORS setup in a file
+func detectGlobalCORS(filePath, content string) *CORSPolicy {
+	patterns := globalCORSPatterns[extOf(filePath)]
+	if len(patterns) == 0 {
+		return nil
+	}
+	lines := sourceLines(content)
+	for i, line := range lines {
+		// The call's arguments may continue on later lines
+		call := balancedLine(lines, i)
+		for _, pattern := range patterns {
+			if loc := pattern.FindStringIndex(call); loc != nil && loc[0] < len(line) {
+				return &CORSPolicy{
+					Origins: corsOrigins(call[loc[1]:]),
+					Source:  fmt.Sprintf("%s:%d", filePath, i+1),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// scanGlobalCORS returns the first global CORS setup among a scan's files
+func scanGlobalCORS(rootDir string, files []string) *CORSPolicy {
+	for _, filePath := range files {
+		if globalCORSPatterns[extOf(filePath)] == nil {
+			continue
+		}
+		content, err := readFile(filePath)
+		if err != nil {
+			continue
+		}
+		relPath, _ := filepath.Rel(rootDir, filePath)
+		if policy := detectGlobalCORS(relPath, string(content)); policy != nil {
+			return policy
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestDetectRouteCORS verifies CORS declared on routes and controllers
+func TestDetectRouteCORS(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		code string
+		want [][]string // origins per endpoint; nil for no CORS
+	}{
+		{
+			name: "spring method",
+			file: "UserController.java",
+			code: `@RestController
+public class UserController {
+    @CrossOrigin(origins = {"https://app.example.com", "https://admin.example.com"})
+    @GetMapping("/users")
+    public List<User> list() { return null; }
+
+    @PostMapping("/users")
+    public User create() { return null; }
+}
+`,
+			want: [][]string{{"https://app.example.com", "https://admin.example.com"}, nil},
+		},
+		{
+			name: "spring class",
+			file: "OrderController.java",
+			code: `@CrossOrigin("https://shop.example.com")
+@RestController
+public class OrderController {
+    @GetMapping("/orders")
+    public List<Order> list() { return null; }
+
+    @CrossOrigin
+    @DeleteMapping("/orders/{id}")
+    public void delete() {}
+}
+`,
+			want: [][]string{{"https://shop.example.com"}, {AnyOrigin}},
+		},
+		{
+			name: "express middleware",
+			file: "routes.js",
+			code: `router.get("/public", cors(), handler)
+router.post("/widgets", cors({ origin: 'https://widgets.example.com' }), create)
+router.get("/private", handler)
+`,
+			want: [][]string{{AnyOrigin}, {"https://widgets.example.com"}, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.file, tt.code)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, ep := range endpoints {
+				var got []string
+				if ep.CORS != nil {
+					got = ep.CORS.Origins
+				}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("%s %s CORS origins = %v, want %v", ep.Method, ep.Path, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDetectGlobalCORS verifies application-wide CORS setups
+func TestDetectGlobalCORS(t *testing.T) {
+	tests := []struct {
+		file string
+		code string
+		want []string
+		line int
+	}{
+		{"app.js", `const app = express()
+app.use(cors())
+`, []string{AnyOrigin}, 2},
+		{"server.ts", `app.use(cors({
+  origin: ['https://a.example.com', 'https://b.example.com'],
+}))
+`, []string{"https://a.example.com", "https://b.example.com"}, 1},
+		{"main.py", `app = FastAPI()
+
+app.add_middleware(
+    CORSMiddleware,
+    allow_origins=["https://app.example.com"],
+    allow_methods=["*"],
+)
+`, []string{"https://app.example.com"}, 3},
+		{"WebConfig.java", `@Configuration
+public class WebConfig implements WebMvcConfigurer {
+    public void addCorsMappings(CorsRegistry registry) {
+        registry.addMapping("/api/**")
+            .allowedOrigins("https://app.example.com", "https://m.example.com");
+    }
+}
+`, []string{"https://app.example.com", "https://m.example.com"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			policy := detectGlobalCORS(tt.file, tt.code)
+			if policy == nil {
+				t.Fatal("detectGlobalCORS() = nil, want a policy")
+			}
+			if !reflect.DeepEqual(policy.Origins, tt.want) {
+				t.Errorf("origins = %v, want %v", policy.Origins, tt.want)
+			}
+			if want := fmt.Sprintf("%s:%d", tt.file, tt.line); policy.Source != want {
+				t.Errorf("source = %q, want %q", policy.Source, want)
+			}
+		})
+	}
+
+	if policy := detectGlobalCORS("routes.js", `router.get("/x", cors(), h)`); policy != nil {
+		t.Errorf("per-route cors() reported as global: %+v", policy)
+	}
+}
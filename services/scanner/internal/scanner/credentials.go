@@ -0,0 +1,157 @@
+// Package scanner - Per-host git credentials with in-memory rotation
+package scanner
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CredentialProvider supplies the token used to clone from a git host
+type CredentialProvider interface {
+	// Token returns the current token for a lowercase host, or "" for none
+	Token(host string) string
+}
+
+// RotatableCredentials is a CredentialProvider whose tokens can be replaced
+// while the scanner runs
+type RotatableCredentials interface {
+	CredentialProvider
+	// Set replaces a host's token; an empty token removes it
+	Set(host, token string)
+	// Hosts lists the hosts holding a token
+	Hosts() []string
+}
+
+// ErrCredentialsReadOnly is returned when the active provider can't rotate tokens
+var ErrCredentialsReadOnly = errors.New("credential provider does not support rotation")
+
+// MemoryCredentials keeps per-host tokens in memory
+type MemoryCredentials struct {
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+// NewMemoryCredentials creates an in-memory provider seeded with tokens by host
+func NewMemoryCredentials(tokens map[string]string) *MemoryCredentials {
+	c := &MemoryCredentials{tokens: make(map[string]string)}
+	for host, token := range tokens {
+		c.Set(host, token)
+	}
+	return c
+}
+
+// Token implements CredentialProvider
+func (c *MemoryCredentials) Token(host string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tokens[strings.ToLower(host)]
+}
+
+// Set implements RotatableCredentials
+func (c *MemoryCredentials) Set(host, token string) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token == "" {
+		delete(c.tokens, host)
+		return
+	}
+	c.tokens[host] = token
+}
+
+// Hosts implements RotatableCredentials
+func (c *MemoryCredentials) Hosts() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	hosts := make([]string, 0, len(c.tokens))
+	for host := range c.tokens {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// credentials is the active provider
+var (
+	credentials   CredentialProvider = NewMemoryCredentials(nil)
+	credentialsMu sync.RWMutex
+)
+
+// SetCredentialProvider replaces the active credential provider
+func SetCredentialProvider(p CredentialProvider) {
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	credentials = p
+}
+
+// activeCredentials returns the active credential provider
+func activeCredentials() CredentialProvider {
+	credentialsMu.RLock()
+	defer credentialsMu.RUnlock()
+	return credentials
+}
+
+// RotateCredential replaces the token used for a host from the next clone on
+func RotateCredential(host, token string) error {
+	rotatable, ok := activeCredentials().(RotatableCredentials)
+	if !ok {
+		return ErrCredentialsReadOnly
+	}
+	rotatable.Set(host, token)
+	return nil
+}
+
+// CredentialHosts lists the hosts the active provider holds tokens for
+func CredentialHosts() ([]string, error) {
+	rotatable, ok := activeCredentials().(RotatableCredentials)
+	if !ok {
+		return nil, ErrCredentialsReadOnly
+	}
+	return rotatable.Hosts(), nil
+}
+
+// cloneToken picks the token for a clone: the one given with the request,
+// otherwise the provider's current token for the repository's host
+func cloneToken(rawURL, token string) string {
+	if token != "" {
+		return token
+	}
+	if host := hostOf(rawURL); host != "" {
+		return activeCredentials().Token(host)
+	}
+	return ""
+}
+
+// urlUserinfoPattern matches credentials embedded in URLs
+var urlUserinfoPattern = regexp.MustCompile(`(\w+://)[^/@\s]+@`)
+
+// redact removes URL credentials and the given secrets from text bound for
+// logs, errors or scan status
+func redact(s string, secrets ...string) string {
+	s = urlUserinfoPattern.ReplaceAllString(s, "${1}***@")
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "***")
+		}
+	}
+	return s
+}
+
+// parseCredentialList parses "github.com=ghp_x,gitlab.com=glpat_y" into
+// tokens by lowercase host
+func parseCredentialList(s string) map[string]string {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		host, token, ok := strings.Cut(pair, "=")
+		host = strings.ToLower(strings.TrimSpace(host))
+		token = strings.TrimSpace(token)
+		if !ok || host == "" || token == "" {
+			continue
+		}
+		tokens[host] = token
+	}
+	return tokens
+}
@@ -0,0 +1,209 @@
+// Package scanner - Endpoint descriptions from README and API docs
+package scanner
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// docFileNames are the markdown files read for route descriptions, by
+// lowercase base name
+var docFileNames = map[string]bool{"readme.md": true, "api.md": true}
+
+// Markdown route patterns
+var (
+	// A path, optionally after its method: /users/{id}, GET /users
+	docRoutePattern = regexp.MustCompile(`^(?:([A-Za-z]+)\s+)?(/\S*)$`)
+	// ## GET /users, ### `/users/{id}`
+	docHeadingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*$`)
+	// Table headers naming the description column
+	docDescriptionHeader = regexp.MustCompile(`(?i)^(?:description|summary|purpose|details|notes?)$`)
+	docTableSeparator    = regexp.MustCompile(`^\|?[\s:|-]+\|?$`)
+	docLinkPattern       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+)
+
+// docRoute is a route described in a markdown document
+type docRoute struct {
+	method, path, text string
+}
+
+// docText strips the inline markdown from a table cell or heading: code
+// spans, emphasis and links
+func docText(s string) string {
+	s = docLinkPattern.ReplaceAllString(s, "$1")
+	s = strings.NewReplacer("`", "", "**", "", "__", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// docRouteOf parses a cell or heading naming a route, such as `GET /users`
+func docRouteOf(s string) (method, path string, ok bool) {
+	m := docRoutePattern.FindStringSubmatch(docText(s))
+	if m == nil {
+		return "", "", false
+	}
+	method = strings.ToUpper(m[1])
+	if method != "" && !knownMethods[method] {
+		return "", "", false
+	}
+	return method, m[2], true
+}
+
+// tableCells splits a markdown table row, keeping escaped pipes in cells
+func tableCells(line string) []string {
+	line = strings.Trim(strings.TrimSpace(line), "|")
+	cells := strings.Split(strings.ReplaceAll(line, `\|`, "\x00"), "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(cell, "\x00", "|"))
+	}
+	return cells
+}
+
+// parseDocRoutes finds the routes a markdown document describes, in tables
+// with a path column:
+//
+//	| Method | Path        | Description    |
+//	|--------|-------------|----------------|
+//	| GET    | /users/{id} | Fetch one user |
+//
+// and in headings naming a route, described by the paragraph that follows:
+//
+//	## GET /users/{id}
+//	Fetch one user.
+//
+// Fenced code blocks are skipped.
+func parseDocRoutes(content string) []docRoute {
+	var routes []docRoute
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	fenced := false
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		// Tables and headings in code blocks are examples, not docs
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "|") && i+1 < len(lines) && docTableSeparator.MatchString(strings.TrimSpace(lines[i+1])):
+			header := tableCells(line)
+			descCol := -1
+			for col, name := range header {
+				if docDescriptionHeader.MatchString(docText(name)) {
+					descCol = col
+				}
+			}
+			i += 2
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				if route, ok := tableRoute(tableCells(lines[i]), descCol); ok {
+					routes = append(routes, route)
+				}
+			}
+			i--
+
+		case docHeadingPattern.MatchString(line):
+			method, path, ok := docRouteOf(docHeadingPattern.FindStringSubmatch(line)[1])
+			if !ok {
+				continue
+			}
+			var paragraph []string
+			for j := i + 1; j < len(lines); j++ {
+				text := strings.TrimSpace(lines[j])
+				if strings.HasPrefix(text, "#") || strings.HasPrefix(text, "|") || strings.HasPrefix(text, "```") ||
+					(text == "" && len(paragraph) > 0) {
+					break
+				}
+				if text != "" {
+					paragraph = append(paragraph, docText(text))
+				}
+			}
+			if len(paragraph) > 0 {
+				routes = append(routes, docRoute{method, path, strings.Join(paragraph, " ")})
+			}
+		}
+	}
+	return routes
+}
+
+// tableRoute reads a table row: the first cell naming a path, a method cell
+// if any, and the description column, or else the first other non-empty cell
+func tableRoute(cells []string, descCol int) (docRoute, bool) {
+	var route docRoute
+	pathCol := -1
+	for col, cell := range cells {
+		if method, path, ok := docRouteOf(cell); ok && pathCol < 0 {
+			route.path, pathCol = path, col
+			if method != "" {
+				route.method = method
+			}
+		} else if method := strings.ToUpper(docText(cell)); knownMethods[method] {
+			route.method = method
+		}
+	}
+	if pathCol < 0 {
+		return route, false
+	}
+	if descCol >= 0 && descCol < len(cells) {
+		route.text = docText(cells[descCol])
+	} else {
+		for col, cell := range cells {
+			if text := docText(cell); col != pathCol && text != "" && !knownMethods[strings.ToUpper(text)] {
+				route.text = text
+				break
+			}
+		}
+	}
+	return route, route.text != ""
+}
+
+// attachDocs reads the README.md and API.md files under rootDir and
+// describes the endpoints they document, matched on path and, when the doc
+// names one, method. The text fills an empty Summary, else an empty
+// Description; what the code declares wins.
+func attachDocs(rootDir string, found []Endpoint) {
+	filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != rootDir && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !docFileNames[strings.ToLower(d.Name())] {
+			return nil
+		}
+		content, err := readFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, route := range parseDocRoutes(string(content)) {
+			describeEndpoints(found, route)
+		}
+		return nil
+	})
+}
+
+// describeEndpoints attaches a documented route's text to the endpoints it
+// names
+func describeEndpoints(found []Endpoint, route docRoute) {
+	for i := range found {
+		ep := &found[i]
+		if ep.Kind == KindClientCall || canonicalPath("/"+strings.TrimPrefix(ep.Path, "/")) != canonicalPath(route.path) {
+			continue
+		}
+		if route.method != "" && !sameMethod(ep.Method, route.method) {
+			continue
+		}
+		switch {
+		case ep.Summary == "":
+			ep.Summary = route.text
+		case ep.Description == "" && ep.Summary != route.text:
+			ep.Description = route.text
+		}
+	}
+}
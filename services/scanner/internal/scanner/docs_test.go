@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestAttachDocs verifies endpoints pick up descriptions from a README
+// route table and an API.md route heading, only when enabled
+func TestAttachDocs(t *testing.T) {
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"app/main.py": `from fastapi import FastAPI
+app = FastAPI()
+
+@app.get("/users")
+def list_users():
+    return []
+
+@app.delete("/users/{user_id}")
+def delete_user(user_id: int):
+    return None
+
+@app.get("/health")
+def health():
+    """Liveness probe."""
+    return {}
+`,
+		"README.md": "# Users service\n\n" +
+			"| Method | Endpoint | Description |\n" +
+			"|--------|----------|-------------|\n" +
+			"| GET | `/users` | Lists **all** users \\| paginated |\n" +
+			"| POST | `/users` | Creates a user |\n" +
+			"| GET | `/health` | Reports readiness |\n",
+		"docs/API.md": "## DELETE /users/:id\n\nRemoves a user and\ntheir sessions.\n\nMore detail.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(rootDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	scan := func(scanID string) map[string]Endpoint {
+		mu.Lock()
+		scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+		endpoints[scanID] = []Endpoint{}
+		mu.Unlock()
+		scanCheckout(scanID, rootDir)
+		eps, _ := GetEndpoints(scanID)
+		byRoute := make(map[string]Endpoint)
+		for _, ep := range eps {
+			byRoute[ep.Method+" "+ep.Path] = ep
+		}
+		return byRoute
+	}
+
+	// Off by default
+	if ep := scan("docs-off")["GET /users"]; ep.Summary != "" {
+		t.Errorf("GET /users summary = %q with docs disabled", ep.Summary)
+	}
+
+	config.ExtractDocs = true
+	eps := scan("docs-on")
+	tests := []struct {
+		route, summary, description string
+	}{
+		{"GET /users", "Lists all users | paginated", ""},
+		{"DELETE /users/{user_id}", "Removes a user and their sessions.", ""},
+		// Declared summaries win; the docs fill the description
+		{"GET /health", "Liveness probe.", "Reports readiness"},
+	}
+	for _, tt := range tests {
+		ep, ok := eps[tt.route]
+		if !ok {
+			t.Errorf("%s not found in %v", tt.route, eps)
+			continue
+		}
+		if ep.Summary != tt.summary || ep.Description != tt.description {
+			t.Errorf("%s = %q / %q, want %q / %q", tt.route, ep.Summary, ep.Description, tt.summary, tt.description)
+		}
+	}
+}
+
+// TestParseDocRoutesSkipsCodeBlocks verifies route tables and headings inside
+// fenced code blocks aren't read as docs
+func TestParseDocRoutesSkipsCodeBlocks(t *testing.T) {
+	content := "## GET /users\n\nLists users.\n\n" +
+		"```markdown\n" +
+		"## DELETE /users/{id}\n\nExample heading.\n\n" +
+		"| Method | Path | Description |\n" +
+		"|--------|------|-------------|\n" +
+		"| POST | /users | Example row |\n" +
+		"```\n\n" +
+		"| Method | Path | Description |\n" +
+		"|--------|------|-------------|\n" +
+		"| GET | /health | Reports readiness |\n"
+
+	want := []docRoute{
+		{"GET", "/users", "Lists users."},
+		{"GET", "/health", "Reports readiness"},
+	}
+	if got := parseDocRoutes(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDocRoutes() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+// Package scanner - Dropping endpoints whose paths match a denylist
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// dropPathRegexp translates a path glob: * matches within a segment and **
+// across segments. A trailing /** also matches the path above it, so
+// /actuator/** drops /actuator itself.
+func dropPathRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case pattern[i:] == "/**":
+			b.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("/?$")
+	return regexp.MustCompile(b.String())
+}
+
+// dropPaths removes the endpoints whose path matches any of the globs,
+// returning those kept and how many were dropped
+func dropPaths(eps []Endpoint, patterns []string) ([]Endpoint, int) {
+	if len(patterns) == 0 {
+		return eps, 0
+	}
+	globs := compileDropPaths(patterns)
+
+	kept := eps[:0:0]
+	for _, ep := range eps {
+		if !droppedPath(ep.Path, globs) {
+			kept = append(kept, ep)
+		}
+	}
+	return kept, len(eps) - len(kept)
+}
+
+// compileDropPaths translates path globs for droppedPath
+func compileDropPaths(patterns []string) []*regexp.Regexp {
+	globs := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		globs[i] = dropPathRegexp(pattern)
+	}
+	return globs
+}
+
+// droppedPath reports whether path matches any of the compiled globs
+func droppedPath(path string, globs []*regexp.Regexp) bool {
+	for _, glob := range globs {
+		if glob.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
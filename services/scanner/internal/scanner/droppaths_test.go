@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestDropPaths verifies endpoints matching the drop globs are removed and
+// counted, per scan or by the server default
+func TestDropPaths(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(rootDir, "main.py"), []byte(`from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/actuator")
+def actuator(): ...
+
+@app.get("/actuator/health")
+def health(): ...
+
+@app.get("/actuator/metrics/{name}")
+def metric(name: str): ...
+
+@app.get("/debug/vars")
+def debug_vars(): ...
+
+@app.get("/users")
+def users(): ...
+
+@app.get("/users/actuator")
+def user_actuator(): ...
+
+@app.get("/actuators")
+def actuators(): ...
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.DropPaths = []string{"/actuator/**"}
+
+	scan := func(scanID string, globs []string) ([]string, int) {
+		mu.Lock()
+		scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now(), DropPaths: globs}
+		endpoints[scanID] = []Endpoint{}
+		mu.Unlock()
+		scanCheckout(scanID, rootDir)
+
+		status, _ := GetStatus(scanID)
+		eps, _ := GetEndpoints(scanID)
+		var paths []string
+		for _, ep := range eps {
+			paths = append(paths, ep.Path)
+		}
+		sort.Strings(paths)
+		if status.Endpoints != len(eps) {
+			t.Errorf("%s: endpoint_count = %d, want %d", scanID, status.Endpoints, len(eps))
+		}
+		return paths, status.DroppedPaths
+	}
+
+	// The server default
+	paths, dropped := scan("drop-default", nil)
+	if want := []string{"/actuators", "/debug/vars", "/users", "/users/actuator"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	if dropped != 3 {
+		t.Errorf("DroppedPaths = %d, want 3", dropped)
+	}
+
+	// A scan's own globs replace the default
+	paths, dropped = scan("drop-request", []string{"/debug/*", "/users/*"})
+	if want := []string{"/actuator", "/actuator/health", "/actuator/metrics/{name}", "/actuators", "/users"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	if dropped != 2 {
+		t.Errorf("DroppedPaths = %d, want 2", dropped)
+	}
+
+	// An empty list opts out of the default
+	if paths, dropped = scan("drop-none", []string{}); len(paths) != 7 || dropped != 0 {
+		t.Errorf("paths = %v, dropped %d, want all 7 kept", paths, dropped)
+	}
+}
@@ -0,0 +1,599 @@
+// Package scanner - Endpoint enrichment from surrounding source lines
+package scanner
+
+import (
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultContextLines is how many lines before/after a route are inspected for metadata
+const DefaultContextLines = 5
+
+// API version patterns
+var (
+	// Path segment: /api/v2/users
+	pathVersionPattern = regexp.MustCompile(`(?i)/(v\d+(?:\.\d+)?)(?:/|$)`)
+	// Media type negotiation: application/vnd.company.v2+json, application/vnd.app-v2+json
+	mediaTypeVersionPattern = regexp.MustCompile(`(?i)application/vnd\.[\w.-]*?[.-](v\d+(?:\.\d+)?)\b`)
+	// Header negotiation: headers = "X-API-Version=2", req.headers['api-version'] === '2'
+	headerVersionPattern = regexp.MustCompile(`(?i)\b(?:x-)?api-version["'\]]*\s*(?:=|==|===|:|,)\s*["']?(v?\d+(?:\.\d+)?)`)
+)
+
+// enrichEndpoints attaches metadata found near each endpoint's declaration.
+// The window never crosses into the neighbouring routes' declarations.
+func enrichEndpoints(found []Endpoint, lines []string) {
+	window := config.ContextLines
+	if window < 0 {
+		window = 0
+	}
+
+	for i := range found {
+		line := found[i].LineNumber
+		start := line - 1 - window
+		end := line - 1 + window
+
+		// Endpoints expanded from one declaration share a line; the
+		// neighbours are the nearest declarations on other lines
+		for j := i - 1; j >= 0; j-- {
+			if found[j].LineNumber < line {
+				start = max(start, found[j].LineNumber)
+				break
+			}
+		}
+		for j := i + 1; j < len(found); j++ {
+			if found[j].LineNumber > line {
+				end = min(end, found[j].LineNumber-2)
+				break
+			}
+		}
+		if start < 0 {
+			start = 0
+		}
+		if end >= len(lines) {
+			end = len(lines) - 1
+		}
+
+		enrichEndpoint(&found[i], routeContext{
+			lines:    lines[start : end+1],
+			routeIdx: line - 1 - start,
+		})
+	}
+}
+
+// routeContext is the window of source lines around a route declaration
+type routeContext struct {
+	lines    []string
+	routeIdx int // index of the route's own line within lines
+}
+
+// text returns the whole window
+func (c routeContext) text() string {
+	return strings.Join(c.lines, "\n")
+}
+
+// after returns the route line and the lines following it
+func (c routeContext) after() string {
+	return strings.Join(c.lines[c.routeIdx:], "\n")
+}
+
+// annotations returns the route line with the contiguous annotation or
+// decorator lines directly above and below it
+func (c routeContext) annotations() string {
+	isAnnotation := func(line string) bool {
+		return strings.HasPrefix(strings.TrimSpace(line), "@")
+	}
+	start, end := c.routeIdx, c.routeIdx
+	for start > 0 && isAnnotation(c.lines[start-1]) {
+		start--
+	}
+	for end+1 < len(c.lines) && isAnnotation(c.lines[end+1]) {
+		end++
+	}
+	return strings.Join(c.lines[start:end+1], "\n")
+}
+
+// enrichEndpoint applies all enrichment detectors to a single endpoint
+func enrichEndpoint(ep *Endpoint, ctx routeContext) {
+	ext := strings.ToLower(filepath.Ext(ep.FilePath))
+	window := ctx.text()
+
+	ep.APIVersion = detectAPIVersion(ep.Path, window)
+	if ep.ResponseType == "" {
+		ep.ResponseType = detectResponseType(ext, ctx.after())
+	}
+	if ep.Summary == "" {
+		ep.Summary = detectSummary(ext, ctx.after())
+	}
+	if ep.Pagination == nil {
+		ep.Pagination = detectPagination(ext, ctx.after())
+	}
+	if ep.RequiredScopes == nil {
+		ep.RequiredScopes = detectRequiredScopes(ext, ctx)
+	}
+	if !ep.Streaming {
+		ep.Streaming = detectStreaming(ctx.annotations() + "\n" + ctx.after())
+	}
+	if !ep.Async {
+		ep.Async = detectAsync(ctx.annotations() + "\n" + ctx.after())
+	}
+	if !ep.Idempotent {
+		ep.Idempotent = idempotentMethods[strings.ToUpper(ep.Method)] || detectIdempotencyKey(ctx.after())
+	}
+	if !ep.Cacheable {
+		ep.Cacheable = detectCacheable(ctx.annotations() + "\n" + ctx.after())
+	}
+	if !ep.FileUpload {
+		ep.FileUpload = detectFileUpload(ctx.annotations() + "\n" + ctx.after())
+	}
+	if !ep.ValidatedInput {
+		ep.ValidatedInput, ep.InputModel = detectInputValidation(ext, ctx)
+	}
+	if ep.CORS == nil {
+		ep.CORS = detectRouteCORS(ext, ctx)
+	}
+	if ep.ResponseHeaders == nil && ep.Kind != KindClientCall {
+		ep.ResponseHeaders = detectResponseHeaders(ctx.after())
+	}
+	if deprecated, sunset := detectSunset(ctx.after()); deprecated {
+		ep.Deprecated = true
+		ep.Sunset = sunset
+	}
+}
+
+// Python docstring directly inside the handler: """List all users."""
+var pythonDocstringPattern = regexp.MustCompile(`(?m)^\s*(?:async\s+)?def\s+\w+\s*\(.*\n\s*(?:"""|\'\'\')\s*([^\n"']+)`)
+
+// detectSummary uses the first line of the handler's docstring as its summary
+func detectSummary(ext, after string) string {
+	if ext != ".py" {
+		return ""
+	}
+	if m := pythonDocstringPattern.FindStringSubmatch(after); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// detectAPIVersion returns the API version from the path, falling back to
+// media type or header based version negotiation near the route
+func detectAPIVersion(path, window string) string {
+	if m := pathVersionPattern.FindStringSubmatch(path); m != nil {
+		return strings.ToLower(m[1])
+	}
+	if m := mediaTypeVersionPattern.FindStringSubmatch(window); m != nil {
+		return strings.ToLower(m[1])
+	}
+	if m := headerVersionPattern.FindStringSubmatch(window); m != nil {
+		version := strings.ToLower(m[1])
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		return version
+	}
+	return ""
+}
+
+// Response type patterns
+var (
+	// FastAPI: @app.get("/users", response_model=UserOut)
+	pythonResponseModelPattern = regexp.MustCompile(`response_model\s*=\s*([\w.\[\]]+)`)
+	// Spring: public ResponseEntity<UserDto> getUser(...)
+	javaReturnTypePattern = regexp.MustCompile(`(?m)^\s*public\s+(?:static\s+)?([\w.<>\[\], ?]+?)\s+\w+\s*\(`)
+	// NestJS: findOne(@Param('id') id: string): Promise<BookDto> {
+	tsReturnTypePattern = regexp.MustCompile(`(?m)^\s*(?:async\s+)?\w+\s*\(.*\)\s*:\s*([\w.<>\[\]]+)\s*\{`)
+	// Wrappers that don't describe the payload itself
+	responseWrapperPattern = regexp.MustCompile(`^(?:ResponseEntity|Promise|Observable|Mono|Optional|CompletableFuture)<(.+)>$`)
+)
+
+// detectResponseType captures the declared response model near a route.
+// Generic wrappers are unwrapped once; other generics are kept verbatim.
+func detectResponseType(ext, after string) string {
+	var m []string
+	switch ext {
+	case ".py":
+		m = pythonResponseModelPattern.FindStringSubmatch(after)
+	case ".java":
+		m = javaReturnTypePattern.FindStringSubmatch(after)
+	case ".ts":
+		m = tsReturnTypePattern.FindStringSubmatch(after)
+	}
+	if m == nil {
+		return ""
+	}
+
+	responseType := strings.TrimSpace(m[1])
+	if w := responseWrapperPattern.FindStringSubmatch(responseType); w != nil {
+		responseType = strings.TrimSpace(w[1])
+	}
+	if responseType == "void" || responseType == "None" || responseType == "?" {
+		return ""
+	}
+	return responseType
+}
+
+// Pagination patterns
+var (
+	// Handler parameter lists, allowing one level of nested parentheses: Query(20)
+	pythonSignaturePattern = regexp.MustCompile(`def\s+\w+\s*\(((?:[^()]|\([^()]*\))*)\)`)
+	javaSignaturePattern   = regexp.MustCompile(`public\s+[\w.<>\[\], ?]+?\s+\w+\s*\(((?:[^()]|\([^()]*\))*)\)`)
+
+	// FastAPI: limit: int = 50, page: int = Query(1), cursor: Optional[str] = None
+	pythonPageParamPattern = regexp.MustCompile(`\b(page|page_size|per_page|limit|offset|skip|cursor|size)\s*:\s*[\w\[\]., ]+?(?:=\s*(?:Query\(\s*(?:default\s*=\s*)?)?(\d+))?\s*(?:[,)]|$)`)
+	// Spring: @RequestParam(defaultValue = "20") int size
+	javaPageParamPattern    = regexp.MustCompile(`@RequestParam\s*(?:\(([^)]*)\))?\s*(?:final\s+)?\w+\s+(page|pageSize|perPage|limit|offset|cursor|size)\b`)
+	javaDefaultValuePattern = regexp.MustCompile(`defaultValue\s*=\s*"(\d+)"`)
+	// Spring Data: @PageableDefault(size = 20) Pageable pageable
+	javaPageableDefaultPattern = regexp.MustCompile(`@PageableDefault\s*\(\s*(?:size\s*=\s*|value\s*=\s*)?(\d+)`)
+)
+
+// pageSizeParams are the parameters whose default is the page size
+var pageSizeParams = map[string]bool{
+	"limit": true, "size": true, "page_size": true, "per_page": true, "pageSize": true, "perPage": true,
+}
+
+// detectPagination captures pagination parameters from a handler signature
+func detectPagination(ext, after string) *Pagination {
+	p := &Pagination{}
+
+	switch ext {
+	case ".py":
+		sig := pythonSignaturePattern.FindStringSubmatch(after)
+		if sig == nil {
+			return nil
+		}
+		for _, m := range pythonPageParamPattern.FindAllStringSubmatch(sig[1], -1) {
+			p.add(m[1], m[2])
+		}
+
+	case ".java":
+		sig := javaSignaturePattern.FindStringSubmatch(after)
+		if sig == nil {
+			return nil
+		}
+		if strings.Contains(sig[1], "Pageable ") {
+			p.Params = append(p.Params, "page", "size", "sort")
+			if m := javaPageableDefaultPattern.FindStringSubmatch(sig[1]); m != nil {
+				p.DefaultPageSize, _ = strconv.Atoi(m[1])
+			} else {
+				p.DefaultPageSize = 20 // Spring Data default
+			}
+		}
+		for _, m := range javaPageParamPattern.FindAllStringSubmatch(sig[1], -1) {
+			def := ""
+			if d := javaDefaultValuePattern.FindStringSubmatch(m[1]); d != nil {
+				def = d[1]
+			}
+			p.add(m[2], def)
+		}
+
+	default:
+		return nil
+	}
+
+	if len(p.Params) == 0 {
+		return nil
+	}
+	return p
+}
+
+// add records a pagination parameter and, for size parameters, its default
+func (p *Pagination) add(name, def string) {
+	for _, existing := range p.Params {
+		if existing == name {
+			return
+		}
+	}
+	p.Params = append(p.Params, name)
+	if pageSizeParams[name] && def != "" {
+		p.DefaultPageSize, _ = strconv.Atoi(def)
+	}
+}
+
+// Security requirement patterns
+var (
+	// Spring: @Secured("ROLE_ADMIN"), @Secured({"ROLE_A", "ROLE_B"}), @RolesAllowed("admin")
+	javaSecuredPattern = regexp.MustCompile(`@(?:Secured|RolesAllowed)\s*\(\s*(?:value\s*=\s*)?\{?([^)}]*)`)
+	// Spring: @PreAuthorize("hasScope('users:read') and hasRole('ADMIN')")
+	javaPreAuthorizePattern = regexp.MustCompile(`@PreAuthorize\s*\(\s*"([^"]*)"`)
+	springExpressionPattern = regexp.MustCompile(`\b(hasScope|hasAnyScope|hasRole|hasAnyRole|hasAuthority|hasAnyAuthority)\s*\(([^)]*)\)`)
+	// FastAPI: user = Security(get_current_user, scopes=["items:read"])
+	pythonSecurityPattern = regexp.MustCompile(`Security\s*\((?:[^()]|\([^()]*\))*?scopes\s*=\s*\[([^\]]*)\]`)
+	// Express: app.get("/x", requiredScopes("read:users"), h), guard.check(["admin"])
+	jsScopeMiddlewarePattern = regexp.MustCompile(`\b(?:requiredScopes|requireScopes?|checkScopes|scopeCheck|guard\.check)\s*\(\s*\[?([^)\]]*)`)
+
+	quotedStringPattern = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// detectRequiredScopes captures the roles and scopes a route declares
+func detectRequiredScopes(ext string, ctx routeContext) []string {
+	var scopes []string
+	add := func(list string, prefix string) {
+		for _, m := range quotedStringPattern.FindAllStringSubmatch(list, -1) {
+			// Space-separated scope strings: requiredScopes("read:a write:a")
+			for _, scope := range strings.Fields(m[1]) {
+				if prefix != "" && !strings.HasPrefix(scope, prefix) {
+					scope = prefix + scope
+				}
+				if !containsString(scopes, scope) {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+
+	switch ext {
+	case ".java":
+		block := ctx.annotations()
+		for _, m := range javaSecuredPattern.FindAllStringSubmatch(block, -1) {
+			add(m[1], "")
+		}
+		for _, m := range javaPreAuthorizePattern.FindAllStringSubmatch(block, -1) {
+			for _, expr := range springExpressionPattern.FindAllStringSubmatch(m[1], -1) {
+				// hasRole('ADMIN') checks the ROLE_ADMIN authority
+				prefix := ""
+				if strings.HasSuffix(expr[1], "Role") {
+					prefix = "ROLE_"
+				}
+				add(expr[2], prefix)
+			}
+		}
+
+	case ".py":
+		// Decorator dependencies and the handler signature
+		after := ctx.after()
+		if sig := pythonSignaturePattern.FindStringIndex(after); sig != nil {
+			after = after[:sig[1]]
+		}
+		for _, m := range pythonSecurityPattern.FindAllStringSubmatch(after, -1) {
+			add(m[1], "")
+		}
+
+	case ".js", ".ts":
+		// Middleware passed alongside the handler on the route line
+		for _, m := range jsScopeMiddlewarePattern.FindAllStringSubmatch(ctx.lines[ctx.routeIdx], -1) {
+			add(m[1], "")
+		}
+	}
+
+	return scopes
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Deprecation headers set by a handler (RFC 8594 / RFC 9745), in any of
+// res.set('Sunset', '...'), c.Header("Sunset", "..."),
+// response.headers["Sunset"] = "...", or {"Sunset": "..."}
+var (
+	sunsetHeaderPattern      = regexp.MustCompile(`(?i)["']Sunset["']\s*\]?\s*(?:,|=|:)\s*["']([^"']+)["']`)
+	deprecationHeaderPattern = regexp.MustCompile(`(?i)["']Deprecation["']\s*\]?\s*(?:,|=|:)`)
+)
+
+// detectSunset reports whether the handler marks itself deprecated and,
+// if it sets a Sunset header, the sunset date
+func detectSunset(after string) (bool, string) {
+	if m := sunsetHeaderPattern.FindStringSubmatch(after); m != nil {
+		value := strings.TrimSpace(m[1])
+		if t, err := http.ParseTime(value); err == nil {
+			return true, t.UTC().Format("2006-01-02")
+		}
+		return true, value
+	}
+	return deprecationHeaderPattern.MatchString(after), ""
+}
+
+var (
+	// Declared stream types and content types, across frameworks
+	streamingResponsePattern = regexp.MustCompile(`(?i:text/event-stream)|TEXT_EVENT_STREAM|\b(?:StreamingResponse|EventSourceResponse|SseEmitter|ResponseBodyEmitter|StreamingResponseBody|ServerSentEvent|PushStreamContent)\b|\.(?:Stream|SSEvent)\s*\(|http\.Flusher|\bstream_with_context\b`)
+	// Express/Node: res.write(...) repeated in a loop or flushed
+	streamWritePattern = regexp.MustCompile(`\b(?:res|response|reply\.raw)\.write\s*\(`)
+	streamLoopPattern  = regexp.MustCompile(`\b(?:for|while|setInterval)\b|\.(?:flush|flushHeaders)\s*\(|\.on\s*\(\s*["']data["']`)
+)
+
+// detectStreaming reports whether a handler responds with server-sent
+// events or a chunked stream
+func detectStreaming(handler string) bool {
+	if streamingResponsePattern.MatchString(handler) {
+		return true
+	}
+	return streamWritePattern.MatchString(handler) && streamLoopPattern.MatchString(handler)
+}
+
+// Background job enqueues and long-polling: Celery task.delay() and
+// apply_async(), RQ and asynq enqueue, Sidekiq perform_async, ActiveJob
+// perform_later, Bull queue.add(), Hangfire BackgroundJob.Enqueue, Laravel
+// Job::dispatch(), FastAPI background tasks, Spring @Async and DeferredResult
+var asyncJobPattern = regexp.MustCompile(`\.(?:delay|apply_async|send_task|enqueue|enqueue_call|Enqueue|perform_async|perform_in|perform_later|add_task)\s*\(|\b\w*[qQ]ueue\.add\s*\(|::dispatch\s*\(|\bdispatch\s*\(\s*new\s|@Async\b|\bDeferredResult\b`)
+
+// detectAsync reports whether a handler hands its work to a background job
+// or holds the request open until something happens
+func detectAsync(handler string) bool {
+	return asyncJobPattern.MatchString(handler)
+}
+
+// File upload parameters and middleware: FastAPI UploadFile, Flask
+// request.files, multer, Spring MultipartFile, Gin FormFile, ASP.NET
+// IFormFile, Laravel $request->file()
+var fileUploadPattern = regexp.MustCompile(`\b(?:UploadFile|MultipartFile|IFormFile|FormFile|multer|request\.files|upload\.(?:single|array|fields|any))\b|\$request->(?:file|hasFile)\s*\(`)
+
+// detectFileUpload reports whether a handler accepts uploaded files
+func detectFileUpload(handler string) bool {
+	return fileUploadPattern.MatchString(handler)
+}
+
+// idempotentMethods are idempotent by definition (RFC 9110 section 9.2.2)
+var idempotentMethods = map[string]bool{
+	"GET": true, "HEAD": true, "OPTIONS": true, "TRACE": true, "PUT": true, "DELETE": true,
+}
+
+var (
+	// Handlers reading an Idempotency-Key header make retries safe
+	idempotencyKeyPattern = regexp.MustCompile(`(?i)["']idempotency-key["']|\bIdempotency_Key\b|@Idempotent\b`)
+
+	// Cache-Control values set by the handler: res.set('Cache-Control', 'public, max-age=60')
+	cacheControlPattern = regexp.MustCompile(`(?i)["']Cache-Control["']\s*\]?\s*(?:,|=|:)\s*["']([^"']+)["']`)
+	// Caching annotations and middleware: Spring @Cacheable, Django @cache_page,
+	// fastapi-cache @cache(), ASP.NET [ResponseCache], apicache, Rails expires_in
+	cacheAnnotationPattern = regexp.MustCompile(`@Cacheable\b|@cache_page\b|@cache\s*\(|\[ResponseCache\b|\bapicache\.middleware\b|\bcache\(\s*["']\d|\bexpires_in\b`)
+)
+
+// detectIdempotencyKey reports whether a handler honours an Idempotency-Key
+func detectIdempotencyKey(after string) bool {
+	return idempotencyKeyPattern.MatchString(after)
+}
+
+// detectCacheable reports whether a handler's responses may be cached, from
+// its Cache-Control header or a caching annotation
+func detectCacheable(handler string) bool {
+	if m := cacheControlPattern.FindStringSubmatch(handler); m != nil {
+		value := strings.ToLower(m[1])
+		if strings.Contains(value, "no-store") || strings.Contains(value, "no-cache") || strings.Contains(value, "max-age=0") {
+			return false
+		}
+		return strings.Contains(value, "max-age") || strings.Contains(value, "public") || strings.Contains(value, "immutable")
+	}
+	return cacheAnnotationPattern.MatchString(handler)
+}
+
+// Response headers set by a handler
+var (
+	// Only receivers holding the response count: outgoing request headers
+	// and context stores such as Gin's c.Set("user", u) aren't response headers.
+	// res.set('X-Total-Count', n), reply.header(...), ctx.set(...) (Koa),
+	// response.headers.set(...), c.Header("X", v), c.Writer.Header().Set(...),
+	// c.Response().Header().Set(...), w.Header().Set(...),
+	// response.setHeader(...), Response.Headers.Add(...)
+	headerCallPattern = regexp.MustCompile(`\b(?:` +
+		`(?:res|resp|response|reply|ctx)\.(?:set|setHeader|header|append|addHeader)` +
+		`|(?:res|resp|response)\.headers\.(?:set|append)` +
+		`|c\.Header` +
+		`|(?:c|ctx)\.(?:Writer|Response\(\))\.Header\(\)\.(?:Set|Add)` +
+		`|(?:w|rw)\.Header\(\)\.(?:Set|Add)` +
+		`|Response\.Headers\.(?:Add|Append)` +
+		`|responseHeaders\.(?:set|add|Set|Add)` +
+		`)\s*\(\s*["']([A-Za-z][\w-]*)["']`)
+	// response.headers["X-Request-Id"] = ..., Django response["ETag"] = ...,
+	// Response.Headers["X-A"] = ...
+	headerIndexPattern = regexp.MustCompile(`(?:\b(?:res|resp|response)(?:\.headers)?|\bResponse\.Headers)\s*\[\s*["']([A-Za-z][\w-]*)["']\s*\]\s*=[^=]`)
+	// Spring ResponseEntity builders: ResponseEntity.ok().header("X-Rate", ...)
+	headerBuilderPattern = regexp.MustCompile(`\)\s*\.header\s*\(\s*"([A-Za-z][\w-]*)"`)
+	// Builder shorthands for well-known headers: .eTag(v), .location(uri)
+	headerShorthandPattern = regexp.MustCompile(`\)\s*\.(eTag|location|lastModified|cacheControl)\s*\(`)
+	// Header maps: JSONResponse(data, headers={"X-A": ...}), res.set({ 'X-A': ... }),
+	// res.writeHead(200, { 'X-A': ... })
+	headerMapPattern    = regexp.MustCompile(`(?:\b\w*Response\s*\([^{()]*\bheaders\s*=\s*|\b(?:res|reply|ctx|response)\.(?:set|header|headers|writeHead)\s*\(\s*(?:\d+\s*,\s*)?)\{([^}]*)\}`)
+	headerMapKeyPattern = regexp.MustCompile(`["']([A-Za-z][\w-]*)["']\s*:`)
+)
+
+// headerShorthands are the headers set by Spring's builder shorthands
+var headerShorthands = map[string]string{
+	"eTag": "ETag", "location": "Location", "lastModified": "Last-Modified", "cacheControl": "Cache-Control",
+}
+
+// detectResponseHeaders returns the names of the headers a handler sets on
+// its responses, in the order first set
+func detectResponseHeaders(handler string) []string {
+	type match struct {
+		at   int
+		name string
+	}
+	var matches []match
+	for _, pattern := range []*regexp.Regexp{headerCallPattern, headerIndexPattern, headerBuilderPattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(handler, -1) {
+			matches = append(matches, match{m[2], handler[m[2]:m[3]]})
+		}
+	}
+	for _, m := range headerShorthandPattern.FindAllStringSubmatchIndex(handler, -1) {
+		matches = append(matches, match{m[2], headerShorthands[handler[m[2]:m[3]]]})
+	}
+	for _, m := range headerMapPattern.FindAllStringSubmatchIndex(handler, -1) {
+		for _, k := range headerMapKeyPattern.FindAllStringSubmatchIndex(handler[m[2]:m[3]], -1) {
+			matches = append(matches, match{m[2] + k[2], handler[m[2]+k[2] : m[2]+k[3]]})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].at < matches[j].at })
+
+	var headers []string
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		if key := strings.ToLower(m.name); !seen[key] {
+			seen[key] = true
+			headers = append(headers, m.name)
+		}
+	}
+	return headers
+}
+
+// Input validation patterns
+var (
+	// FastAPI: def create(user: UserCreate, db: Session = Depends(get_db))
+	pythonTypedParamPattern = regexp.MustCompile(`\b\w+\s*:\s*([A-Z]\w*)\s*(=\s*\w+)?`)
+	// NestJS: @Body() dto: CreateUserDto, @Body(new ValidationPipe()) dto: CreateUserDto
+	nestBodyPattern = regexp.MustCompile(`@Body\s*\((?:[^()]|\([^()]*\))*\)\s*\w+\s*:\s*([A-Z][\w.]*)`)
+	// Spring: @Valid @RequestBody UserDto body, @RequestBody @Validated UserDto body
+	springValidBodyPattern = regexp.MustCompile(`(?:@Valid(?:ated)?\s+@RequestBody|@RequestBody\s+@Valid(?:ated)?)\s+(?:final\s+)?([\w.]+)`)
+	// express-validator chains and schemas: body('email').isEmail(), checkSchema(userSchema)
+	expressValidatorPattern = regexp.MustCompile(`\b(?:body|check|param|query)\s*\(\s*["'][^"']*["']\s*\)\s*\.\s*(?:is|not|exists|optional|trim|escape|matches|custom|notEmpty)\w*\s*\(`)
+	expressSchemaPattern    = regexp.MustCompile(`\bcheckSchema\s*\(\s*(\w+)?`)
+)
+
+// pythonNonModelTypes are capitalised FastAPI parameter types that aren't
+// request models: framework types, typing wrappers and standard library
+// scalars taken from the path or query
+var pythonNonModelTypes = map[string]bool{
+	"Request": true, "Response": true, "WebSocket": true, "BackgroundTasks": true,
+	"UploadFile": true, "Session": true, "AsyncSession": true, "Optional": true,
+	"List": true, "Dict": true, "Set": true, "Tuple": true, "Sequence": true,
+	"Any": true, "Union": true, "Annotated": true, "Literal": true,
+	"HTTPAuthorizationCredentials": true, "OAuth2PasswordRequestForm": true,
+	"UUID": true, "Decimal": true, "Path": true, "PurePath": true,
+	"IPv4Address": true, "IPv6Address": true,
+}
+
+// detectInputValidation reports whether a handler's input is validated by a
+// schema (Pydantic model, class-validator DTO, @Valid body, express-validator)
+// and the model name when one is declared. Best-effort: Pydantic and Nest
+// bodies are assumed to be validated models when they're class-typed.
+func detectInputValidation(ext string, ctx routeContext) (bool, string) {
+	switch ext {
+	case ".py":
+		m := pythonSignaturePattern.FindStringSubmatch(ctx.after())
+		if m == nil {
+			return false, ""
+		}
+		for _, p := range pythonTypedParamPattern.FindAllStringSubmatch(m[1], -1) {
+			// Dependencies and defaulted params aren't the request body
+			if p[2] != "" || pythonNonModelTypes[p[1]] {
+				continue
+			}
+			return true, p[1]
+		}
+	case ".ts", ".js":
+		handler := ctx.annotations() + "\n" + ctx.after()
+		if m := nestBodyPattern.FindStringSubmatch(handler); m != nil {
+			return true, m[1]
+		}
+		if m := expressSchemaPattern.FindStringSubmatch(handler); m != nil {
+			return true, m[1]
+		}
+		if expressValidatorPattern.MatchString(handler) {
+			return true, ""
+		}
+	case ".java", ".kt":
+		if m := springValidBodyPattern.FindStringSubmatch(ctx.annotations() + "\n" + ctx.after()); m != nil {
+			return true, m[1]
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,651 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	javaMediaTypeVersion = `@RestController
+@RequestMapping("/api")
+public class UserController {
+
+    @GetMapping(value = "/users", produces = "application/vnd.company.app-v2+json")
+    public List<User> getUsersV2() {
+        return users;
+    }
+
+    @GetMapping(value = "/accounts", headers = "X-API-Version=3")
+    public List<Account> getAccounts() {
+        return accounts;
+    }
+
+    @GetMapping("/v1/orders")
+    public List<Order> getOrders() {
+        return orders;
+    }
+
+    @GetMapping("/status")
+    public String status() {
+        return "ok";
+    }
+}
+`
+)
+
+// TestDetectAPIVersion verifies path, media type and header based versions are captured
+func TestDetectAPIVersion(t *testing.T) {
+	endpoints := ScanFile("UserController.java", javaMediaTypeVersion)
+	want := map[string]string{
+		"/api/users":     "v2",
+		"/api/accounts":  "v3",
+		"/api/v1/orders": "v1",
+		"/api/status":    "",
+	}
+
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(want))
+	}
+	for _, ep := range endpoints {
+		if ep.APIVersion != want[ep.Path] {
+			t.Errorf("%s APIVersion = %q, want %q", ep.Path, ep.APIVersion, want[ep.Path])
+		}
+	}
+}
+
+// TestDetectResponseType verifies declared response models are captured
+func TestDetectResponseType(t *testing.T) {
+	pythonResponseModel := `from fastapi import APIRouter
+
+router = APIRouter()
+
+@router.get("/users/{user_id}", response_model=UserOut)
+async def get_user(user_id: int):
+    return {}
+`
+	tsReturnType := `@Controller('books')
+export class BooksController {
+    @Get(':id')
+    async findOne(@Param('id') id: string): Promise<BookDto> {
+        return this.books.find(id);
+    }
+}
+`
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     []string
+	}{
+		{"Spring", "UserController.java", javaSpring, []string{"List<User>", "User", "User"}},
+		{"FastAPI", "routes/users.py", pythonResponseModel, []string{"UserOut"}},
+		{"NestJS", "books.controller.ts", tsReturnType, []string{"BookDto"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if endpoints[i].ResponseType != want {
+					t.Errorf("endpoint %d ResponseType = %q, want %q", i, endpoints[i].ResponseType, want)
+				}
+			}
+		})
+	}
+}
+
+// TestContextLinesWindow verifies the enrichment window size controls what is captured
+func TestContextLinesWindow(t *testing.T) {
+	content := `from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/users")
+async def list_users():
+    """List all users."""
+    return []
+`
+	defer func(orig Config) { Configure(orig) }(config)
+
+	tests := []struct {
+		contextLines int
+		want         string
+	}{
+		{1, ""},
+		{2, "List all users."},
+		{1000, "List all users."}, // window well past the file bounds
+	}
+
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.ContextLines = tt.contextLines
+		Configure(cfg)
+
+		endpoints := ScanFile("main.py", content)
+		if len(endpoints) != 1 {
+			t.Fatalf("ScanFile() found %d endpoints, want 1", len(endpoints))
+		}
+		if endpoints[0].Summary != tt.want {
+			t.Errorf("ContextLines=%d: Summary = %q, want %q", tt.contextLines, endpoints[0].Summary, tt.want)
+		}
+	}
+}
+
+// TestDetectPagination verifies paging parameters and default page sizes are captured
+func TestDetectPagination(t *testing.T) {
+	fastAPI := `@router.get("/users")
+async def list_users(skip: int = 0, limit: int = Query(50, le=100), q: str = ""):
+    return []
+
+@router.get("/users/{user_id}")
+async def get_user(user_id: int):
+    return {}
+`
+	spring := `@RestController
+public class OrderController {
+    @GetMapping("/orders")
+    public Page<Order> list(@PageableDefault(size = 25) Pageable pageable) {
+        return orders.findAll(pageable);
+    }
+
+    @GetMapping("/events")
+    public List<Event> events(@RequestParam(defaultValue = "0") int page, @RequestParam(value = "size", defaultValue = "10") int size) {
+        return events.page(page, size);
+    }
+}
+`
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     []*Pagination
+	}{
+		{"FastAPI", "users.py", fastAPI, []*Pagination{
+			{Params: []string{"skip", "limit"}, DefaultPageSize: 50},
+			nil,
+		}},
+		{"Spring", "OrderController.java", spring, []*Pagination{
+			{Params: []string{"page", "size", "sort"}, DefaultPageSize: 25},
+			{Params: []string{"page", "size"}, DefaultPageSize: 10},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !reflect.DeepEqual(endpoints[i].Pagination, want) {
+					t.Errorf("%s Pagination = %+v, want %+v", endpoints[i].Path, endpoints[i].Pagination, want)
+				}
+			}
+		})
+	}
+}
+
+// TestDetectRequiredScopes verifies security annotations and middleware are captured
+func TestDetectRequiredScopes(t *testing.T) {
+	spring := `@RestController
+public class AdminController {
+    @Secured({"ROLE_ADMIN", "ROLE_OPS"})
+    @GetMapping("/admin/users")
+    public List<User> users() {
+        return users.findAll();
+    }
+
+    @PostMapping("/admin/reports")
+    @PreAuthorize("hasScope('reports:write') and hasRole('AUDITOR')")
+    public Report create(@RequestBody Report report) {
+        return reports.save(report);
+    }
+
+    @GetMapping("/public")
+    public String open() {
+        return "ok";
+    }
+}
+`
+	fastAPI := `@app.get("/items")
+async def list_items(user: User = Security(get_current_user, scopes=["items:read"])):
+    return []
+
+@app.post("/items", dependencies=[Security(verify, scopes=["items:write", "items:read"])])
+async def create_item(item: Item):
+    return item
+
+@app.get("/status")
+async def status():
+    return {}
+`
+	express := `app.get("/messages", requiredScopes("read:messages write:messages"), listMessages)
+app.delete("/messages/:id", guard.check(["admin"]), deleteMessage)
+app.get("/ping", ping)
+`
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     [][]string
+	}{
+		{"Spring", "AdminController.java", spring, [][]string{
+			{"ROLE_ADMIN", "ROLE_OPS"},
+			{"reports:write", "ROLE_AUDITOR"},
+			nil,
+		}},
+		{"FastAPI", "items.py", fastAPI, [][]string{
+			{"items:read"},
+			{"items:write", "items:read"},
+			nil,
+		}},
+		{"Express", "routes.js", express, [][]string{
+			{"read:messages", "write:messages"},
+			{"admin"},
+			nil,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !reflect.DeepEqual(endpoints[i].RequiredScopes, want) {
+					t.Errorf("%s RequiredScopes = %v, want %v", endpoints[i].Path, endpoints[i].RequiredScopes, want)
+				}
+			}
+		})
+	}
+}
+
+// TestDetectSunset verifies Deprecation/Sunset headers set by handlers are captured
+func TestDetectSunset(t *testing.T) {
+	express := `router.get("/v1/users", (req, res) => {
+  res.set('Deprecation', 'true')
+  res.set('Sunset', 'Wed, 31 Dec 2025 23:59:59 GMT')
+  res.json(users)
+})
+
+router.get("/v1/orders", (req, res) => {
+  res.set("Deprecation", "@1735689600")
+  res.json(orders)
+})
+
+router.get("/v2/users", (req, res) => {
+  res.json(users)
+})
+`
+	gin := `package api
+
+func Register(r *gin.Engine) {
+	r.GET("/legacy", func(c *gin.Context) {
+		c.Header("Sunset", "2026-06-30")
+		c.JSON(200, nil)
+	})
+}
+`
+	tests := []struct {
+		name       string
+		filePath   string
+		content    string
+		deprecated []bool
+		sunset     []string
+	}{
+		{"Express", "routes.js", express, []bool{true, true, false}, []string{"2025-12-31", "", ""}},
+		{"Gin", "api.go", gin, []bool{true}, []string{"2026-06-30"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.sunset) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.sunset))
+			}
+			for i, ep := range endpoints {
+				if ep.Deprecated != tt.deprecated[i] || ep.Sunset != tt.sunset[i] {
+					t.Errorf("%s deprecated=%v sunset=%q, want %v %q", ep.Path, ep.Deprecated, ep.Sunset, tt.deprecated[i], tt.sunset[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDetectStreaming verifies SSE and chunked stream handlers are flagged
+func TestDetectStreaming(t *testing.T) {
+	fastapi := `@app.get("/events")
+async def events():
+    return StreamingResponse(event_source(), media_type="text/event-stream")
+
+@app.get("/users")
+async def users():
+    return []
+`
+	spring := `@RestController
+public class FeedController {
+    @GetMapping(value = "/feed", produces = MediaType.TEXT_EVENT_STREAM_VALUE)
+    public Flux<Item> feed() { return items; }
+
+    @GetMapping("/notifications")
+    public SseEmitter notifications() {
+        return new SseEmitter();
+    }
+
+    @GetMapping("/items")
+    public List<Item> items() { return items; }
+}
+`
+	express := `router.get("/progress", (req, res) => {
+  for (const step of steps) {
+    res.write(JSON.stringify(step) + "\n")
+  }
+  res.end()
+})
+
+router.post("/upload", (req, res) => {
+  res.write("ok")
+  res.end()
+})
+`
+	tests := []struct {
+		name      string
+		filePath  string
+		content   string
+		streaming []bool
+	}{
+		{"FastAPI", "events.py", fastapi, []bool{true, false}},
+		{"Spring", "FeedController.java", spring, []bool{true, true, false}},
+		{"Express", "routes.js", express, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.streaming) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.streaming))
+			}
+			for i, ep := range endpoints {
+				if ep.Streaming != tt.streaming[i] {
+					t.Errorf("%s streaming = %v, want %v", ep.Path, ep.Streaming, tt.streaming[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDetectAsync verifies handlers enqueueing background jobs are flagged
+// and synchronous handlers aren't
+func TestDetectAsync(t *testing.T) {
+	flask := `@app.post("/reports")
+def create_report():
+    job = generate_report.delay(request.json["month"])
+    return {"job_id": job.id}, 202
+
+@app.get("/reports/<id>")
+def get_report(id):
+    return Report.query.get(id).to_dict()
+`
+	express := `router.post("/emails", async (req, res) => {
+  await emailQueue.add("welcome", { to: req.body.email })
+  res.status(202).end()
+})
+
+router.get("/emails", async (req, res) => {
+  res.json(await Email.findAll())
+})
+`
+	laravel := `Route::post('/podcasts', function (Request $request) {
+    ProcessPodcast::dispatch($request->input('url'));
+    return response()->noContent(202);
+});
+Route::get('/podcasts', function () {
+    return Podcast::all();
+});
+`
+	spring := `@RestController
+public class PollController {
+    @GetMapping("/poll")
+    public DeferredResult<Message> poll() {
+        return pending.register(new DeferredResult<>(30000L));
+    }
+
+    @GetMapping("/messages")
+    public List<Message> messages() { return messages.findAll(); }
+}
+`
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		async    []bool
+	}{
+		{"Celery", "reports.py", flask, []bool{true, false}},
+		{"Bull", "routes.js", express, []bool{true, false}},
+		{"Laravel", "routes/api.php", laravel, []bool{true, false}},
+		{"Spring", "PollController.java", spring, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.async) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.async))
+			}
+			for i, ep := range endpoints {
+				if ep.Async != tt.async[i] {
+					t.Errorf("%s %s async = %v, want %v", ep.Method, ep.Path, ep.Async, tt.async[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDetectIdempotencyAndCaching verifies the derived idempotent and cacheable flags
+func TestDetectIdempotencyAndCaching(t *testing.T) {
+	express := `router.get("/products", (req, res) => {
+  res.set('Cache-Control', 'public, max-age=300')
+  res.json(products)
+})
+
+router.get("/cart", (req, res) => {
+  res.set('Cache-Control', 'no-store')
+  res.json(cart)
+})
+
+router.post("/payments", (req, res) => {
+  const key = req.headers['idempotency-key']
+  res.json(charge(key))
+})
+
+router.post("/orders", (req, res) => {
+  res.json(order)
+})
+
+router.delete("/orders/:id", (req, res) => {
+  res.sendStatus(204)
+})
+`
+	spring := `@RestController
+public class CatalogController {
+    @Cacheable("catalog")
+    @GetMapping("/catalog")
+    public List<Item> catalog() { return items; }
+
+    @PostMapping("/catalog")
+    public Item add() { return null; }
+}
+`
+	tests := []struct {
+		name       string
+		filePath   string
+		content    string
+		idempotent []bool
+		cacheable  []bool
+	}{
+		{"Express", "routes.js", express, []bool{true, true, true, false, true}, []bool{true, false, false, false, false}},
+		{"Spring", "CatalogController.java", spring, []bool{true, false}, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.idempotent) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.idempotent))
+			}
+			for i, ep := range endpoints {
+				if ep.Idempotent != tt.idempotent[i] || ep.Cacheable != tt.cacheable[i] {
+					t.Errorf("%s %s idempotent=%v cacheable=%v, want %v %v", ep.Method, ep.Path, ep.Idempotent, ep.Cacheable, tt.idempotent[i], tt.cacheable[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDetectInputValidation verifies validated input detection and model names
+func TestDetectInputValidation(t *testing.T) {
+	fastapi := `@app.post("/users")
+def create_user(user: UserCreate, db: Session = Depends(get_db)):
+    return db.add(user)
+
+@app.get("/users/{user_id}")
+def get_user(user_id: int, request: Request):
+    return users[user_id]
+
+@app.get("/orders/{order_id}")
+def get_order(order_id: UUID, total: Decimal, since: datetime.date, tags: Optional[List[str]] = None):
+    return orders[order_id]
+`
+	spring := `@RestController
+public class UserController {
+    @PostMapping("/users")
+    public User create(@Valid @RequestBody CreateUserRequest body) { return null; }
+
+    @PutMapping("/users/{id}")
+    public User update(@PathVariable Long id, @RequestBody User body) { return null; }
+}
+`
+	express := `router.post('/signup', body('email').isEmail(), body('password').isLength({ min: 8 }), signup)
+router.post('/profile', checkSchema(profileSchema), saveProfile)
+router.get('/health', (req, res) => res.send('ok'))
+`
+	tests := []struct {
+		name      string
+		filePath  string
+		content   string
+		validated []bool
+		models    []string
+	}{
+		{"FastAPI", "main.py", fastapi, []bool{true, false, false}, []string{"UserCreate", "", ""}},
+		{"Spring", "UserController.java", spring, []bool{true, false}, []string{"CreateUserRequest", ""}},
+		{"ExpressValidator", "routes.js", express, []bool{true, true, false}, []string{"", "profileSchema", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.validated) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.validated))
+			}
+			for i, ep := range endpoints {
+				if ep.ValidatedInput != tt.validated[i] || ep.InputModel != tt.models[i] {
+					t.Errorf("%s %s validated=%v model=%q, want %v %q", ep.Method, ep.Path, ep.ValidatedInput, ep.InputModel, tt.validated[i], tt.models[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDetectResponseHeaders verifies the response header names handlers set
+func TestDetectResponseHeaders(t *testing.T) {
+	express := `router.get("/users", (req, res) => {
+  res.set('X-Total-Count', users.length)
+  res.setHeader('X-Page', page)
+  res.json(users)
+})
+
+router.get("/export", (req, res) => {
+  res.writeHead(200, { 'Content-Type': 'text/csv', 'Content-Disposition': 'attachment' })
+  res.end(csv)
+})
+
+router.get("/plain", (req, res) => {
+  const auth = req.headers['authorization']
+  res.json({})
+})
+
+router.get("/proxy", async (req, res) => {
+  const headers = new Headers()
+  headers.set('Authorization', token)
+  headers.append('X-Trace-Id', traceId)
+  const upstream = await fetch(url, { headers })
+  res.json(await upstream.json())
+})
+`
+	gin := `package main
+
+func routes(r *gin.Engine) {
+	r.GET("/items", func(c *gin.Context) {
+		c.Header("X-Request-Id", requestID(c))
+		c.Writer.Header().Set("X-Rate-Limit", "100")
+		c.JSON(200, items)
+	})
+	r.GET("/me", func(c *gin.Context) {
+		c.Set("userID", currentUser(c))
+		c.JSON(200, me)
+	})
+}
+`
+	spring := `@RestController
+public class FileController {
+    @GetMapping("/files/{id}")
+    public ResponseEntity<Resource> download(@PathVariable String id) {
+        return ResponseEntity.ok()
+            .header("Content-Disposition", "attachment")
+            .eTag(version)
+            .body(resource);
+    }
+}
+`
+	fastapi := `@app.get("/reports")
+def reports(response: Response):
+    response.headers["X-Report-Version"] = "2"
+    return JSONResponse(data, headers={"X-Generated-At": now, "x-report-version": "2"})
+
+@app.get("/mirror")
+def mirror():
+    upstream = requests.get(MIRROR_URL, headers={"Authorization": token})
+    return upstream.json()
+`
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     [][]string
+	}{
+		{"Express", "routes.js", express, [][]string{
+			{"X-Total-Count", "X-Page"}, {"Content-Type", "Content-Disposition"}, nil, nil,
+		}},
+		{"Gin", "main.go", gin, [][]string{{"X-Request-Id", "X-Rate-Limit"}, nil}},
+		{"Spring", "FileController.java", spring, [][]string{{"Content-Disposition", "ETag"}}},
+		{"FastAPI", "main.py", fastapi, [][]string{{"X-Report-Version", "X-Generated-At"}, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, ep := range endpoints {
+				if !reflect.DeepEqual(ep.ResponseHeaders, tt.want[i]) {
+					t.Errorf("%s %s response headers = %v, want %v", ep.Method, ep.Path, ep.ResponseHeaders, tt.want[i])
+				}
+			}
+		})
+	}
+}
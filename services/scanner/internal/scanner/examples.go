@@ -0,0 +1,220 @@
+// Package scanner - Example payloads mined from test files
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxExamplesPerEndpoint bounds how many examples are attached to one endpoint
+const maxExamplesPerEndpoint = 3
+
+// Example is a request/response pair taken from a test that calls an endpoint
+type Example struct {
+	Request  string `json:"request,omitempty"`
+	Response string `json:"response,omitempty"`
+	Source   string `json:"source"` // test file and line
+}
+
+// examplePatterns locate test requests and their payloads for one language
+type examplePatterns struct {
+	call     *regexp.Regexp // captures method and path
+	request  *regexp.Regexp // ends where the request body literal starts
+	response *regexp.Regexp // ends where the expected response literal starts
+}
+
+var examplePatternsByExt = map[string]examplePatterns{
+	// pytest + TestClient: client.post("/users", json={...}); assert r.json() == {...}
+	".py": {
+		call:     regexp.MustCompile(`\b\w+\.(get|post|put|patch|delete)\s*\(\s*f?["'](/[^"']*)["']`),
+		request:  regexp.MustCompile(`\b(?:json|data)\s*=\s*`),
+		response: regexp.MustCompile(`\.json\(\)\s*==\s*`),
+	},
+	// supertest: request(app).post('/users').send({...}).expect(201, {...})
+	".js": {
+		call:     regexp.MustCompile(`\.(get|post|put|patch|delete)\s*\(\s*["'\x60](/[^"'\x60]*)["'\x60]\s*\)`),
+		request:  regexp.MustCompile(`\.send\(\s*`),
+		response: regexp.MustCompile(`(?:\.expect\(\s*\d+\s*,\s*|\.toEqual\(\s*)`),
+	},
+	// httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{...}`))
+	".go": {
+		call:     regexp.MustCompile(`NewRequest(?:WithContext)?\s*\((?:\s*\w+\s*,)?\s*(?:http\.Method(\w+)|"(\w+)")\s*,\s*"(/[^"]*)"`),
+		request:  regexp.MustCompile(`strings\.NewReader\(\s*`),
+		response: regexp.MustCompile(`JSONEq\(\s*t\s*,\s*`),
+	},
+}
+
+func init() {
+	examplePatternsByExt[".ts"] = examplePatternsByExt[".js"]
+}
+
+// isTestFile reports whether a path looks like a test by name or directory
+func isTestFile(path string) bool {
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	switch {
+	case strings.HasSuffix(name, "_test"), strings.HasPrefix(name, "test_"),
+		strings.HasSuffix(name, ".test"), strings.HasSuffix(name, ".spec"),
+		strings.HasSuffix(name, "Test"), strings.HasSuffix(name, "Tests"):
+		return true
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		switch dir {
+		case "test", "tests", "__tests__", "spec":
+			return true
+		}
+	}
+	return false
+}
+
+// testRequest is one request made by a test
+type testRequest struct {
+	method, path string
+	example      Example
+}
+
+// extractTestRequests finds requests made by a test file and their payloads
+func extractTestRequests(relPath, content string) []testRequest {
+	patterns, ok := examplePatternsByExt[strings.ToLower(filepath.Ext(relPath))]
+	if !ok {
+		return nil
+	}
+	lines := sourceLines(content)
+
+	type call struct {
+		line         int
+		method, path string
+	}
+	var calls []call
+	for i, line := range lines {
+		m := patterns.call.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// The path is the last group; the method is the first non-empty one
+		method := ""
+		for _, g := range m[1 : len(m)-1] {
+			if g != "" {
+				method = strings.ToUpper(g)
+				break
+			}
+		}
+		calls = append(calls, call{line: i, method: method, path: m[len(m)-1]})
+	}
+
+	var found []testRequest
+	for n, c := range calls {
+		// A request's payloads sit between it and the next request
+		end := min(c.line+10, len(lines))
+		if n+1 < len(calls) {
+			end = min(end, max(calls[n+1].line, c.line+1))
+		}
+		window := strings.Join(lines[c.line:end], "\n")
+
+		ex := Example{Source: fmt.Sprintf("%s:%d", relPath, c.line+1)}
+		if loc := patterns.request.FindStringIndex(window); loc != nil {
+			ex.Request = payloadLiteral(window[loc[1]:])
+		}
+		if loc := patterns.response.FindStringIndex(window); loc != nil {
+			ex.Response = payloadLiteral(window[loc[1]:])
+		}
+		if ex.Request == "" && ex.Response == "" {
+			continue
+		}
+		found = append(found, testRequest{method: c.method, path: c.path, example: ex})
+	}
+	return found
+}
+
+// payloadLiteral returns the object/array literal or raw string at the start of s
+func payloadLiteral(s string) string {
+	if s == "" {
+		return ""
+	}
+	switch s[0] {
+	case '`':
+		if end := strings.IndexByte(s[1:], '`'); end >= 0 {
+			return s[1 : end+1]
+		}
+		return ""
+	case '{', '[':
+	default:
+		return ""
+	}
+
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return s[:i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// pathMatches reports whether a concrete request path fits a route template
+func pathMatches(template, concrete string) bool {
+	concrete, _, _ = strings.Cut(concrete, "?")
+	t := strings.Split(strings.Trim(template, "/"), "/")
+	c := strings.Split(strings.Trim(concrete, "/"), "/")
+	if len(t) != len(c) {
+		return false
+	}
+	for i := range t {
+		if t[i] == c[i] {
+			continue
+		}
+		loc := pathParamPattern.FindStringIndex(t[i])
+		if loc == nil || loc[0] != 0 || loc[1] != len(t[i]) || c[i] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// attachExamples scans the test files among files and links the payloads they
+// send to the matching endpoints. Only the first few examples are kept.
+func attachExamples(rootDir string, files []string, found []Endpoint) {
+	for _, filePath := range files {
+		relPath, _ := filepath.Rel(rootDir, filePath)
+		if !isTestFile(relPath) {
+			continue
+		}
+		content, err := readFile(filePath)
+		if err != nil {
+			continue
+		}
+
+		for _, req := range extractTestRequests(relPath, string(content)) {
+			for i := range found {
+				ep := &found[i]
+				if ep.Kind == KindClientCall || len(ep.Examples) >= maxExamplesPerEndpoint {
+					continue
+				}
+				if !sameMethod(ep.Method, req.method) && !sameMethod(ep.Method, "ANY") && !sameMethod(ep.Method, "ALL") {
+					continue
+				}
+				if pathMatches(ep.Path, req.path) {
+					ep.Examples = append(ep.Examples, req.example)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAttachExamplesFromTests verifies a test hitting /users is linked to the endpoint
+func TestAttachExamplesFromTests(t *testing.T) {
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"app/users.py": `from fastapi import APIRouter
+router = APIRouter()
+
+@router.post("/users")
+async def create_user(user: UserIn):
+    return user
+
+@router.get("/users/{user_id}")
+async def get_user(user_id: int):
+    return {}
+`,
+		"tests/test_users.py": `def test_create_user(client):
+    resp = client.post("/users", json={"name": "Ada", "roles": ["admin"]})
+    assert resp.status_code == 201
+    assert resp.json() == {"id": 1, "name": "Ada"}
+
+def test_get_user(client):
+    resp = client.get("/users/1")
+    assert resp.json() == {
+        "id": 1,
+        "name": "Ada",
+    }
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(rootDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	scan := func(scanID string) []Endpoint {
+		mu.Lock()
+		scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+		endpoints[scanID] = []Endpoint{}
+		mu.Unlock()
+		scanCheckout(scanID, rootDir)
+		eps, _ := GetEndpoints(scanID)
+		return eps
+	}
+
+	// Off by default
+	for _, ep := range scan("examples-off") {
+		if len(ep.Examples) > 0 {
+			t.Errorf("%s %s has examples with extraction disabled", ep.Method, ep.Path)
+		}
+	}
+
+	config.ExtractExamples = true
+	eps := scan("examples-on")
+	if len(eps) != 2 {
+		t.Fatalf("found %d endpoints, want 2: %+v", len(eps), eps)
+	}
+
+	create := eps[0]
+	if create.Method != "POST" || len(create.Examples) != 1 {
+		t.Fatalf("POST /users examples = %+v, want 1", create.Examples)
+	}
+	want := Example{
+		Request:  `{"name": "Ada", "roles": ["admin"]}`,
+		Response: `{"id": 1, "name": "Ada"}`,
+		Source:   "tests/test_users.py:2",
+	}
+	if create.Examples[0] != want {
+		t.Errorf("POST /users example = %+v, want %+v", create.Examples[0], want)
+	}
+
+	get := eps[1]
+	if len(get.Examples) != 1 || get.Examples[0].Request != "" || get.Examples[0].Response == "" {
+		t.Errorf("GET /users/{user_id} examples = %+v, want one response example", get.Examples)
+	}
+}
+
+// TestIsTestFile verifies test files are recognised by name and directory
+func TestIsTestFile(t *testing.T) {
+	tests := map[string]bool{
+		"tests/test_users.py":               true,
+		"handlers/users_test.go":            true,
+		"src/users.spec.ts":                 true,
+		"src/UserControllerTest.java":       true,
+		"__tests__/users.js":                true,
+		"app/latest.py":                     false,
+		"src/main/java/UserController.java": false,
+	}
+	for path, want := range tests {
+		if got := isTestFile(path); got != want {
+			t.Errorf("isTestFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
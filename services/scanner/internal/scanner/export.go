@@ -0,0 +1,321 @@
+// Package scanner - Export formats for scanned endpoints
+package scanner
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exportTitle names an exported document after the scanned repository
+func exportTitle(status *ScanStatus) string {
+	name := strings.TrimSuffix(path.Base(strings.TrimRight(status.URL, "/")), ".git")
+	if name == "" || name == "." || name == "/" {
+		name = status.ID
+	}
+	return name + " API"
+}
+
+// exportParamPattern captures the name of :id, {id}, {id:[0-9]+}, <id> and <int:id> parameters
+var exportParamPattern = regexp.MustCompile(`:(\w+)|\{(\w+)(?::[^}]*)?\}|<(?:\w+:)?(\w+)>`)
+
+// templatePath rewrites path parameters with format, e.g. "{%s}" or ":%s",
+// returning the rewritten path and the parameter names in order
+func templatePath(p, format string) (string, []string) {
+	out, params := rewriteParams(p, format)
+	if !strings.HasPrefix(out, "/") {
+		out = "/" + out
+	}
+	return out, params
+}
+
+// rewriteParams renders each path parameter with format, dropping type
+// converters and constraints, and returns the parameter names in order
+func rewriteParams(p, format string) (string, []string) {
+	var params []string
+	out := exportParamPattern.ReplaceAllStringFunc(p, func(m string) string {
+		sub := exportParamPattern.FindStringSubmatch(m)
+		name := sub[1] + sub[2] + sub[3]
+		params = append(params, name)
+		return fmt.Sprintf(format, name)
+	})
+	return out, params
+}
+
+// BuildOpenAPI renders endpoints as an OpenAPI 3.0 document. Routes without a
+// specific method (ANY) are listed under the x-any-method path item extension;
+// client calls and RPC procedures are left out.
+func BuildOpenAPI(status *ScanStatus, eps []Endpoint) map[string]any {
+	paths := make(map[string]any)
+	schemes := make(map[string]*AuthScheme)
+	schemeScopes := make(map[string][]string)
+
+	for _, ep := range eps {
+		if ep.Kind == KindClientCall || ep.Kind == KindProcedure || isEventKind(ep.Kind) {
+			continue
+		}
+		p, params := templatePath(ep.Path, "{%s}")
+
+		var parameters []map[string]any
+		for _, name := range params {
+			parameters = append(parameters, map[string]any{
+				"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, name := range ep.QueryParams {
+			parameters = append(parameters, map[string]any{
+				"name": name, "in": "query", "schema": map[string]any{"type": "string"},
+			})
+		}
+
+		responses := make(map[string]any)
+		for _, code := range ep.ResponseCodes {
+			responses[strconv.Itoa(code)] = map[string]any{"description": httpStatusText(code)}
+		}
+		if len(responses) == 0 {
+			responses["200"] = map[string]any{"description": "OK"}
+		}
+		if len(ep.ResponseHeaders) > 0 {
+			headers := make(map[string]any)
+			for _, name := range ep.ResponseHeaders {
+				headers[name] = map[string]any{"schema": map[string]any{"type": "string"}}
+			}
+			// Headers set by the handler go on its successful responses
+			for code, response := range responses {
+				if n, _ := strconv.Atoi(code); n < 400 {
+					response.(map[string]any)["headers"] = headers
+				}
+			}
+		}
+
+		op := map[string]any{
+			"operationId": ep.ID,
+			"tags":        ep.Tags,
+			"responses":   responses,
+			"x-source":    fmt.Sprintf("%s:%d", ep.FilePath, ep.LineNumber),
+		}
+		if ep.Summary != "" {
+			op["summary"] = ep.Summary
+		}
+		if ep.Description != "" {
+			op["description"] = ep.Description
+		}
+		if len(parameters) > 0 {
+			op["parameters"] = parameters
+		}
+		if ep.Deprecated {
+			op["deprecated"] = true
+		}
+		if ep.EnvRestricted {
+			op["x-env-restricted"] = ep.EnvCondition
+		}
+		if ep.Auth != nil {
+			// Only OAuth2 requirements list scopes
+			name := securitySchemeName(ep.Auth)
+			scopes := []string{}
+			if ep.Auth.Type == "oauth2" {
+				scopes = append(scopes, ep.RequiredScopes...)
+				for _, scope := range scopes {
+					if !containsString(schemeScopes[name], scope) {
+						schemeScopes[name] = append(schemeScopes[name], scope)
+					}
+				}
+			}
+			if _, ok := schemes[name]; !ok {
+				schemes[name] = ep.Auth
+			}
+			op["security"] = []map[string][]string{{name: scopes}}
+		}
+
+		method := strings.ToLower(ep.Method)
+		if method == "any" || method == "all" {
+			method = "x-any-method"
+		}
+		item, _ := paths[p].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[p] = item
+		}
+		if _, exists := item[method]; !exists {
+			item[method] = op
+		}
+	}
+
+	info := map[string]any{"title": exportTitle(status), "version": "1.0.0"}
+	if status.Commit != "" {
+		info["description"] = "Generated from commit " + status.Commit
+	}
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info":    info,
+		"paths":   paths,
+	}
+	if len(schemes) > 0 {
+		objects := make(map[string]any, len(schemes))
+		for name, scheme := range schemes {
+			objects[name] = securitySchemeObject(scheme, schemeScopes[name])
+		}
+		doc["components"] = map[string]any{"securitySchemes": objects}
+	}
+	return doc
+}
+
+// httpStatusText describes a response code, falling back to the code itself
+func httpStatusText(code int) string {
+	if text := http.StatusText(code); text != "" {
+		return text
+	}
+	return strconv.Itoa(code)
+}
+
+// postmanSchema is the Postman collection format written by BuildPostman
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// BuildPostman renders endpoints as a Postman v2.1 collection with one folder
+// per tag. Requests use a {{baseUrl}} variable.
+func BuildPostman(status *ScanStatus, eps []Endpoint) map[string]any {
+	folders := make(map[string][]any)
+	var order []string
+
+	for _, ep := range eps {
+		if ep.Kind == KindClientCall || ep.Kind == KindProcedure || isEventKind(ep.Kind) {
+			continue
+		}
+		p, _ := templatePath(ep.Path, ":%s")
+		method := strings.ToUpper(ep.Method)
+		if method == "ANY" || method == "ALL" {
+			method = "GET"
+		}
+
+		name := ep.Summary
+		if name == "" {
+			name = ep.Method + " " + p
+		}
+		request := map[string]any{
+			"name": name,
+			"request": map[string]any{
+				"method": method,
+				"url": map[string]any{
+					"raw":  "{{baseUrl}}" + p,
+					"host": []string{"{{baseUrl}}"},
+					"path": strings.Split(strings.TrimPrefix(p, "/"), "/"),
+				},
+			},
+		}
+
+		tag := "api"
+		if len(ep.Tags) > 0 {
+			tag = ep.Tags[0]
+		}
+		if _, seen := folders[tag]; !seen {
+			order = append(order, tag)
+		}
+		folders[tag] = append(folders[tag], request)
+	}
+
+	items := make([]any, 0, len(order))
+	for _, tag := range order {
+		items = append(items, map[string]any{"name": tag, "item": folders[tag]})
+	}
+	return map[string]any{
+		"info":     map[string]any{"name": exportTitle(status), "schema": postmanSchema},
+		"item":     items,
+		"variable": []any{map[string]any{"key": "baseUrl", "value": "http://localhost"}},
+	}
+}
+
+// WriteMarkdown renders endpoints as a Markdown reference grouped by tag.
+// Sections are written to w as they are rendered, not built up in memory.
+func WriteMarkdown(w io.Writer, status *ScanStatus, eps []Endpoint) error {
+	byTag := make(map[string][]Endpoint)
+	for _, ep := range eps {
+		if ep.Kind == KindClientCall {
+			continue
+		}
+		tag := "api"
+		if len(ep.Tags) > 0 {
+			tag = ep.Tags[0]
+		}
+		byTag[tag] = append(byTag[tag], ep)
+	}
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "# %s\n\n", exportTitle(status))
+	fmt.Fprintf(b, "Repository: %s\n", status.URL)
+	if status.Commit != "" {
+		fmt.Fprintf(b, "Commit: `%s`\n", status.Commit)
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(b, "\n## %s\n\n| Method | Path | Summary | Source |\n|--------|------|---------|--------|\n", tag)
+		for _, ep := range byTag[tag] {
+			summary := ep.Summary
+			if ep.Deprecated {
+				summary = strings.TrimSpace("**Deprecated** " + summary)
+			}
+			fmt.Fprintf(b, "| %s | `%s` | %s | %s:%d |\n", ep.Method, ep.Path, strings.ReplaceAll(summary, "|", `\|`), ep.FilePath, ep.LineNumber)
+		}
+	}
+
+	return b.Flush()
+}
+
+// Entries of the export bundle
+const (
+	BundleOpenAPI   = "openapi.json"
+	BundlePostman   = "postman_collection.json"
+	BundleMarkdown  = "API.md"
+	BundleEndpoints = "endpoints.json"
+)
+
+// WriteExportBundle streams a zip of every export format for a scan to w.
+// Each entry is compressed as it is written, so the archive isn't buffered.
+func WriteExportBundle(w io.Writer, status *ScanStatus, eps []Endpoint) error {
+	zw := zip.NewWriter(w)
+
+	writeJSON := func(name string, v any) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	if err := writeJSON(BundleOpenAPI, BuildOpenAPI(status, eps)); err != nil {
+		return err
+	}
+	if err := writeJSON(BundlePostman, BuildPostman(status, eps)); err != nil {
+		return err
+	}
+	f, err := zw.Create(BundleMarkdown)
+	if err != nil {
+		return err
+	}
+	if err := WriteMarkdown(f, status, eps); err != nil {
+		return err
+	}
+	if err := writeJSON(BundleEndpoints, map[string]any{
+		"scan_id":   status.ID,
+		"commit":    status.Commit,
+		"count":     len(eps),
+		"endpoints": eps,
+	}); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestWriteExportBundle verifies the download zip holds every export format
+func TestWriteExportBundle(t *testing.T) {
+	status := &ScanStatus{ID: "scan-1", URL: "https://github.com/acme/shop.git", Status: "completed"}
+	eps := []Endpoint{
+		{ID: "a", Method: "GET", Path: "/users/:id", Summary: "Get user", Tags: []string{"users"}, FilePath: "routes/users.js", LineNumber: 3, ResponseCodes: []int{200, 404}},
+		{ID: "b", Method: "POST", Path: "/orders/<int:order_id>", Tags: []string{"orders"}, FilePath: "orders/views.py", LineNumber: 10, QueryParams: []string{"dry_run"}},
+		{ID: "c", Method: "GET", Path: "/api/items", Kind: KindClientCall, FilePath: "src/App.vue", LineNumber: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExportBundle(&buf, status, eps); err != nil {
+		t.Fatalf("WriteExportBundle: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+
+	files := make(map[string]string)
+	var names []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+		names = append(names, f.Name)
+	}
+	want := []string{BundleOpenAPI, BundlePostman, BundleMarkdown, BundleEndpoints}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("zip entries = %v, want %v", names, want)
+	}
+
+	var spec struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(files[BundleOpenAPI]), &spec); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	if spec.OpenAPI == "" || spec.Paths["/users/{id}"]["get"] == nil || spec.Paths["/orders/{order_id}"]["post"] == nil {
+		t.Errorf("openapi paths = %v", spec.Paths)
+	}
+	if _, ok := spec.Paths["/api/items"]; ok {
+		t.Error("client calls should not be exported as operations")
+	}
+
+	if !strings.Contains(files[BundlePostman], "{{baseUrl}}/users/:id") {
+		t.Errorf("postman collection missing templated request:\n%s", files[BundlePostman])
+	}
+	if !strings.Contains(files[BundleMarkdown], "## users") || !strings.Contains(files[BundleMarkdown], "`/users/:id`") {
+		t.Errorf("markdown missing users section:\n%s", files[BundleMarkdown])
+	}
+
+	var raw struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(files[BundleEndpoints]), &raw); err != nil || raw.Count != len(eps) {
+		t.Errorf("endpoints.json count = %d (%v), want %d", raw.Count, err, len(eps))
+	}
+}
@@ -0,0 +1,432 @@
+// Package scanner - Pluggable per-language extractors
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LanguageExtractor detects API endpoints for one language. Each language
+// lives in its own lang_*.go file and registers itself at init.
+type LanguageExtractor interface {
+	// Name identifies the language in logs
+	Name() string
+	// Extensions lists the lowercase file extensions handled, with the dot
+	Extensions() []string
+	// Indicators are the Stage 1 patterns marking a file as likely API code
+	Indicators() []*regexp.Regexp
+	// Keywords are literals of which every indicator needs at least one;
+	// files containing none are rejected before running the indicators
+	Keywords() []string
+	// Extract returns the endpoints declared in a file (Stage 2)
+	Extract(filePath, content string) []Endpoint
+}
+
+// FileNameExtractor is implemented by extractors that claim files by name
+// rather than extension, such as declarative route configs
+type FileNameExtractor interface {
+	// FileNames lists the lowercase base names handled
+	FileNames() []string
+}
+
+// FileLoader reads a repository file by its slash-separated path relative
+// to the repository root
+type FileLoader func(relPath string) ([]byte, error)
+
+// RefExtractor is implemented by extractors whose files reference other
+// files of the repository, such as OpenAPI documents split with $ref. load
+// is nil when a file is scanned on its own.
+type RefExtractor interface {
+	ExtractRefs(filePath, content string, load FileLoader) []Endpoint
+}
+
+// extractors holds the registered extractors by extension; fileExtractors
+// by base name, which takes precedence
+var (
+	extractors     = make(map[string]LanguageExtractor)
+	fileExtractors = make(map[string]LanguageExtractor)
+)
+
+// RegisterExtractor makes an extractor responsible for its extensions and
+// file names, replacing any extractor previously registered for them
+func RegisterExtractor(x LanguageExtractor) {
+	for _, ext := range x.Extensions() {
+		extractors[ext] = x
+		supportedExtensions[ext] = true
+	}
+	if named, ok := x.(FileNameExtractor); ok {
+		for _, name := range named.FileNames() {
+			fileExtractors[name] = x
+		}
+	}
+}
+
+// extractorFor returns the extractor for a file, by name then extension.
+// Extractors disabled by DISABLED_LANGUAGES claim no files.
+func extractorFor(filePath string) (LanguageExtractor, bool) {
+	if x, ok := fileExtractors[strings.ToLower(filepath.Base(filePath))]; ok && !languageDisabled(x) {
+		return x, true
+	}
+	if x, ok := extractors[extOf(filePath)]; ok && !languageDisabled(x) {
+		return x, true
+	}
+	return nil, false
+}
+
+// languageDisabled reports whether an extractor is turned off by name
+func languageDisabled(x LanguageExtractor) bool {
+	return config.DisabledLanguages[strings.ToLower(x.Name())]
+}
+
+// handledFiles lists the extensions and file names an extractor claims
+func handledFiles(x LanguageExtractor) []string {
+	handled := x.Extensions()
+	if named, ok := x.(FileNameExtractor); ok {
+		handled = append(handled[:len(handled):len(handled)], named.FileNames()...)
+	}
+	return handled
+}
+
+// registeredExtractors returns one entry per registered extractor, sorted by name
+func registeredExtractors() []LanguageExtractor {
+	seen := make(map[LanguageExtractor]bool)
+	var list []LanguageExtractor
+	for _, x := range extractors {
+		if !seen[x] {
+			seen[x] = true
+			list = append(list, x)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// DefaultMaxLineLength is the longest line extractors look at. Longer lines
+// (minified bundles, inlined data) are blanked rather than failing the file.
+const DefaultMaxLineLength = 64 * 1024
+
+// sourceLines splits file content into lines, blanking any longer than the
+// configured maximum so line numbers stay correct
+func sourceLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if config.MaxLineLength > 0 && len(line) > config.MaxLineLength {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// longLines counts the lines sourceLines blanks
+func longLines(content string) int {
+	if config.MaxLineLength <= 0 {
+		return 0
+	}
+	n := 0
+	for _, line := range strings.Split(content, "\n") {
+		if len(line) > config.MaxLineLength {
+			n++
+		}
+	}
+	return n
+}
+
+// lineParser turns a pattern match on one line into a method and path
+type lineParser func(line string, matches []string) (method, path string, ok bool)
+
+// regexExtractor is a line-oriented extractor driven by regex patterns.
+// The first pattern matching a line wins.
+type regexExtractor struct {
+	name           string
+	extensions     []string
+	indicators     []*regexp.Regexp
+	keywords       []string
+	patterns       []*regexp.Regexp
+	parse          lineParser
+	allowEmptyPath bool // e.g. NestJS @Get() inherits the controller path
+	joinDecorators bool // match decorators split over several lines as one line
+
+	// extra runs declarations that span lines or expand to several routes
+	extra func(filePath string, lines []string) []Endpoint
+	// refine adjusts the line matches with file-level context, e.g. a class
+	// prefix, and may expand a match into several endpoints
+	refine func(filePath string, found []Endpoint, lines []string) []Endpoint
+	// annotations applies routes documented in comments, e.g. swagger-jsdoc
+	// blocks. It runs after commented-out routes are dropped.
+	annotations func(filePath string, found []Endpoint, lines []string) []Endpoint
+}
+
+func (x *regexExtractor) Name() string                 { return x.name }
+func (x *regexExtractor) Extensions() []string         { return x.extensions }
+func (x *regexExtractor) Indicators() []*regexp.Regexp { return x.indicators }
+func (x *regexExtractor) Keywords() []string           { return x.keywords }
+
+// Extract implements LanguageExtractor
+func (x *regexExtractor) Extract(filePath, content string) []Endpoint {
+	lines := sourceLines(content)
+	found := x.extractLines(filePath, lines)
+	if x.refine != nil {
+		found = x.refine(filePath, found, lines)
+	}
+	if x.extra != nil {
+		found = append(found, x.extra(filePath, lines)...)
+	}
+	found = excludeCommentedRoutes(extOf(filePath), found, lines)
+	if x.annotations != nil {
+		found = x.annotations(filePath, found, lines)
+	}
+	markConditionalRoutes(extOf(filePath), found, lines)
+	return found
+}
+
+// extractLines applies the line patterns
+func (x *regexExtractor) extractLines(filePath string, lines []string) []Endpoint {
+	var found []Endpoint
+
+	for i, line := range lines {
+		lineNum := i + 1
+		if x.joinDecorators {
+			line = decoratorLine(lines, i)
+		}
+		for _, pattern := range x.patterns {
+			matches := pattern.FindStringSubmatch(line)
+			if len(matches) < 2 {
+				continue
+			}
+
+			method, path, ok := x.parse(line, matches)
+			if !ok {
+				continue
+			}
+
+			// Skip invalid paths (empty paths are valid for decorators like @Get() in NestJS)
+			if path == "" && !x.allowEmptyPath {
+				continue
+			}
+
+			ep := Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, lineNum),
+				Path:       path,
+				Method:     method,
+				FilePath:   filePath,
+				LineNumber: lineNum,
+			}
+			if template, how, ok := dynamicRoute(line, path); ok {
+				ep.Path = template
+				ep.Dynamic = true
+				ep.Warnings = append(ep.Warnings, dynamicPathWarning(how))
+			}
+			ep.Tags = []string{extractTag(filePath, ep.Path)}
+			found = append(found, ep)
+
+			// Break after finding first match to avoid duplicate endpoints from multiple patterns
+			break
+		}
+	}
+
+	return found
+}
+
+// maxDecoratorLines bounds how far a call's arguments are followed
+const maxDecoratorLines = 20
+
+// decoratorLine returns the decorator starting at lines[i] joined with its
+// continuation lines, e.g.
+//
+//	@app.get(
+//	    "/users",
+//	    tags=["u"])
+//
+// becomes `@app.get( "/users", tags=["u"])`. Other lines are returned as is.
+func decoratorLine(lines []string, i int) string {
+	if !strings.HasPrefix(strings.TrimSpace(lines[i]), "@") {
+		return lines[i]
+	}
+	return balancedLine(lines, i)
+}
+
+// decoratorBlock returns the decorators stacked directly above and below
+// lines[i], each joined onto one line
+func decoratorBlock(lines []string, i int) []string {
+	start := i
+	for start > 0 {
+		d := decoratorEndingAt(lines, start-1)
+		if d < 0 {
+			break
+		}
+		start = d
+	}
+
+	var block []string
+	for j := start; j < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[j]), "@"); j++ {
+		block = append(block, strings.TrimSpace(balancedLine(lines, j)))
+		for depth := parenDepth(lines[j]); depth > 0 && j+1 < len(lines); {
+			j++
+			depth += parenDepth(lines[j])
+		}
+	}
+	return block
+}
+
+// decoratorEndingAt returns the index of the decorator whose (possibly
+// multi-line) call ends at lines[end], or -1
+func decoratorEndingAt(lines []string, end int) int {
+	for d := end; d >= 0 && d >= end-maxDecoratorLines; d-- {
+		if !strings.HasPrefix(strings.TrimSpace(lines[d]), "@") {
+			continue
+		}
+		last := d
+		for depth := parenDepth(lines[d]); depth > 0 && last+1 < len(lines); {
+			last++
+			depth += parenDepth(lines[last])
+		}
+		if last == end {
+			return d
+		}
+		return -1
+	}
+	return -1
+}
+
+// balancedLine returns lines[i] joined with the lines that follow it until
+// its parentheses balance
+func balancedLine(lines []string, i int) string {
+	line := lines[i]
+	depth := parenDepth(line)
+	for j := i + 1; depth > 0 && j < len(lines) && j <= i+maxDecoratorLines; j++ {
+		line += " " + strings.TrimSpace(lines[j])
+		depth += parenDepth(lines[j])
+	}
+	return line
+}
+
+// parenDepth returns the net number of parentheses a line opens, ignoring
+// any inside string literals
+func parenDepth(line string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+	}
+	return depth
+}
+
+var (
+	templatePlaceholderPattern = regexp.MustCompile(`\$\{([^}]*)\}`) // JS `/users/${id}`
+	formatPlaceholderPattern   = regexp.MustCompile(`\{([^{}]*)\}`)  // Python f"/users/{id}", "/users/{}".format(id)
+	placeholderNamePattern     = regexp.MustCompile(`\w+`)
+)
+
+// dynamicRoute recognises a path literal that is interpolated at runtime
+// (JS template literal, Python f-string or str.format) and returns it with
+// each placeholder rewritten as a {name} parameter
+func dynamicRoute(line, path string) (string, string, bool) {
+	idx := strings.Index(line, path)
+	if idx < 1 {
+		return path, "", false
+	}
+	quote, rest := line[idx-1], line[idx+len(path):]
+	switch {
+	case quote == '`' && templatePlaceholderPattern.MatchString(path):
+		return replacePlaceholders(path, templatePlaceholderPattern), "template literal", true
+	case (quote == '"' || quote == '\'') && fStringPrefix(line[:idx-1]):
+		return replacePlaceholders(path, formatPlaceholderPattern), "f-string", true
+	case (quote == '"' || quote == '\'') && strings.HasPrefix(strings.TrimLeft(rest[min(1, len(rest)):], " "), ".format("):
+		return replacePlaceholders(path, formatPlaceholderPattern), "str.format", true
+	}
+	return path, "", false
+}
+
+// fStringPrefix reports whether before ends in a Python f-string prefix
+// (f, fr or rf, in any case) starting a token of its own
+func fStringPrefix(before string) bool {
+	for k := min(2, len(before)); k >= 1; k-- {
+		switch strings.ToLower(before[len(before)-k:]) {
+		case "f", "fr", "rf":
+			start := len(before) - k
+			return start == 0 || !isWordByte(before[start-1])
+		}
+	}
+	return false
+}
+
+// replacePlaceholders rewrites each placeholder matched by pattern as a
+// {name} parameter, naming it after the interpolated identifier
+func replacePlaceholders(path string, pattern *regexp.Regexp) string {
+	n := 0
+	return pattern.ReplaceAllStringFunc(path, func(m string) string {
+		n++
+		return "{" + placeholderName(pattern.FindStringSubmatch(m)[1], n) + "}"
+	})
+}
+
+// placeholderName names a placeholder after the last identifier of its
+// expression ({user.id} is {id}), ignoring format specs; positional
+// placeholders become param1, param2...
+func placeholderName(expr string, n int) string {
+	expr, _, _ = strings.Cut(expr, "!")
+	expr, _, _ = strings.Cut(expr, ":")
+	if words := placeholderNamePattern.FindAllString(expr, -1); len(words) > 0 {
+		if last := words[len(words)-1]; !isDigits(last) {
+			return last
+		}
+	}
+	return fmt.Sprintf("param%d", n)
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// dynamicPathWarning explains a Dynamic endpoint's path
+func dynamicPathWarning(how string) string {
+	return fmt.Sprintf("path built with %s at runtime; placeholders are best-effort parameters", how)
+}
+
+// methodPathParser handles patterns capturing (method, path)
+func methodPathParser(line string, matches []string) (string, string, bool) {
+	if len(matches) < 3 {
+		return "", "", false
+	}
+	return strings.ToUpper(matches[1]), matches[2], true
+}
+
+// markConditionalRoutes flags routes whose innermost enclosing block is
+// conditional, and routes only registered in some environments
+func markConditionalRoutes(ext string, found []Endpoint, lines []string) {
+	for i := range found {
+		if _, ok := enclosingCondition(ext, lines, found[i].LineNumber-1); ok {
+			found[i].Conditional = true
+		}
+	}
+	markEnvironmentRoutes(ext, found, lines)
+}
@@ -0,0 +1,175 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// fakeExtractor reports one endpoint per "ROUTE <path>" line
+type fakeExtractor struct {
+	calls int
+}
+
+func (f *fakeExtractor) Name() string         { return "Fake" }
+func (f *fakeExtractor) Extensions() []string { return []string{".fake"} }
+func (f *fakeExtractor) Indicators() []*regexp.Regexp {
+	return []*regexp.Regexp{regexp.MustCompile(`^ROUTE\s`)}
+}
+func (f *fakeExtractor) Keywords() []string { return []string{"ROUTE"} }
+
+func (f *fakeExtractor) Extract(filePath, content string) []Endpoint {
+	f.calls++
+	var found []Endpoint
+	for i, line := range sourceLines(content) {
+		if path, ok := strings.CutPrefix(line, "ROUTE "); ok {
+			found = append(found, Endpoint{Method: "GET", Path: path, FilePath: filePath, LineNumber: i + 1})
+		}
+	}
+	return found
+}
+
+// TestRegisterExtractor verifies a registered extractor drives both stages
+// for its extension
+func TestRegisterExtractor(t *testing.T) {
+	fake := &fakeExtractor{}
+	RegisterExtractor(fake)
+	t.Cleanup(func() {
+		delete(extractors, ".fake")
+		delete(supportedExtensions, ".fake")
+	})
+
+	content := "ROUTE /things\nnoise\nROUTE /things/{id}\n"
+	if !hasAPIIndicators("api.fake", content) {
+		t.Fatal("hasAPIIndicators = false for registered extension")
+	}
+	if hasAPIIndicators("api.fake", "nothing here\n") {
+		t.Error("hasAPIIndicators = true without indicators")
+	}
+
+	found := ScanFile("api.fake", content)
+	if fake.calls != 1 {
+		t.Fatalf("Extract called %d times; want 1", fake.calls)
+	}
+	if len(found) != 2 || found[0].Path != "/things" || found[1].Path != "/things/{id}" || found[1].LineNumber != 3 {
+		t.Errorf("ScanFile = %+v", found)
+	}
+
+	if got := ScanFile("api.unknown", content); len(got) != 0 {
+		t.Errorf("unregistered extension produced %d endpoints", len(got))
+	}
+}
+
+// TestScanFileLongLines verifies an overlong minified line doesn't hide the rest of the file
+func TestScanFileLongLines(t *testing.T) {
+	minified := "var bundle=\"" + strings.Repeat("a", 100*1024) + "\";app.get('/hidden', h);"
+	code := "app.get('/before', h)\n" + minified + "\r\napp.post('/after', h)\n"
+
+	endpoints := ScanFile("server.js", code)
+	if len(endpoints) != 2 {
+		t.Fatalf("ScanFile() found %d endpoints, want 2: %+v", len(endpoints), endpoints)
+	}
+	if ep := endpoints[1]; ep.Path != "/after" || ep.LineNumber != 3 {
+		t.Errorf("got %s at line %d, want /after at line 3", ep.Path, ep.LineNumber)
+	}
+	if n := longLines(code); n != 1 {
+		t.Errorf("longLines() = %d, want 1", n)
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.MaxLineLength = 0
+	if endpoints := ScanFile("server.js", code); len(endpoints) != 3 {
+		t.Errorf("ScanFile() without a limit found %d endpoints, want 3", len(endpoints))
+	}
+}
+
+// TestDynamicPaths verifies interpolated paths are flagged with normalized placeholders
+func TestDynamicPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     []string // path per endpoint
+		dynamic  []bool
+	}{
+		{
+			name:     "Python",
+			filePath: "api.py",
+			content: `@router.get(f"{API_PREFIX}/users/{user.id}")
+def get_user():
+    pass
+
+@app.route("/orders/{}/items".format(version), methods=["POST"])
+def add_item():
+    pass
+
+@router.get("/users/{user_id}")
+def literal():
+    pass
+`,
+			want:    []string{"{API_PREFIX}/users/{id}", "/orders/{param1}/items", "/users/{user_id}"},
+			dynamic: []bool{true, true, false},
+		},
+		{
+			name:     "Go",
+			filePath: "routes.go",
+			content: `package api
+
+func Register(r *gin.Engine, cfg Config) {
+	r.GET(fmt.Sprintf("/api/%s/users/%d", cfg.Version, 7), listUsers)
+	r.POST("/tenants/"+tenantID+"/jobs", createJob)
+	r.DELETE("/jobs/:id", deleteJob)
+}
+`,
+			want:    []string{"/api/{Version}/users/{param2}", "/tenants/{tenantID}/jobs", "/jobs/{id}"},
+			dynamic: []bool{true, true, false},
+		},
+		{
+			name:     "JavaScript",
+			filePath: "routes.js",
+			content:  "router.get(`${base}/reports/${report.id}`, getReport)\nrouter.get('/reports', listReports)\n",
+			want:     []string{"{base}/reports/{id}", "/reports"},
+			dynamic:  []bool{true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := ScanFile(tt.filePath, tt.content)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(tt.want), endpoints)
+			}
+			for i, ep := range endpoints {
+				if ep.Path != tt.want[i] || ep.Dynamic != tt.dynamic[i] {
+					t.Errorf("endpoint %d = %q dynamic=%v, want %q dynamic=%v", i, ep.Path, ep.Dynamic, tt.want[i], tt.dynamic[i])
+				}
+				if ep.Dynamic && len(ep.Warnings) == 0 {
+					t.Errorf("endpoint %d is dynamic without a warning", i)
+				}
+			}
+		})
+	}
+}
+
+// TestDynamicRouteFString verifies only an f prefix of the literal itself
+// makes a Python string an f-string
+func TestDynamicRouteFString(t *testing.T) {
+	tests := []struct {
+		line    string
+		dynamic bool
+	}{
+		{`@router.get(f"/users/{id}")`, true},
+		{`@router.get(rf'/users/{id}')`, true},
+		{`@router.get(FR"/users/{id}")`, true},
+		{`if "/users/{id}" in routes:`, false},
+		{`path = of '/users/{id}'`, false},
+		{`register(ref'/users/{id}')`, false},
+		{`@router.get(xf"/users/{id}")`, false},
+	}
+	for _, tt := range tests {
+		if _, _, dynamic := dynamicRoute(tt.line, "/users/{id}"); dynamic != tt.dynamic {
+			t.Errorf("dynamicRoute(%q) dynamic = %v, want %v", tt.line, dynamic, tt.dynamic)
+		}
+	}
+}
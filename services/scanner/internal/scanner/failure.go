@@ -0,0 +1,60 @@
+// Package scanner - Categories of scan failures
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Failure categories, reported in ScanStatus.ErrorCategory so operators can
+// aggregate failures without parsing messages
+const (
+	ErrorCategoryClone      = "clone"      // the repository couldn't be cloned or checked out
+	ErrorCategoryAuth       = "auth"       // credentials were missing or rejected
+	ErrorCategoryTimeout    = "timeout"    // a clone or post-process command ran out of time
+	ErrorCategoryNotFound   = "not_found"  // the repository, branch or commit doesn't exist
+	ErrorCategoryExtraction = "extraction" // discovery, extraction or post-processing failed
+	ErrorCategoryLimit      = "limit"      // the repository exceeds a scan limit
+	ErrorCategoryInternal   = "internal"   // the scanner itself failed, e.g. was restarted
+)
+
+var (
+	// ErrCloneAuth is returned when no credentials can be set up for a clone
+	ErrCloneAuth = errors.New("clone credentials unavailable")
+	// ErrMaxFiles is returned when a repository has more files than MaxFilesToScan
+	ErrMaxFiles = errors.New("max files limit reached")
+)
+
+// errorCategory classifies a failure from the error behind it, falling back
+// to the category of the step that failed
+func errorCategory(err error, fallback string) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrCloneAuth), errors.Is(err, transport.ErrAuthenticationRequired),
+		errors.Is(err, transport.ErrAuthorizationFailed):
+		return ErrorCategoryAuth
+	case errors.Is(err, transport.ErrRepositoryNotFound), errors.Is(err, plumbing.ErrReferenceNotFound),
+		errors.Is(err, ErrCommitNotFound):
+		return ErrorCategoryNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCategoryTimeout
+	case errors.Is(err, ErrMaxFiles):
+		return ErrorCategoryLimit
+	}
+	return fallback
+}
+
+// redactedError hides secrets in an error's message while keeping the error
+// it wraps matchable with errors.Is
+type redactedError struct {
+	err     error
+	secrets []string
+}
+
+func (e redactedError) Error() string { return redact(e.err.Error(), e.secrets...) }
+func (e redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestScanErrorCategory verifies failed scans report why they failed
+func TestScanErrorCategory(t *testing.T) {
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	// Nothing listens on a closed server's address
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name, url, want string
+	}{
+		{"auth", unauthorized.URL + "/repo.git", ErrorCategoryAuth},
+		{"not-found", missing.URL + "/repo.git", ErrorCategoryNotFound},
+		{"clone", closed.URL + "/repo.git", ErrorCategoryClone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanID := "category-" + tt.name
+			StartScan(ScanJob{ScanID: scanID, URL: tt.url})
+			status, _ := GetStatus(scanID)
+			if status.Status != "failed" || status.ErrorCategory != tt.want {
+				t.Errorf("scan = %s (%s: %q), want failed as %s", status.Status, status.ErrorCategory, status.Error, tt.want)
+			}
+		})
+	}
+}
+
+// TestErrorCategory verifies errors are classified through wrapping, and
+// unknown ones fall back to the failing step's category
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("%w: loading SSH key: no such file", ErrCloneAuth), ErrorCategoryAuth},
+		{fmt.Errorf("%w: abc123", ErrCommitNotFound), ErrorCategoryNotFound},
+		{ErrMaxFiles, ErrorCategoryLimit},
+		{fmt.Errorf("failed to clone repository: %w", redactedError{fmt.Errorf("boom"), nil}), ErrorCategoryExtraction},
+	}
+	for _, tt := range tests {
+		if got := errorCategory(tt.err, ErrorCategoryExtraction); got != tt.want {
+			t.Errorf("errorCategory(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.PostProcessCommand = writeScript(t, "sleep 5")
+	config.PostProcessTimeout = 1
+	if _, err := postProcess(nil); errorCategory(err, ErrorCategoryExtraction) != ErrorCategoryTimeout {
+		t.Errorf("post-process timeout %v not classified as a timeout", err)
+	}
+}
@@ -0,0 +1,279 @@
+// Package scanner - Routes declared in gateway and routing config files
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// GatewayTag tags endpoints declared in route config files
+const GatewayTag = "gateway"
+
+// ocelotConfigFile is Ocelot's gateway configuration
+const ocelotConfigFile = "ocelot.json"
+
+// ocelotConfig is the part of ocelot.json describing routes. Ocelot before
+// 16.0 called them ReRoutes.
+type ocelotConfig struct {
+	Routes   []ocelotRoute `yaml:"Routes"`
+	ReRoutes []ocelotRoute `yaml:"ReRoutes"`
+}
+
+type ocelotRoute struct {
+	UpstreamPathTemplate   string   `yaml:"UpstreamPathTemplate"`
+	UpstreamHTTPMethod     []string `yaml:"UpstreamHttpMethod"`
+	DownstreamPathTemplate string   `yaml:"DownstreamPathTemplate"`
+	DownstreamScheme       string   `yaml:"DownstreamScheme"`
+	DownstreamHostAndPorts []struct {
+		Host string `yaml:"Host"`
+		Port int    `yaml:"Port"`
+	} `yaml:"DownstreamHostAndPorts"`
+	ServiceName string `yaml:"ServiceName"`
+}
+
+// kongConfig is Kong's declarative configuration (kong.yml)
+type kongConfig struct {
+	Services []struct {
+		Name     string      `yaml:"name"`
+		URL      string      `yaml:"url"`
+		Protocol string      `yaml:"protocol"`
+		Host     string      `yaml:"host"`
+		Port     int         `yaml:"port"`
+		Path     string      `yaml:"path"`
+		Routes   []kongRoute `yaml:"routes"`
+	} `yaml:"services"`
+}
+
+type kongRoute struct {
+	Paths   []string `yaml:"paths"`
+	Methods []string `yaml:"methods"`
+}
+
+// configRoute is a route in a project's routes.json/routes.yaml. Methods
+// may be a list or a string like Symfony's "GET|HEAD".
+type configRoute struct {
+	Path     string `yaml:"path"`
+	Method   string `yaml:"method"`
+	Methods  any    `yaml:"methods"`
+	Upstream string `yaml:"upstream"`
+	Target   string `yaml:"target"`
+	URL      string `yaml:"url"`
+	Service  string `yaml:"service"`
+}
+
+// gatewayRoute is one route read from a config file, before line lookup
+type gatewayRoute struct {
+	methods  []string
+	path     string
+	upstream string
+}
+
+// extractGatewayRoutes parses a route config file by its name. Configs
+// that fail to parse are logged and skipped.
+func extractGatewayRoutes(filePath, content string) []Endpoint {
+	var routes []gatewayRoute
+	var err error
+	key := "" // key whose line declares a route's path, preferred when locating it
+	switch strings.ToLower(filepath.Base(filePath)) {
+	case ocelotConfigFile:
+		routes, err = parseOcelotRoutes(content)
+		key = "UpstreamPathTemplate"
+	case "kong.yml", "kong.yaml":
+		routes, err = parseKongRoutes(content)
+	default:
+		routes, err = parseConfigRoutes(content)
+		key = "path"
+	}
+	if err != nil {
+		log.Printf("⚠️  Skipping route config %s: %v", filePath, err)
+		return nil
+	}
+
+	lines := sourceLines(content)
+	var found []Endpoint
+	from := 0
+	for _, route := range routes {
+		if route.path == "" {
+			continue
+		}
+		line := gatewayRouteLine(lines, key, route.path, from)
+		if line > 0 {
+			from = line - 1
+		}
+		methods := route.methods
+		if len(methods) == 0 {
+			methods = []string{"ANY"}
+		}
+		for _, method := range methods {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			found = append(found, Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, line),
+				Path:       route.path,
+				Method:     method,
+				FilePath:   filePath,
+				LineNumber: line,
+				Tags:       []string{GatewayTag},
+				Upstream:   route.upstream,
+			})
+		}
+	}
+	return found
+}
+
+// gatewayRouteLine returns the 1-based line declaring path at or after
+// line index from, preferring lines with key, or 0 when it can't be found
+func gatewayRouteLine(lines []string, key, path string, from int) int {
+	pattern := regexp.MustCompile(`(?:^|["'\s])` + regexp.QuoteMeta(path) + `(?:["'\s,]|$)`)
+	for _, start := range []int{from, 0} {
+		fallback := 0
+		for i := start; i < len(lines); i++ {
+			if !pattern.MatchString(lines[i]) {
+				continue
+			}
+			if key == "" || strings.Contains(lines[i], key) {
+				return i + 1
+			}
+			if fallback == 0 {
+				fallback = i + 1
+			}
+		}
+		if fallback != 0 {
+			return fallback
+		}
+	}
+	return 0
+}
+
+// parseOcelotRoutes reads ocelot.json. Upstream templates are what clients
+// call; the downstream service is the route's upstream target.
+func parseOcelotRoutes(content string) ([]gatewayRoute, error) {
+	var cfg ocelotConfig
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		return nil, err
+	}
+	var routes []gatewayRoute
+	for _, r := range append(cfg.Routes, cfg.ReRoutes...) {
+		scheme := r.DownstreamScheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		host := r.ServiceName
+		if len(r.DownstreamHostAndPorts) > 0 {
+			hp := r.DownstreamHostAndPorts[0]
+			host = hp.Host
+			if hp.Port != 0 {
+				host += ":" + strconv.Itoa(hp.Port)
+			}
+		}
+		upstream := r.DownstreamPathTemplate
+		if host != "" {
+			upstream = scheme + "://" + host + upstream
+		}
+		routes = append(routes, gatewayRoute{
+			methods:  r.UpstreamHTTPMethod,
+			path:     r.UpstreamPathTemplate,
+			upstream: upstream,
+		})
+	}
+	return routes, nil
+}
+
+// parseKongRoutes reads a Kong declarative config, one route per path
+func parseKongRoutes(content string) ([]gatewayRoute, error) {
+	var cfg kongConfig
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		return nil, err
+	}
+	var routes []gatewayRoute
+	for _, svc := range cfg.Services {
+		upstream := svc.URL
+		if upstream == "" && svc.Host != "" {
+			protocol := svc.Protocol
+			if protocol == "" {
+				protocol = "http"
+			}
+			upstream = protocol + "://" + svc.Host
+			if svc.Port != 0 {
+				upstream += ":" + strconv.Itoa(svc.Port)
+			}
+			upstream += svc.Path
+		}
+		for _, r := range svc.Routes {
+			for _, path := range r.Paths {
+				routes = append(routes, gatewayRoute{methods: r.Methods, path: path, upstream: upstream})
+			}
+		}
+	}
+	return routes, nil
+}
+
+// parseConfigRoutes reads a project's routes file: a list of routes, a
+// list under "routes", or routes keyed by name as in Symfony
+func parseConfigRoutes(content string) ([]gatewayRoute, error) {
+	var list []configRoute
+	if err := yaml.Unmarshal([]byte(content), &list); err == nil {
+		return configGatewayRoutes(list), nil
+	}
+
+	var wrapped struct {
+		Routes []configRoute `yaml:"routes"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &wrapped); err == nil && len(wrapped.Routes) > 0 {
+		return configGatewayRoutes(wrapped.Routes), nil
+	}
+
+	var named yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(content), &named); err != nil {
+		return nil, err
+	}
+	for _, item := range named {
+		raw, err := yaml.Marshal(item.Value)
+		if err != nil {
+			continue
+		}
+		var r configRoute
+		if yaml.Unmarshal(raw, &r) == nil {
+			list = append(list, r)
+		}
+	}
+	return configGatewayRoutes(list), nil
+}
+
+// configGatewayRoutes converts routes file entries, skipping imports and
+// other entries without a path
+func configGatewayRoutes(list []configRoute) []gatewayRoute {
+	var routes []gatewayRoute
+	for _, r := range list {
+		if r.Path == "" {
+			continue
+		}
+		var methods []string
+		if r.Method != "" {
+			methods = append(methods, r.Method)
+		}
+		switch m := r.Methods.(type) {
+		case string:
+			methods = append(methods, strings.FieldsFunc(m, func(c rune) bool { return c == '|' || c == ',' || c == ' ' })...)
+		case []any:
+			for _, v := range m {
+				if s, ok := v.(string); ok {
+					methods = append(methods, s)
+				}
+			}
+		}
+		upstream := r.Upstream
+		for _, alt := range []string{r.Target, r.URL, r.Service} {
+			if upstream == "" {
+				upstream = alt
+			}
+		}
+		routes = append(routes, gatewayRoute{methods: methods, path: r.Path, upstream: upstream})
+	}
+	return routes
+}
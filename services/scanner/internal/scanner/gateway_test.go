@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestOcelotRoutes verifies routes are read from ocelot.json with their downstream service
+func TestOcelotRoutes(t *testing.T) {
+	content := `{
+  "Routes": [
+    {
+      "DownstreamPathTemplate": "/api/users/{id}",
+      "DownstreamScheme": "https",
+      "DownstreamHostAndPorts": [{ "Host": "users-service", "Port": 8080 }],
+      "UpstreamPathTemplate": "/users/{id}",
+      "UpstreamHttpMethod": [ "Get", "Put" ]
+    },
+    {
+      "DownstreamPathTemplate": "/orders",
+      "ServiceName": "orders",
+      "UpstreamPathTemplate": "/orders"
+    }
+  ],
+  "GlobalConfiguration": { "BaseUrl": "https://api.example.com" }
+}
+`
+	if !hasAPIIndicators("gateway/ocelot.json", content) {
+		t.Fatal("ocelot.json rejected by the pre-filter")
+	}
+
+	endpoints := ScanFile("gateway/ocelot.json", content)
+	want := []struct {
+		method, path, upstream string
+		line                   int
+	}{
+		{"GET", "/users/{id}", "https://users-service:8080/api/users/{id}", 7},
+		{"PUT", "/users/{id}", "https://users-service:8080/api/users/{id}", 7},
+		{"ANY", "/orders", "http://orders/orders", 13},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.Upstream != w.upstream || ep.LineNumber != w.line {
+			t.Errorf("endpoint %d = %s %s -> %s (line %d), want %s %s -> %s (line %d)",
+				i, ep.Method, ep.Path, ep.Upstream, ep.LineNumber, w.method, w.path, w.upstream, w.line)
+		}
+		if len(ep.Tags) != 1 || ep.Tags[0] != GatewayTag {
+			t.Errorf("endpoint %d tags = %v, want [%s]", i, ep.Tags, GatewayTag)
+		}
+	}
+}
+
+// TestRouteConfigFiles verifies Kong and routes.yaml configs, and that
+// only route config files are picked up among JSON and YAML files
+func TestRouteConfigFiles(t *testing.T) {
+	kong := `_format_version: "3.0"
+services:
+  - name: billing
+    url: http://billing:9000
+    routes:
+      - name: invoices
+        paths:
+          - /invoices
+        methods: [GET, POST]
+`
+	symfony := `blog_list:
+    path: /blog
+    controller: App\Controller\BlogController::list
+    methods: GET|HEAD
+
+controllers:
+    resource: ../src/Controller/
+    type: attribute
+`
+	kongEps := ScanFile("kong.yml", kong)
+	if len(kongEps) != 2 || kongEps[0].Path != "/invoices" || kongEps[1].Method != "POST" || kongEps[0].Upstream != "http://billing:9000" {
+		t.Errorf("kong.yml endpoints = %+v", kongEps)
+	}
+
+	symfonyEps := ScanFile("config/routes.yaml", symfony)
+	if len(symfonyEps) != 2 || symfonyEps[0].Method != "GET" || symfonyEps[1].Method != "HEAD" || symfonyEps[0].LineNumber != 2 {
+		t.Errorf("routes.yaml endpoints = %+v", symfonyEps)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"ocelot.json", "package.json", "docker-compose.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := getCodeFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "ocelot.json" {
+		t.Errorf("getCodeFiles() = %v, want only ocelot.json", files)
+	}
+}
@@ -0,0 +1,658 @@
+// Package scanner - AST based extraction for Go sources
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// goVerbMethods are the router methods named after HTTP verbs (Gin, Echo)
+var goVerbMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true,
+	"DELETE": true, "OPTIONS": true, "HEAD": true,
+}
+
+// goExtractor walks a parsed Go file and records route registrations
+type goExtractor struct {
+	fset     *token.FileSet
+	filePath string
+	consts   map[string]string // package-level string constants/variables
+	found    []Endpoint
+	handled  map[*ast.CallExpr]bool
+	stack    []ast.Node // ancestors of the node being visited
+
+	prefixes map[string]string         // gorilla subrouter path prefixes by receiver expression
+	prefixAt map[string]int            // line declaring each subrouter's PathPrefix
+	wrappers map[string][]wrapperRoute // registration helpers by function/method name
+	inWraps  map[*ast.CallExpr]bool    // registrations parameterised by a wrapper
+	site     *ast.CallExpr             // wrapper call being expanded, if any
+
+	routeMaps    []routeMap                 // handler maps registered in a loop
+	microMethods map[string][]*ast.FuncDecl // go-micro handler methods by receiver type
+}
+
+// wrapperRoute is a registration inside a helper such as
+//
+//	func (s *server) handle(pattern string, h http.Handler) { s.mux.Handle(pattern, h) }
+//
+// whose path or method comes from the helper's parameters. It is extracted
+// where the helper is called, with the call's arguments substituted.
+type wrapperRoute struct {
+	call   *ast.CallExpr
+	params []string        // helper parameters, in order
+	uses   map[string]bool // parameters the route arguments depend on
+}
+
+// extractGoAST extracts endpoints from Go source using the go/ast parser.
+// It reports false when the source does not parse, so callers can fall back
+// to the regex patterns.
+func extractGoAST(filePath, content string) ([]Endpoint, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+
+	x := &goExtractor{
+		fset:     fset,
+		filePath: filePath,
+		consts:   make(map[string]string),
+		handled:  make(map[*ast.CallExpr]bool),
+		prefixes: make(map[string]string),
+		prefixAt: make(map[string]int),
+		wrappers: make(map[string][]wrapperRoute),
+		inWraps:  make(map[*ast.CallExpr]bool),
+	}
+
+	// Package-level constants and variables, in declaration order
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok {
+			x.recordValueSpecs(gen, x.consts, nil)
+		}
+	}
+
+	// Registration helpers must be known before their call sites are visited
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			x.recordWrapper(fn)
+		}
+	}
+	x.dropUncalledWrappers(file)
+
+	// Route maps are extracted from their entries, not the loop registering them
+	x.findRouteMaps(file)
+	x.microMethods = goMicroMethods(file)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		locals := make(map[string]string)
+		x.stack = x.stack[:0]
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if n == nil {
+				x.stack = x.stack[:len(x.stack)-1]
+				return true
+			}
+			x.stack = append(x.stack, n)
+
+			switch node := n.(type) {
+			case *ast.DeclStmt:
+				if gen, ok := node.Decl.(*ast.GenDecl); ok {
+					x.recordValueSpecs(gen, locals, locals)
+				}
+			case *ast.AssignStmt:
+				x.recordAssign(node, locals)
+			case *ast.CallExpr:
+				if !x.expandWrapper(node, locals) {
+					x.visitCall(node, locals)
+				}
+			}
+			return true
+		})
+	}
+	x.recordRouteMaps()
+
+	return x.found, true
+}
+
+// recordValueSpecs stores string values and subrouter prefixes declared by a
+// const/var declaration
+func (x *goExtractor) recordValueSpecs(gen *ast.GenDecl, into, locals map[string]string) {
+	if gen.Tok != token.CONST && gen.Tok != token.VAR {
+		return
+	}
+	for _, spec := range gen.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Values) != len(vs.Names) {
+			continue
+		}
+		for i, name := range vs.Names {
+			if prefix, ok := x.subrouterPrefix(vs.Values[i], locals); ok {
+				x.prefixes[name.Name] = prefix
+				x.prefixAt[name.Name] = x.fset.Position(vs.Values[i].Pos()).Line
+				continue
+			}
+			if value, ok := x.resolveString(vs.Values[i], locals); ok {
+				into[name.Name] = value
+			}
+		}
+	}
+}
+
+// recordAssign stores string values assigned to local identifiers and the
+// prefixes of subrouters assigned to variables or fields
+func (x *goExtractor) recordAssign(assign *ast.AssignStmt, locals map[string]string) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, lhs := range assign.Lhs {
+		if prefix, ok := x.subrouterPrefix(assign.Rhs[i], locals); ok {
+			x.prefixes[types.ExprString(lhs)] = prefix
+			x.prefixAt[types.ExprString(lhs)] = x.fset.Position(assign.Rhs[i].Pos()).Line
+			continue
+		}
+
+		ident, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if value, ok := x.resolveString(assign.Rhs[i], locals); ok {
+			locals[ident.Name] = value
+		} else {
+			// Reassigned to something we can't follow
+			delete(locals, ident.Name)
+		}
+	}
+}
+
+// subrouterPrefix returns the full prefix of a gorilla subrouter expression,
+// r.PathPrefix("/api").Subrouter(), composing the prefix of r itself
+func (x *goExtractor) subrouterPrefix(expr ast.Expr, locals map[string]string) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Subrouter" {
+		return "", false
+	}
+	inner, ok := sel.X.(*ast.CallExpr)
+	if !ok || len(inner.Args) != 1 {
+		return "", false
+	}
+	innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+	if !ok || innerSel.Sel.Name != "PathPrefix" {
+		return "", false
+	}
+	prefix, ok := x.resolveString(inner.Args[0], locals)
+	if !ok {
+		return "", false
+	}
+	return joinRoutePath(x.routerPrefix(innerSel.X, locals), prefix), true
+}
+
+// routerPrefix returns the path prefix of the router a route is registered on
+func (x *goExtractor) routerPrefix(router ast.Expr, locals map[string]string) string {
+	if prefix, ok := x.subrouterPrefix(router, locals); ok {
+		return prefix
+	}
+	return x.prefixes[types.ExprString(router)]
+}
+
+// routerPrefixLine returns the line declaring the prefix of the router a
+// route is registered on, or 0 when it has none
+func (x *goExtractor) routerPrefixLine(router ast.Expr, locals map[string]string) int {
+	if _, ok := x.subrouterPrefix(router, locals); ok {
+		return x.fset.Position(router.Pos()).Line
+	}
+	return x.prefixAt[types.ExprString(router)]
+}
+
+// joinRoutePath appends a route path to a router prefix
+func joinRoutePath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// resolveString evaluates string literals, known identifiers and concatenations
+func (x *goExtractor) resolveString(expr ast.Expr, locals map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.Ident:
+		if value, ok := locals[e.Name]; ok {
+			return value, true
+		}
+		value, ok := x.consts[e.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return x.resolveString(e.X, locals)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := x.resolveString(e.X, locals)
+		if !ok {
+			return "", false
+		}
+		right, ok := x.resolveString(e.Y, locals)
+		return left + right, ok
+	}
+	return "", false
+}
+
+// resolvePath resolves a route path argument. Identifiers that can't be
+// resolved are kept by name with a warning rather than dropped, and paths
+// built with fmt.Sprintf or concatenation keep their literal parts with
+// {name} placeholders. Any warning marks the endpoint Dynamic.
+func (x *goExtractor) resolvePath(expr ast.Expr, locals map[string]string) (path string, warning string, ok bool) {
+	if value, ok := x.resolveString(expr, locals); ok {
+		return value, "", true
+	}
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		return ident.Name, fmt.Sprintf("unresolved path identifier %q", ident.Name), true
+	}
+	n := 0
+	if template, ok := x.sprintfTemplate(expr, locals, &n); ok {
+		return template, dynamicPathWarning("fmt.Sprintf"), true
+	}
+	if bin, isBinary := expr.(*ast.BinaryExpr); isBinary && bin.Op == token.ADD {
+		if template, literal := x.concatTemplate(bin, locals, &n); literal {
+			return template, dynamicPathWarning("concatenation"), true
+		}
+	}
+	return "", "", false
+}
+
+// Go fmt verbs: %s, %d, %v, %08x...
+var printfVerbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+
+// sprintfTemplate renders fmt.Sprintf(format, args...) with each verb as a
+// placeholder named after its argument
+func (x *goExtractor) sprintfTemplate(expr ast.Expr, locals map[string]string, n *int) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 || types.ExprString(call.Fun) != "fmt.Sprintf" {
+		return "", false
+	}
+	format, ok := x.resolveString(call.Args[0], locals)
+	if !ok {
+		return "", false
+	}
+	args := call.Args[1:]
+	arg := 0
+	template := printfVerbPattern.ReplaceAllStringFunc(format, func(string) string {
+		var value ast.Expr
+		if arg < len(args) {
+			value = args[arg]
+		}
+		arg++
+		return x.placeholder(value, locals, n)
+	})
+	return strings.ReplaceAll(template, "%%", "%"), true
+}
+
+// concatTemplate renders a concatenation with its unresolvable operands as
+// placeholders, reporting whether any literal part was found
+func (x *goExtractor) concatTemplate(expr ast.Expr, locals map[string]string, n *int) (string, bool) {
+	if bin, ok := expr.(*ast.BinaryExpr); ok && bin.Op == token.ADD {
+		left, leftLiteral := x.concatTemplate(bin.X, locals, n)
+		right, rightLiteral := x.concatTemplate(bin.Y, locals, n)
+		return left + right, leftLiteral || rightLiteral
+	}
+	if value, ok := x.resolveString(expr, locals); ok {
+		return value, true
+	}
+	if template, ok := x.sprintfTemplate(expr, locals, n); ok {
+		return template, true
+	}
+	return x.placeholder(expr, locals, n), false
+}
+
+// placeholder names a value interpolated into a path: a resolvable string
+// is inlined, an identifier or field becomes {name}, anything else {paramN}
+func (x *goExtractor) placeholder(expr ast.Expr, locals map[string]string, n *int) string {
+	*n++
+	if expr == nil {
+		return fmt.Sprintf("{param%d}", *n)
+	}
+	if value, ok := x.resolveString(expr, locals); ok {
+		return value
+	}
+	return "{" + placeholderName(types.ExprString(expr), *n) + "}"
+}
+
+// routeArgs returns the arguments of a registration call that carry its
+// path and method, or nil if the call isn't a registration form
+func routeArgs(call *ast.CallExpr) []ast.Expr {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	switch name := sel.Sel.Name; {
+	case goVerbMethods[name] && len(call.Args) >= 1:
+		return call.Args[:1]
+	case name == "Add" && len(call.Args) >= 2:
+		return call.Args[:2]
+	case name == "Handle" && len(call.Args) >= 2 && isMethodArg(call.Args[0]):
+		return call.Args[:2]
+	case (name == "HandleFunc" || name == "Handle") && len(call.Args) >= 1:
+		return call.Args[:1]
+	case name == "Methods":
+		if inner, ok := sel.X.(*ast.CallExpr); ok && len(inner.Args) > 0 {
+			return append([]ast.Expr{inner.Args[0]}, call.Args...)
+		}
+	}
+	return nil
+}
+
+// isMethodArg reports whether expr is literally an HTTP verb, so that
+// Handle(method, path, h) can be told apart from Handle(path, h)
+func isMethodArg(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		return strings.HasPrefix(e.Sel.Name, "Method")
+	case *ast.BasicLit:
+		value, err := strconv.Unquote(e.Value)
+		return err == nil && goVerbMethods[strings.ToUpper(value)]
+	}
+	return false
+}
+
+// recordWrapper notes the registrations in fn whose path or method depends
+// on fn's parameters. Only helpers declared in the same file are followed.
+func (x *goExtractor) recordWrapper(fn *ast.FuncDecl) {
+	var params []string
+	isParam := make(map[string]bool)
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			params = append(params, name.Name)
+			isParam[name.Name] = true
+		}
+	}
+	if len(params) == 0 {
+		return
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		uses := make(map[string]bool)
+		for _, arg := range routeArgs(call) {
+			ast.Inspect(arg, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && isParam[ident.Name] {
+					uses[ident.Name] = true
+				}
+				return true
+			})
+		}
+		if len(uses) > 0 {
+			x.wrappers[fn.Name.Name] = append(x.wrappers[fn.Name.Name], wrapperRoute{call: call, params: params, uses: uses})
+			x.inWraps[call] = true
+		}
+		return true
+	})
+}
+
+// dropUncalledWrappers forgets helpers that are never called in the file,
+// such as exported Register(r, prefix) functions, so their registrations
+// are extracted in place as before
+func (x *goExtractor) dropUncalledWrappers(file *ast.File) {
+	called := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if name, ok := calledName(call); ok {
+				called[name] = true
+			}
+		}
+		return true
+	})
+
+	for name, routes := range x.wrappers {
+		if called[name] {
+			continue
+		}
+		for _, route := range routes {
+			delete(x.inWraps, route.call)
+		}
+		delete(x.wrappers, name)
+	}
+}
+
+// calledName returns the function or method name of a call that could
+// target a helper: f(...) or recv.f(...), but not a registration itself
+func calledName(call *ast.CallExpr) (string, bool) {
+	if routeArgs(call) != nil {
+		return "", false
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name, true
+	case *ast.SelectorExpr:
+		return fun.Sel.Name, true
+	}
+	return "", false
+}
+
+// expandWrapper extracts the routes registered through a helper call such as
+// s.handle("/users", h), reporting whether call was a helper call. Calls whose
+// path or method arguments don't resolve are skipped rather than guessed.
+func (x *goExtractor) expandWrapper(call *ast.CallExpr, locals map[string]string) bool {
+	name, ok := calledName(call)
+	if !ok {
+		return false
+	}
+	routes, ok := x.wrappers[name]
+	if !ok {
+		return false
+	}
+
+	x.site = call
+	defer func() { x.site = nil }()
+
+	for _, route := range routes {
+		if len(call.Args) != len(route.params) {
+			continue
+		}
+		args := make(map[string]string)
+		for i, param := range route.params {
+			if value, ok := x.resolveString(call.Args[i], locals); ok {
+				args[param] = value
+			} else if method, ok := x.resolveMethod(call.Args[i], locals); ok {
+				args[param] = method
+			}
+		}
+		resolved := true
+		for param := range route.uses {
+			if _, ok := args[param]; !ok {
+				resolved = false
+			}
+		}
+		if resolved {
+			x.visitRegistration(route.call, args)
+		}
+	}
+	return true
+}
+
+// visitCall records a route if the call is a known registration form
+func (x *goExtractor) visitCall(call *ast.CallExpr, locals map[string]string) {
+	if x.inWraps[call] {
+		// Extracted where the helper is called
+		return
+	}
+	x.visitRegistration(call, locals)
+}
+
+// visitRegistration records the route declared by a registration call
+func (x *goExtractor) visitRegistration(call *ast.CallExpr, locals map[string]string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || x.handled[call] {
+		return
+	}
+	name := sel.Sel.Name
+
+	switch {
+	case goVerbMethods[name] && len(call.Args) >= 1:
+		// Gin, Echo: r.GET("/path", handler)
+		path, warning, ok := x.resolvePath(call.Args[0], locals)
+		if ok {
+			x.add(call, locals, name, path, warning)
+		}
+
+	case (name == "Add" || name == "Handle") && len(call.Args) >= 2:
+		// Generic registration: router.Add(http.MethodGet, "/path", h), r.Handle("GET", "/path", h).
+		// Only recognised when the first argument is an HTTP verb.
+		method, ok := x.resolveMethod(call.Args[0], locals)
+		if !ok {
+			if name == "Handle" {
+				x.visitHandle(call, locals)
+			}
+			return
+		}
+		path, warning, ok := x.resolvePath(call.Args[1], locals)
+		if ok {
+			x.add(call, locals, method, path, warning)
+		}
+
+	case name == "Methods":
+		// Gorilla: r.HandleFunc("/path", h).Methods("GET", "POST")
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+		if !ok || (innerSel.Sel.Name != "HandleFunc" && innerSel.Sel.Name != "Handle") || len(inner.Args) == 0 {
+			return
+		}
+		path, warning, ok := x.resolvePath(inner.Args[0], locals)
+		if !ok {
+			return
+		}
+		x.handled[inner] = true
+		for _, arg := range call.Args {
+			if method, ok := x.resolveString(arg, locals); ok {
+				x.add(inner, locals, strings.ToUpper(method), path, warning)
+			}
+		}
+
+	case (name == "HandleFunc" || name == "Handle") && len(call.Args) >= 1:
+		x.visitHandle(call, locals)
+
+	case name == "Handler" || name == "HandlerFunc":
+		// go-kit: r.Methods("GET").Path("/users/{id}").Handler(server)
+		x.visitRouteBuilder(call, locals)
+
+	case isMicroRegistration(name, call):
+		x.visitMicroHandler(call)
+	}
+}
+
+// visitHandle records a standard library registration: mux.HandleFunc("/path", h)
+// or a Go 1.22 method pattern such as "GET /path"
+func (x *goExtractor) visitHandle(call *ast.CallExpr, locals map[string]string) {
+	path, warning, ok := x.resolvePath(call.Args[0], locals)
+	if !ok {
+		return
+	}
+	method := "ANY"
+	if verb, rest, found := strings.Cut(path, " "); found && goVerbMethods[verb] {
+		method, path = verb, strings.TrimSpace(rest)
+	}
+	x.add(call, locals, method, path, warning)
+}
+
+// resolveMethod resolves an HTTP verb argument: "GET", a string constant,
+// or a method constant such as http.MethodGet / fiber.MethodPost
+func (x *goExtractor) resolveMethod(expr ast.Expr, locals map[string]string) (string, bool) {
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if verb, found := strings.CutPrefix(sel.Sel.Name, "Method"); found {
+			verb = strings.ToUpper(verb)
+			return verb, goVerbMethods[verb]
+		}
+		return "", false
+	}
+	value, ok := x.resolveString(expr, locals)
+	value = strings.ToUpper(value)
+	return value, ok && goVerbMethods[value]
+}
+
+// add records an endpoint declared by call
+func (x *goExtractor) add(call *ast.CallExpr, locals map[string]string, method, path, warning string) {
+	prefixLine := 0
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if prefix := x.routerPrefix(sel.X, locals); prefix != "" {
+			path = joinRoutePath(prefix, path)
+			prefixLine = x.routerPrefixLine(sel.X, locals)
+		}
+	}
+	if path == "" {
+		return
+	}
+	if x.site != nil {
+		// Routes from a helper are reported where the helper is called
+		call = x.site
+	}
+	x.record(x.fset.Position(call.Pos()).Line, prefixLine, method, path, warning)
+}
+
+// record appends an endpoint declared at line
+func (x *goExtractor) record(line, prefixLine int, method, path, warning string) {
+	ep := Endpoint{
+		ID:         fmt.Sprintf("%s-%s-%d", scanID(x.filePath), method, line),
+		Path:       path,
+		Method:     method,
+		FilePath:   x.filePath,
+		LineNumber: line,
+		Tags:       []string{extractTag(x.filePath, path)},
+		PrefixLine: prefixLine,
+	}
+	if warning != "" {
+		ep.Warnings = append(ep.Warnings, warning)
+		ep.Dynamic = true
+	}
+	ep.Conditional = x.inConditional()
+	x.found = append(x.found, ep)
+}
+
+// inConditional reports whether the current node sits in an if/else body or
+// a switch/select case, without crossing into a nested function literal
+func (x *goExtractor) inConditional() bool {
+	for i := len(x.stack) - 1; i >= 0; i-- {
+		switch node := x.stack[i].(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CaseClause, *ast.CommClause:
+			return true
+		case *ast.IfStmt:
+			// Registrations in the condition itself always run
+			if i+1 < len(x.stack) && (x.stack[i+1] == node.Body || x.stack[i+1] == node.Else) {
+				return true
+			}
+		}
+	}
+	return false
+}
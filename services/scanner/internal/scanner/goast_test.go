@@ -0,0 +1,234 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const goConstPaths = `package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiPrefix = "/api"
+	userPath  = apiPrefix + "/users"
+)
+
+var healthPath = "/health"
+
+func Register(r *gin.Engine, mux *http.ServeMux, dynamicPath string) {
+	r.GET(userPath, listUsers)
+	r.GET(healthPath, health)
+
+	orderPath := "/orders"
+	r.POST(apiPrefix+orderPath, createOrder)
+
+	r.DELETE(dynamicPath, remove)
+
+	mux.HandleFunc("GET /items/{id}", getItem)
+}
+`
+
+// TestGoASTResolvesPathConstants verifies identifier paths resolve to their literal values
+func TestGoASTResolvesPathConstants(t *testing.T) {
+	endpoints := ScanFile("routes/routes.go", goConstPaths)
+
+	want := []struct {
+		method, path string
+		warned       bool
+	}{
+		{"GET", "/api/users", false},
+		{"GET", "/health", false},
+		{"POST", "/api/orders", false},
+		{"DELETE", "dynamicPath", true},
+		{"GET", "/items/{id}", false},
+	}
+
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path {
+			t.Errorf("endpoint %d = %s %s, want %s %s", i, ep.Method, ep.Path, w.method, w.path)
+		}
+		if (len(ep.Warnings) > 0) != w.warned {
+			t.Errorf("endpoint %d warnings = %v, want warned=%v", i, ep.Warnings, w.warned)
+		}
+	}
+}
+
+// TestGoASTFallsBackToRegex verifies fragments that don't parse still use the regex patterns
+func TestGoASTFallsBackToRegex(t *testing.T) {
+	fragment := `r.GET("/fragment", handler)`
+	if _, ok := extractGoAST("snippet.go", fragment); ok {
+		t.Fatal("fragment should not parse as a Go file")
+	}
+
+	endpoints := ScanFile("snippet.go", fragment)
+	if len(endpoints) != 1 || endpoints[0].Path != "/fragment" {
+		t.Errorf("ScanFile() = %+v, want regex fallback for /fragment", endpoints)
+	}
+}
+
+// TestGoMethodConstantRegistration verifies .Add/.Handle forms with method arguments
+func TestGoMethodConstantRegistration(t *testing.T) {
+	source := `package routes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Register(app *fiber.App, r *gin.Engine, e *echo.Echo) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	app.Add(http.MethodGet, "/users", listUsers)
+	app.Add(fiber.MethodPost, "/users", createUser)
+	e.Add("DELETE", "/users/:id", deleteUser)
+	r.Handle("PATCH", "/users/:id", updateUser)
+}
+`
+	want := []struct{ method, path string }{
+		{"GET", "/users"},
+		{"POST", "/users"},
+		{"DELETE", "/users/{id}"},
+		{"PATCH", "/users/{id}"},
+	}
+
+	check := func(t *testing.T, endpoints []Endpoint) {
+		if len(endpoints) != len(want) {
+			t.Fatalf("found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+		}
+		for i, w := range want {
+			if endpoints[i].Method != w.method || endpoints[i].Path != w.path {
+				t.Errorf("endpoint %d = %s %s, want %s %s", i, endpoints[i].Method, endpoints[i].Path, w.method, w.path)
+			}
+		}
+	}
+
+	if !hasAPIIndicators("routes.go", source) {
+		t.Fatal("router.Add file should have API indicators")
+	}
+	t.Run("AST", func(t *testing.T) {
+		check(t, ScanFile("routes.go", source))
+	})
+	t.Run("Regex", func(t *testing.T) {
+		// Drop the package clause so the regex fallback is exercised
+		check(t, ScanFile("routes.go", source[len("package routes"):]))
+	})
+}
+
+// TestGoASTRegistrationHelpers verifies routes registered through helper
+// methods are extracted at the helper's call sites
+func TestGoASTRegistrationHelpers(t *testing.T) {
+	source := `package server
+
+import "net/http"
+
+type server struct {
+	mux *http.ServeMux
+}
+
+func (s *server) routes() {
+	s.mux.Handle("/healthz", s.health())
+	s.handle("GET /users", s.listUsers)
+	s.get("/users/{id}", s.getUser)
+	s.api(http.MethodPost, "/orders", s.createOrder)
+	s.handle(s.pattern, s.dynamic)
+}
+
+func (s *server) handle(pattern string, h http.HandlerFunc) {
+	s.mux.Handle(pattern, s.logged(h))
+}
+
+func (s *server) get(path string, h http.HandlerFunc) {
+	s.mux.HandleFunc("GET "+path, h)
+}
+
+func (s *server) api(method, path string, h http.HandlerFunc) {
+	s.mux.HandleFunc(method+" /api"+path, h)
+}
+`
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"ANY", "/healthz", 10},
+		{"GET", "/users", 11},
+		{"GET", "/users/{id}", 12},
+		{"POST", "/api/orders", 13},
+	}
+
+	endpoints := ScanFile("server/routes.go", source)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.LineNumber != w.line {
+			t.Errorf("endpoint %d = %s %s (line %d), want %s %s (line %d)", i, ep.Method, ep.Path, ep.LineNumber, w.method, w.path, w.line)
+		}
+		if len(ep.Warnings) > 0 {
+			t.Errorf("endpoint %d warnings = %v, want none", i, ep.Warnings)
+		}
+	}
+}
+
+// TestGoASTGorillaSubrouters verifies subrouter prefixes are composed into route paths
+func TestGoASTGorillaSubrouters(t *testing.T) {
+	source := `package server
+
+import "github.com/gorilla/mux"
+
+type server struct {
+	router *mux.Router
+	admin  *mux.Router
+}
+
+func (s *server) routes(r *mux.Router) {
+	r.HandleFunc("/health", health).Methods("GET")
+
+	api := r.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/users", listUsers).Methods("GET")
+
+	v1 := api.PathPrefix("/v1/").Subrouter()
+	v1.HandleFunc("/orders", createOrder).Methods("POST", "PUT")
+	v1.Handle("/items/{id}", itemHandler)
+
+	var reports = v1.PathPrefix("/reports").Subrouter()
+	reports.HandleFunc("", listReports).Methods("GET")
+
+	s.admin = r.PathPrefix("/admin").Subrouter()
+	s.admin.HandleFunc("/stats", stats).Methods("GET")
+
+	r.PathPrefix("/inline").Subrouter().HandleFunc("/ping", ping).Methods("HEAD")
+}
+`
+	want := []struct{ method, path string }{
+		{"GET", "/health"},
+		{"GET", "/api/users"},
+		{"POST", "/api/v1/orders"},
+		{"PUT", "/api/v1/orders"},
+		{"ANY", "/api/v1/items/{id}"},
+		{"GET", "/api/v1/reports"},
+		{"GET", "/admin/stats"},
+		{"HEAD", "/inline/ping"},
+	}
+
+	endpoints := ScanFile("server/routes.go", source)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		if endpoints[i].Method != w.method || endpoints[i].Path != w.path {
+			t.Errorf("endpoint %d = %s %s, want %s %s", i, endpoints[i].Method, endpoints[i].Path, w.method, w.path)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package scanner - go-kit transports and go-micro handlers
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// GoMicroTag tags go-micro handler procedures
+const GoMicroTag = "go-micro"
+
+// gorillaMatchers are the route builder calls that narrow a route without
+// changing its method or path
+var gorillaMatchers = map[string]bool{
+	"Name": true, "Queries": true, "Headers": true, "HeadersRegexp": true,
+	"Schemes": true, "Host": true, "MatcherFunc": true,
+}
+
+// visitRouteBuilder records a gorilla route built method-first and ending in
+// its handler, the wiring go-kit services use for their transports:
+//
+//	r.Methods("POST").Path("/profiles/").Handler(httptransport.NewServer(...))
+//
+// Chains without a literal Path, or with builder calls it doesn't know, are
+// skipped.
+func (x *goExtractor) visitRouteBuilder(call *ast.CallExpr, locals map[string]string) {
+	var methods []string
+	path, warning, hasPath := "", "", false
+
+	expr := call.Fun.(*ast.SelectorExpr).X
+	for {
+		link, ok := expr.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		sel, ok := link.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		switch name := sel.Sel.Name; {
+		case name == "Methods":
+			for _, arg := range link.Args {
+				if method, ok := x.resolveMethod(arg, locals); ok {
+					methods = append(methods, method)
+				}
+			}
+		case name == "Path" && len(link.Args) == 1:
+			if path, warning, hasPath = x.resolvePath(link.Args[0], locals); !hasPath {
+				return
+			}
+		case gorillaMatchers[name]:
+		default:
+			// A subrouter or another router's method: not a route chain
+			return
+		}
+		expr = sel.X
+	}
+	if !hasPath {
+		return
+	}
+
+	prefixLine := 0
+	if prefix := x.routerPrefix(expr, locals); prefix != "" {
+		path = joinRoutePath(prefix, path)
+		prefixLine = x.routerPrefixLine(expr, locals)
+	}
+	if len(methods) == 0 {
+		methods = []string{"ANY"}
+	}
+	site := call
+	if x.site != nil {
+		site = x.site
+	}
+	for _, method := range methods {
+		x.record(x.fset.Position(site.Pos()).Line, prefixLine, method, path, warning)
+	}
+}
+
+// goMicroMethods returns the exported methods of each type declared in file
+// that have the go-micro handler signature:
+//
+//	func (g *Greeter) Hello(ctx context.Context, req *Request, rsp *Response) error
+func goMicroMethods(file *ast.File) map[string][]*ast.FuncDecl {
+	methods := make(map[string][]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || !fn.Name.IsExported() {
+			continue
+		}
+		var params []ast.Expr
+		for _, field := range fn.Type.Params.List {
+			for range max(len(field.Names), 1) {
+				params = append(params, field.Type)
+			}
+		}
+		results := fn.Type.Results
+		if len(params) != 3 || types.ExprString(params[0]) != "context.Context" ||
+			results == nil || len(results.List) != 1 || types.ExprString(results.List[0].Type) != "error" {
+			continue
+		}
+		recv := strings.TrimPrefix(types.ExprString(fn.Recv.List[0].Type), "*")
+		methods[recv] = append(methods[recv], fn)
+	}
+	return methods
+}
+
+// visitMicroHandler records the procedures of a go-micro handler registered
+// by call, in any of its forms:
+//
+//	micro.RegisterHandler(service.Server(), new(Greeter))
+//	pb.RegisterGreeterHandler(service.Server(), &Greeter{})
+//	server.NewHandler(new(Greeter))
+//
+// Only handlers whose type is declared in the same file are followed, so the
+// procedures are the type's methods with the handler signature.
+func (x *goExtractor) visitMicroHandler(call *ast.CallExpr) {
+	name := call.Fun.(*ast.SelectorExpr).Sel.Name
+	handler := call.Args[len(call.Args)-1]
+	service := ""
+	switch {
+	case name == "NewHandler":
+		handler = call.Args[0]
+	case name == "RegisterHandler" && len(call.Args) >= 2:
+		handler = call.Args[1]
+	case len(call.Args) >= 2:
+		// Generated by protoc-gen-micro, named after the proto service
+		handler = call.Args[1]
+		service = strings.TrimSuffix(strings.TrimPrefix(name, "Register"), "Handler")
+	default:
+		return
+	}
+
+	typeName := handlerTypeName(handler)
+	methods := x.microMethods[typeName]
+	if len(methods) == 0 {
+		return
+	}
+	if service == "" {
+		service = typeName
+	}
+	x.handled[call] = true
+	for _, fn := range methods {
+		procedure := service + "." + fn.Name.Name
+		line := x.fset.Position(fn.Pos()).Line
+		x.found = append(x.found, Endpoint{
+			ID:         fmt.Sprintf("%s-RPC-%d", scanID(x.filePath), line),
+			Path:       procedure,
+			Method:     "RPC",
+			FilePath:   x.filePath,
+			LineNumber: line,
+			Tags:       []string{GoMicroTag},
+			RPC:        procedure,
+			Kind:       KindProcedure,
+		})
+	}
+}
+
+// isMicroRegistration reports whether call registers a go-micro handler
+func isMicroRegistration(name string, call *ast.CallExpr) bool {
+	switch {
+	case name == "NewHandler":
+		return len(call.Args) >= 1
+	case strings.HasPrefix(name, "Register") && strings.HasSuffix(name, "Handler"):
+		return len(call.Args) >= 2
+	}
+	return false
+}
+
+// handlerTypeName returns T for new(T), &T{} or T{}, or "" otherwise
+func handlerTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "new" && len(e.Args) == 1 {
+			if t, ok := e.Args[0].(*ast.Ident); ok {
+				return t.Name
+			}
+		}
+	case *ast.UnaryExpr:
+		return handlerTypeName(e.X)
+	case *ast.CompositeLit:
+		if t, ok := e.Type.(*ast.Ident); ok {
+			return t.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import "testing"
+
+// TestGoKitTransports verifies go-kit servers wired onto gorilla routes
+// method-first are extracted, including under a subrouter
+func TestGoKitTransports(t *testing.T) {
+	source := `package profilesvc
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+const addressesPath = "/addresses/"
+
+func MakeHTTPHandler(s Service) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{}
+
+	r.Methods("POST").Path("/profiles/").Handler(httptransport.NewServer(
+		e.PostProfileEndpoint,
+		decodePostProfileRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET", "PUT").Path("/profiles/{id}").Handler(httptransport.NewServer(e.GetProfileEndpoint, decodeGetProfileRequest, encodeResponse))
+	r.Path("/health").Name("health").Handler(health)
+
+	v2 := r.PathPrefix("/v2").Subrouter()
+	v2.Methods("GET").Path("/profiles/{id}" + addressesPath).HandlerFunc(listAddresses)
+	v2.Methods("DELETE").Path(dynamicPath()).Handler(deleter)
+	return r
+}
+
+func wrap(h http.HandlerFunc) http.Handler { return http.HandlerFunc(h) }
+`
+	if !hasAPIIndicators("profilesvc/transport.go", source) {
+		t.Fatal("go-kit transport rejected by the pre-filter")
+	}
+
+	endpoints := ScanFile("profilesvc/transport.go", source)
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"POST", "/profiles/", 17},
+		{"GET", "/profiles/{id}", 23},
+		{"PUT", "/profiles/{id}", 23},
+		{"ANY", "/health", 24},
+		{"GET", "/v2/profiles/{id}/addresses/", 27},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.LineNumber != w.line {
+			t.Errorf("endpoint %d = %s %s (line %d), want %s %s (line %d)", i, ep.Method, ep.Path, ep.LineNumber, w.method, w.path, w.line)
+		}
+	}
+}
+
+// TestGoMicroHandlers verifies the methods of a registered go-micro handler
+// are extracted as procedures, and handlers declared elsewhere are skipped
+func TestGoMicroHandlers(t *testing.T) {
+	source := `package main
+
+import (
+	"context"
+
+	"go-micro.dev/v4"
+	pb "example.com/greeter/proto"
+	"example.com/greeter/other"
+)
+
+type Greeter struct{}
+
+func (g *Greeter) Hello(ctx context.Context, req *pb.Request, rsp *pb.Response) error {
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+func (g *Greeter) Stream(ctx context.Context, req *pb.Request, rsp *pb.Response) error { return nil }
+
+func (g *Greeter) helper(ctx context.Context, a, b int) error { return nil }
+
+func (g *Greeter) String() string { return "greeter" }
+
+type Admin struct{}
+
+func (a Admin) Reset(ctx context.Context, req *pb.Empty, rsp *pb.Empty) error { return nil }
+
+func main() {
+	service := micro.NewService(micro.Name("greeter"))
+	pb.RegisterGreeterHandler(service.Server(), new(Greeter))
+	micro.RegisterHandler(service.Server(), &Admin{})
+	micro.RegisterHandler(service.Server(), new(other.Handler))
+	service.Run()
+}
+`
+	endpoints := ScanFile("main.go", source)
+	want := []struct {
+		path string
+		line int
+	}{
+		{"Greeter.Hello", 13},
+		{"Greeter.Stream", 18},
+		{"Admin.Reset", 26},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Path != w.path || ep.LineNumber != w.line || ep.Kind != KindProcedure || ep.Tags[0] != GoMicroTag {
+			t.Errorf("endpoint %d = %s (line %d, kind %q, tags %v), want %s (line %d) procedure", i, ep.Path, ep.LineNumber, ep.Kind, ep.Tags, w.path, w.line)
+		}
+	}
+}
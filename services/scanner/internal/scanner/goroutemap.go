@@ -0,0 +1,278 @@
+// Package scanner - Go route tables registered by ranging over a map
+package scanner
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// goHandlerTypes are the handler types a route map's values may have
+var goHandlerTypes = map[string]bool{
+	"http.HandlerFunc": true, "http.Handler": true,
+	"gin.HandlerFunc": true, "echo.HandlerFunc": true, "fiber.Handler": true,
+}
+
+// goHandlerParams are the parameter types of handler function values
+var goHandlerParams = map[string]bool{
+	"http.ResponseWriter": true, "*gin.Context": true, "echo.Context": true, "*fiber.Ctx": true,
+}
+
+// routeMap is a route map found registered by call, recorded once the
+// prefixes of the routers in the file are known
+type routeMap struct {
+	call   *ast.CallExpr
+	routes []mapRoute
+}
+
+// mapRoute is one method and path of a route map, declared at line
+type mapRoute struct {
+	line         int
+	method, path string
+}
+
+// findRouteMaps finds handler maps registered in a loop:
+//
+//	routes := map[string]http.HandlerFunc{"/users": listUsers}
+//	for path, h := range routes { mux.HandleFunc(path, h) }
+//
+// A map only counts when its values are handlers, every key is a path, and
+// a range over it passes the key to a registration call. Routes take the
+// loop's method (r.GET, .Methods("POST")), the method of a "GET /users"
+// key, or the keys of a nested map[string]map[string]http.HandlerFunc;
+// otherwise ANY.
+func (x *goExtractor) findRouteMaps(file *ast.File) {
+	maps := make(map[string]*ast.CompositeLit)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if i < len(node.Values) {
+					if lit := routeMapLiteral(node.Values[i]); lit != nil {
+						maps[name.Name] = lit
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					if lit := routeMapLiteral(node.Rhs[i]); lit != nil {
+						maps[ident.Name] = lit
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	done := make(map[*ast.CompositeLit]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		loop, ok := n.(*ast.RangeStmt)
+		if !ok {
+			return true
+		}
+		lit := routeMapLiteral(loop.X)
+		if ident, ok := loop.X.(*ast.Ident); ok {
+			lit = maps[ident.Name]
+		}
+		key, ok := loop.Key.(*ast.Ident)
+		if lit == nil || !ok || done[lit] {
+			return true
+		}
+		calls := registrationsUsing(loop.Body, key.Name)
+		if len(calls) == 0 {
+			return true
+		}
+		if routes, ok := x.routeMapRoutes(lit, calls[0]); ok {
+			done[lit] = true
+			x.routeMaps = append(x.routeMaps, routeMap{call: calls[0], routes: routes})
+			for _, call := range calls {
+				x.handled[call] = true
+			}
+		}
+		return true
+	})
+}
+
+// routeMapLiteral returns expr if it is a map literal with string keys and
+// handler values, directly or keyed by method in a nested map
+func routeMapLiteral(expr ast.Expr) *ast.CompositeLit {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	mapType, ok := lit.Type.(*ast.MapType)
+	if !ok || types.ExprString(mapType.Key) != "string" {
+		return nil
+	}
+	value := mapType.Value
+	if inner, ok := value.(*ast.MapType); ok {
+		if types.ExprString(inner.Key) != "string" {
+			return nil
+		}
+		value = inner.Value
+	}
+	if !isHandlerType(value) {
+		return nil
+	}
+	return lit
+}
+
+// isHandlerType reports whether expr is a handler type or a function type
+// taking a request context or response writer
+func isHandlerType(expr ast.Expr) bool {
+	fn, ok := expr.(*ast.FuncType)
+	if !ok {
+		return goHandlerTypes[types.ExprString(expr)]
+	}
+	for _, field := range fn.Params.List {
+		if goHandlerParams[types.ExprString(field.Type)] {
+			return true
+		}
+	}
+	return false
+}
+
+// registrationsUsing returns the registration calls in body that take the
+// named loop variable as an argument, outermost first
+func registrationsUsing(body *ast.BlockStmt, name string) []*ast.CallExpr {
+	var calls []*ast.CallExpr
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || routeArgs(call) == nil {
+			return true
+		}
+		uses := false
+		for _, arg := range call.Args {
+			if ident, ok := arg.(*ast.Ident); ok && ident.Name == name {
+				uses = true
+			}
+		}
+		// r.HandleFunc(path, h).Methods("GET") passes it to the inner call
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Methods" {
+			if inner, ok := sel.X.(*ast.CallExpr); ok {
+				for _, arg := range inner.Args {
+					if ident, ok := arg.(*ast.Ident); ok && ident.Name == name {
+						calls = append(calls, call, inner)
+						return false
+					}
+				}
+			}
+		}
+		if uses {
+			calls = append(calls, call)
+		}
+		return true
+	})
+	return calls
+}
+
+// registrationMethods returns the methods a registration call fixes, such
+// as r.GET(...) or .Methods("GET", "POST"), or nil when it takes any
+func (x *goExtractor) registrationMethods(call *ast.CallExpr) []string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	switch name := sel.Sel.Name; {
+	case goVerbMethods[name]:
+		return []string{name}
+	case name == "Methods":
+		var methods []string
+		for _, arg := range call.Args {
+			if method, ok := x.resolveMethod(arg, nil); ok {
+				methods = append(methods, method)
+			}
+		}
+		return methods
+	case (name == "Add" || name == "Handle") && len(call.Args) >= 2:
+		if method, ok := x.resolveMethod(call.Args[0], nil); ok {
+			return []string{method}
+		}
+	}
+	return nil
+}
+
+// routeMapRoutes returns the routes of a map literal registered by call,
+// or false when any key isn't a path
+func (x *goExtractor) routeMapRoutes(lit *ast.CompositeLit, call *ast.CallExpr) ([]mapRoute, bool) {
+	var routes []mapRoute
+	loopMethods := x.registrationMethods(call)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+		key, ok := x.resolveString(kv.Key, nil)
+		if !ok {
+			return nil, false
+		}
+		method, path := "", key
+		if verb, rest, found := strings.Cut(key, " "); found && goVerbMethods[verb] {
+			method, path = verb, strings.TrimSpace(rest)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return nil, false
+		}
+		line := x.fset.Position(kv.Key.Pos()).Line
+
+		methods := loopMethods
+		if method != "" {
+			methods = []string{method}
+		}
+		if inner, ok := kv.Value.(*ast.CompositeLit); ok {
+			// Handlers keyed by method: "/users": {"GET": list, "POST": create}
+			methods = nil
+			for _, ielt := range inner.Elts {
+				ikv, ok := ielt.(*ast.KeyValueExpr)
+				if !ok {
+					return nil, false
+				}
+				method, ok := x.resolveMethod(ikv.Key, nil)
+				if !ok {
+					return nil, false
+				}
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			methods = []string{"ANY"}
+		}
+		for _, method := range methods {
+			routes = append(routes, mapRoute{line, method, path})
+		}
+	}
+	return routes, len(routes) > 0
+}
+
+// recordRouteMaps records the routes of the route maps found, under the
+// prefix of the router registering them
+func (x *goExtractor) recordRouteMaps() {
+	for _, m := range x.routeMaps {
+		prefix, prefixLine := x.registrationPrefix(m.call)
+		for _, r := range m.routes {
+			x.record(r.line, prefixLine, r.method, joinRoutePath(prefix, r.path), "")
+		}
+	}
+}
+
+// registrationPrefix returns the prefix and its line of the router a
+// registration call is made on
+func (x *goExtractor) registrationPrefix(call *ast.CallExpr) (string, int) {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		router := sel.X
+		if inner, ok := router.(*ast.CallExpr); ok && sel.Sel.Name == "Methods" {
+			// The router is the receiver of the inner HandleFunc
+			if innerSel, ok := inner.Fun.(*ast.SelectorExpr); ok {
+				router = innerSel.X
+			}
+		}
+		if prefix := x.routerPrefix(router, nil); prefix != "" {
+			return prefix, x.routerPrefixLine(router, nil)
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,91 @@
+package scanner
+
+import "testing"
+
+// TestGoRouteMaps verifies handler maps registered in a loop yield one route
+// per entry, and that other string-keyed maps are left alone
+func TestGoRouteMaps(t *testing.T) {
+	source := `package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/mux"
+)
+
+var publicRoutes = map[string]http.HandlerFunc{
+	"/health":  health,
+	"/version": version,
+}
+
+var labels = map[string]string{
+	"/health": "Health check",
+}
+
+func Register(mux *http.ServeMux, g *gin.Engine, r *mux.Router) {
+	for path, h := range publicRoutes {
+		mux.HandleFunc(path, h)
+	}
+
+	for pattern, h := range map[string]http.HandlerFunc{"POST /webhooks": webhook} {
+		mux.HandleFunc(pattern, h)
+	}
+
+	reads := map[string]gin.HandlerFunc{
+		"/users": listUsers,
+	}
+	for path, h := range reads {
+		g.GET(path, h)
+	}
+
+	api := r.PathPrefix("/api").Subrouter()
+	resources := map[string]map[string]http.HandlerFunc{
+		"/orders": {
+			"GET":  listOrders,
+			"POST": createOrder,
+		},
+	}
+	for path, byMethod := range resources {
+		for method, h := range byMethod {
+			api.HandleFunc(path, h).Methods(method)
+		}
+	}
+
+	// Not routes: values aren't handlers, or keys aren't paths
+	for path, label := range labels {
+		log(path, label)
+	}
+	handlers := map[string]http.HandlerFunc{"users": listUsers}
+	for name, h := range handlers {
+		mux.HandleFunc(name, h)
+	}
+}
+`
+	endpoints := ScanFile("routes/routes.go", source)
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"ANY", "/health", 11},
+		{"ANY", "/version", 12},
+		{"POST", "/webhooks", 24},
+		{"GET", "/users", 29},
+		{"GET", "/api/orders", 37},
+		{"POST", "/api/orders", 37},
+		// The unrecognised map's registration is extracted as before
+		{"ANY", "name", 54},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.LineNumber != w.line {
+			t.Errorf("endpoint %d = %s %s (line %d), want %s %s (line %d)", i, ep.Method, ep.Path, ep.LineNumber, w.method, w.path, w.line)
+		}
+	}
+	if ep := endpoints[4]; ep.PrefixLine != 35 {
+		t.Errorf("/api/orders PrefixLine = %d, want 35", ep.PrefixLine)
+	}
+}
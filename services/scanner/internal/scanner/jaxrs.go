@@ -0,0 +1,50 @@
+// Package scanner - JAX-RS (Quarkus, Jersey, RESTEasy) resource methods
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// The HTTP method is an annotation of its own: @GET, @POST, ...
+	jaxrsMethodPattern = regexp.MustCompile(`@(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\b`)
+	// Method-level path, composed onto the resource class's @Path
+	jaxrsPathPattern = regexp.MustCompile(`@Path\s*\(\s*(?:value\s*=\s*)?"([^"]*)"`)
+)
+
+// extractJAXRSRoutes finds resource methods annotated with an HTTP method,
+// taking the path from a @Path among the same annotations. The resource
+// class's @Path is composed later, like other class prefixes.
+func extractJAXRSRoutes(filePath string, lines []string) []Endpoint {
+	var found []Endpoint
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "@") {
+			continue
+		}
+		m := jaxrsMethodPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		method := m[1]
+
+		path := ""
+		for _, annotation := range decoratorBlock(lines, i) {
+			if p := jaxrsPathPattern.FindStringSubmatch(annotation); p != nil {
+				path = p[1]
+				break
+			}
+		}
+
+		found = append(found, Endpoint{
+			ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, i+1),
+			Path:       path,
+			Method:     method,
+			FilePath:   filePath,
+			LineNumber: i + 1,
+			Tags:       []string{extractTag(filePath, path)},
+		})
+	}
+	return found
+}
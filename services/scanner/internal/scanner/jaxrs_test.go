@@ -0,0 +1,71 @@
+package scanner
+
+import "testing"
+
+// TestJAXRSResources verifies resource methods take their HTTP method from
+// its own annotation and compose class and method @Path values
+func TestJAXRSResources(t *testing.T) {
+	code := `package org.acme;
+
+import jakarta.ws.rs.*;
+import jakarta.ws.rs.core.MediaType;
+
+@Path("/users")
+@Produces(MediaType.APPLICATION_JSON)
+public class UserResource {
+
+    @GET
+    public List<User> list() {
+        return User.listAll();
+    }
+
+    @GET
+    @Path("/{id}")
+    public User get(@PathParam("id") Long id) {
+        return User.findById(id);
+    }
+
+    @POST
+    @Consumes(MediaType.APPLICATION_JSON)
+    public Response create(User user) {
+        return Response.status(201).build();
+    }
+
+    @DELETE @Path("{id}")
+    public void delete(@PathParam("id") Long id) {
+        User.deleteById(id);
+    }
+
+    @Path("/{id}/orders")
+    public OrderResource orders(@PathParam("id") Long id) {
+        return new OrderResource(id);
+    }
+}
+`
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"GET", "/users", 10},
+		{"GET", "/users/{id}", 15},
+		{"POST", "/users", 21},
+		{"DELETE", "/users/{id}", 27},
+	}
+
+	if !hasAPIIndicators("UserResource.java", code) {
+		t.Fatal("JAX-RS resource rejected by the pre-filter")
+	}
+	endpoints := ScanFile("src/main/java/org/acme/UserResource.java", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path || ep.LineNumber != want[i].line {
+			t.Errorf("endpoint %d = %s %s at line %d, want %s %s at line %d",
+				i, ep.Method, ep.Path, ep.LineNumber, want[i].method, want[i].path, want[i].line)
+		}
+		if ep.PrefixLine != 6 {
+			t.Errorf("%s %s prefix line = %d, want 6", ep.Method, ep.Path, ep.PrefixLine)
+		}
+	}
+}
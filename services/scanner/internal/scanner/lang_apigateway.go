@@ -0,0 +1,55 @@
+// Package scanner - AWS API Gateway OpenAPI extractor
+package scanner
+
+import (
+	"regexp"
+)
+
+var (
+	apiGatewayIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`["']?x-amazon-apigateway-(?:integration|any-method)["']?\s*:`),
+		// Any OpenAPI document declaring webhooks or callbacks
+		regexp.MustCompile(`(?m)^\s*["']?(?:webhooks|callbacks)["']?\s*:`),
+		// Any Swagger 2.0 document: swagger: "2.0"
+		regexp.MustCompile(`(?m)^\s*["']?swagger["']?\s*:\s*["']?2\.0`),
+	}
+
+	apiGatewayKeywords = []string{apiGatewayExtensionPrefix, "webhooks", "callbacks", "swagger"}
+)
+
+// apiGatewayLanguage extracts routes from OpenAPI documents deployed to AWS
+// API Gateway and from Swagger 2.0 documents, and the webhooks and callbacks
+// of any OpenAPI document. They're claimed by the usual spec file names;
+// OpenAPI 3 specs with neither the API Gateway extensions nor webhooks or
+// callbacks are rejected by the pre-filter.
+type apiGatewayLanguage struct{}
+
+func init() {
+	RegisterExtractor(apiGatewayLanguage{})
+}
+
+func (apiGatewayLanguage) Name() string                 { return "API Gateway" }
+func (apiGatewayLanguage) Extensions() []string         { return nil }
+func (apiGatewayLanguage) Indicators() []*regexp.Regexp { return apiGatewayIndicators }
+func (apiGatewayLanguage) Keywords() []string           { return apiGatewayKeywords }
+
+// FileNames implements FileNameExtractor
+func (apiGatewayLanguage) FileNames() []string {
+	return []string{
+		"openapi.json", "openapi.yml", "openapi.yaml",
+		"swagger.json", "swagger.yml", "swagger.yaml",
+		"api.json", "api.yml", "api.yaml",
+		"apigateway.json", "apigateway.yml", "apigateway.yaml",
+	}
+}
+
+// Extract implements LanguageExtractor
+func (apiGatewayLanguage) Extract(filePath, content string) []Endpoint {
+	return extractAPIGatewayRoutes(filePath, content, nil)
+}
+
+// ExtractRefs implements RefExtractor, following path items $ref-ed into
+// other files of the repository
+func (apiGatewayLanguage) ExtractRefs(filePath, content string, load FileLoader) []Endpoint {
+	return extractAPIGatewayRoutes(filePath, content, load)
+}
@@ -0,0 +1,42 @@
+// Package scanner - C# extractor (ASP.NET Web API)
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	csharpIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`\[(HttpGet|HttpPost|HttpPut|HttpPatch|HttpDelete)\]`),
+		regexp.MustCompile(`\[Route\(`),
+		regexp.MustCompile(`\[ApiController\]`),
+	}
+
+	csharpKeywords = []string{"[Http", "[Route", "[ApiController"}
+
+	csharpPatterns = []*regexp.Regexp{
+		// ASP.NET Web API
+		regexp.MustCompile(`\[(HttpGet|HttpPost|HttpPut|HttpPatch|HttpDelete)\s*\(\s*"([^"]+)"\s*\)\]`),
+		regexp.MustCompile(`\[Route\s*\(\s*"([^"]+)"\s*\)\]`),
+	}
+)
+
+func init() {
+	RegisterExtractor(&regexExtractor{
+		name:       "C#",
+		extensions: []string{".cs"},
+		indicators: csharpIndicators,
+		keywords:   csharpKeywords,
+		patterns:   csharpPatterns,
+		parse:      parseCSharpMatch,
+	})
+}
+
+// parseCSharpMatch handles [HttpGet("...")] and method-less [Route("...")]
+func parseCSharpMatch(line string, matches []string) (string, string, bool) {
+	if len(matches) >= 3 {
+		return strings.ToUpper(strings.TrimPrefix(matches[1], "Http")), matches[2], true
+	}
+	return "ANY", matches[1], true
+}
@@ -0,0 +1,36 @@
+// Package scanner - Elixir extractor (Phoenix router)
+package scanner
+
+import (
+	"regexp"
+)
+
+var (
+	elixirIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`use\s+Phoenix\.Router`),
+		regexp.MustCompile(`use\s+[\w.]+,\s*:router`),
+		regexp.MustCompile(`\bpipe_through\b`),
+		regexp.MustCompile(`\bscope\s*\(?\s*"`),
+	}
+
+	elixirKeywords = []string{"Phoenix.Router", ":router", "pipe_through", "scope"}
+)
+
+// elixirLanguage extracts Phoenix routes, whose paths depend on the
+// enclosing scope and resources blocks
+type elixirLanguage struct{}
+
+func init() {
+	RegisterExtractor(elixirLanguage{})
+}
+
+func (elixirLanguage) Name() string                 { return "Elixir" }
+func (elixirLanguage) Extensions() []string         { return []string{".ex", ".exs"} }
+func (elixirLanguage) Indicators() []*regexp.Regexp { return elixirIndicators }
+func (elixirLanguage) Keywords() []string           { return elixirKeywords }
+
+// Extract implements LanguageExtractor
+func (elixirLanguage) Extract(filePath, content string) []Endpoint {
+	lines := sourceLines(content)
+	return excludeCommentedRoutes(extOf(filePath), extractPhoenixRoutes(filePath, lines), lines)
+}
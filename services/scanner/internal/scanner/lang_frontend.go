@@ -0,0 +1,86 @@
+// Package scanner - Vue/Svelte extractor (client-side API calls)
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// KindClientCall marks endpoints a frontend calls rather than serves
+const KindClientCall = "client-call"
+
+var (
+	frontendIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:\$?fetch|useFetch)\s*\(`),
+		regexp.MustCompile(`\baxios(?:\.(?:get|post|put|patch|delete|head|options))?\s*\(`),
+	}
+
+	frontendKeywords = []string{"fetch", "axios"}
+
+	// Only literal URLs count: the string must be the whole argument, so
+	// concatenations and template interpolations are skipped
+	frontendPatterns = []*regexp.Regexp{
+		// axios.get('/api/x')
+		regexp.MustCompile(`\baxios\.(get|post|put|patch|delete|head|options)\s*\(\s*(?:'([^'$]+)'|"([^"$]+)"|\x60([^\x60$]+)\x60)\s*[,)]`),
+		// fetch('/api/x', { method: 'POST' }), Nuxt $fetch/useFetch
+		regexp.MustCompile(`\b(?:\$?fetch|useFetch)\s*\(\s*(?:'([^'$]+)'|"([^"$]+)"|\x60([^\x60$]+)\x60)\s*[,)]`),
+	}
+
+	fetchMethodPattern = regexp.MustCompile(`\bmethod\s*:\s*['"](\w+)['"]`)
+)
+
+// frontendLanguage extracts API client calls from single-file components
+type frontendLanguage struct {
+	calls *regexExtractor
+}
+
+func init() {
+	RegisterExtractor(&frontendLanguage{
+		calls: &regexExtractor{
+			name:       "Vue/Svelte",
+			extensions: []string{".vue", ".svelte"},
+			indicators: frontendIndicators,
+			keywords:   frontendKeywords,
+			patterns:   frontendPatterns,
+			parse:      parseClientCall,
+		},
+	})
+}
+
+func (f *frontendLanguage) Name() string                 { return f.calls.Name() }
+func (f *frontendLanguage) Extensions() []string         { return f.calls.Extensions() }
+func (f *frontendLanguage) Indicators() []*regexp.Regexp { return f.calls.Indicators() }
+func (f *frontendLanguage) Keywords() []string           { return f.calls.Keywords() }
+
+// Extract implements LanguageExtractor
+func (f *frontendLanguage) Extract(filePath, content string) []Endpoint {
+	lines := sourceLines(content)
+	found := excludeCommentedRoutes(extOf(filePath), f.calls.extractLines(filePath, lines), lines)
+	for i := range found {
+		found[i].Kind = KindClientCall
+	}
+	return found
+}
+
+// parseClientCall reads the method from axios.<verb> or fetch's options
+func parseClientCall(line string, matches []string) (string, string, bool) {
+	var method string
+	urls := matches[1:]
+	if len(matches) == 5 {
+		// axios.<verb>(url)
+		method = strings.ToUpper(matches[1])
+		urls = matches[2:]
+	} else {
+		method = "GET"
+		if m := fetchMethodPattern.FindStringSubmatch(line); m != nil {
+			method = strings.ToUpper(m[1])
+		}
+	}
+
+	for _, url := range urls {
+		if url != "" {
+			return method, url, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const vueFixture = `<template>
+  <button @click="save">Save</button>
+</template>
+
+<script>
+import axios from 'axios'
+
+export default {
+  async mounted() {
+    this.users = await fetch('/api/users').then(r => r.json())
+    this.stats = await axios.get("/api/stats")
+  },
+  methods: {
+    async save() {
+      await fetch('/api/users', { method: 'POST', body: JSON.stringify(this.user) })
+      await axios.delete(` + "`/api/sessions/current`" + `)
+      await fetch('/api/users/' + this.id)
+      await axios.get(` + "`/api/users/${this.id}`" + `)
+      await fetch(this.endpoint)
+    },
+  },
+}
+</script>
+`
+
+// TestVueClientCalls verifies literal fetch/axios URLs become client calls
+func TestVueClientCalls(t *testing.T) {
+	if !hasAPIIndicators("src/components/Users.vue", vueFixture) {
+		t.Fatal("hasAPIIndicators = false for Vue component with API calls")
+	}
+
+	found := ScanFile("src/components/Users.vue", vueFixture)
+
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"GET", "/api/users", 10},
+		{"GET", "/api/stats", 11},
+		{"POST", "/api/users", 15},
+		{"DELETE", "/api/sessions/current", 16},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("found %d client calls; want %d: %+v", len(found), len(want), found)
+	}
+	for i, w := range want {
+		ep := found[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.LineNumber != w.line {
+			t.Errorf("call %d = %s %s (line %d); want %s %s (line %d)", i, ep.Method, ep.Path, ep.LineNumber, w.method, w.path, w.line)
+		}
+		if ep.Kind != KindClientCall {
+			t.Errorf("%s %s kind = %q; want %q", ep.Method, ep.Path, ep.Kind, KindClientCall)
+		}
+	}
+}
+
+// TestSvelteClientCalls verifies .svelte files are handled too
+func TestSvelteClientCalls(t *testing.T) {
+	found := ScanFile("src/routes/+page.svelte", `<script>
+  const res = await fetch("/api/todos", { method: "PATCH" });
+</script>
+`)
+	if len(found) != 1 || found[0].Method != "PATCH" || found[0].Path != "/api/todos" || found[0].Kind != KindClientCall {
+		t.Errorf("found = %+v", found)
+	}
+}
@@ -0,0 +1,42 @@
+// Package scanner - Declarative route config extractor (Ocelot, Kong, routes.yaml)
+package scanner
+
+import (
+	"regexp"
+)
+
+var (
+	gatewayIndicators = []*regexp.Regexp{
+		// Keys are matched as cased, as the config parsers read them
+		regexp.MustCompile(`"?(?:UpstreamPathTemplate|paths|path)"?\s*:`),
+	}
+
+	gatewayKeywords = []string{"UpstreamPathTemplate", "paths", "path"}
+)
+
+// gatewayLanguage extracts routes declared in gateway and routing config
+// files. They're claimed by name; other JSON and YAML files are ignored.
+type gatewayLanguage struct{}
+
+func init() {
+	RegisterExtractor(gatewayLanguage{})
+}
+
+func (gatewayLanguage) Name() string                 { return "Route config" }
+func (gatewayLanguage) Extensions() []string         { return nil }
+func (gatewayLanguage) Indicators() []*regexp.Regexp { return gatewayIndicators }
+func (gatewayLanguage) Keywords() []string           { return gatewayKeywords }
+
+// FileNames implements FileNameExtractor
+func (gatewayLanguage) FileNames() []string {
+	return []string{
+		ocelotConfigFile,
+		"kong.yml", "kong.yaml",
+		"routes.json", "routes.yml", "routes.yaml",
+	}
+}
+
+// Extract implements LanguageExtractor
+func (gatewayLanguage) Extract(filePath, content string) []Endpoint {
+	return extractGatewayRoutes(filePath, content)
+}
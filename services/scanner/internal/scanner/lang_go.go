@@ -0,0 +1,85 @@
+// Package scanner - Go extractor (Gin, Echo, Fiber, gorilla/mux, net/http)
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	goIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`\.(GET|POST|PUT|PATCH|DELETE|OPTIONS|HEAD)\s*\(`),
+		regexp.MustCompile(`\bHandleFunc\s*\(`),
+		regexp.MustCompile(`\bServeHTTP\b`),
+		regexp.MustCompile(`"github\.com/(gin-gonic|labstack|gofiber)`),
+		regexp.MustCompile(`"github\.com/go-kit/kit/transport/http"|"(?:go-micro\.dev|github\.com/(?:micro|asim)/go-micro)`),
+		regexp.MustCompile(`//\s*@Router\s`),
+		regexp.MustCompile(`\.(?:Add|Handle)\s*\(\s*(?:\w+\.Method\w+|"(?:GET|POST|PUT|PATCH|DELETE|OPTIONS|HEAD)")`),
+	}
+
+	goKeywords = []string{".GET", ".POST", ".PUT", ".PATCH", ".DELETE", ".OPTIONS", ".HEAD", "HandleFunc", "ServeHTTP", "gin-gonic", "labstack", "gofiber", "go-kit", "go-micro", "@Router", ".Add", ".Handle"}
+
+	// Regex fallback for sources that don't parse
+	goPatterns = []*regexp.Regexp{
+		// Generic registration with a method argument: router.Add(http.MethodGet, "/x", h)
+		regexp.MustCompile(`\w+\.(?:Add|Handle)\s*\(\s*(?:\w+\.Method)?"?((?i:get|post|put|patch|delete|options|head))"?\s*,\s*["']([^"']+)["']`),
+		// Gin, Echo - method-specific
+		regexp.MustCompile(`\w+\.(GET|POST|PUT|PATCH|DELETE|OPTIONS|HEAD)\s*\(\s*["']([^"']+)["']`),
+		// Standard library
+		regexp.MustCompile(`HandleFunc\s*\(\s*["']([^"']+)["']`),
+		// Gorilla mux
+		regexp.MustCompile(`Handle(?:Func)?\s*\(\s*["']([^"']+)["'].*?\.Methods\s*\(\s*["']([^"']+)["']`),
+	}
+)
+
+// goLanguage extracts Go routes from the AST, falling back to regex
+// patterns for fragments and code that doesn't parse
+type goLanguage struct {
+	fallback *regexExtractor
+}
+
+func init() {
+	RegisterExtractor(&goLanguage{
+		fallback: &regexExtractor{
+			name:       "Go",
+			extensions: []string{".go"},
+			indicators: goIndicators,
+			keywords:   goKeywords,
+			patterns:   goPatterns,
+			parse:      parseGoMatch,
+		},
+	})
+}
+
+func (g *goLanguage) Name() string                 { return g.fallback.Name() }
+func (g *goLanguage) Extensions() []string         { return g.fallback.Extensions() }
+func (g *goLanguage) Indicators() []*regexp.Regexp { return g.fallback.Indicators() }
+func (g *goLanguage) Keywords() []string           { return g.fallback.Keywords() }
+
+// Extract implements LanguageExtractor
+func (g *goLanguage) Extract(filePath, content string) []Endpoint {
+	lines := sourceLines(content)
+
+	// The AST extractor flags conditional registrations precisely; the
+	// environment checks guarding them are read from the source
+	if found, ok := extractGoAST(filePath, content); ok {
+		found = applySwagAnnotations(found, lines, filePath)
+		markEnvironmentRoutes(".go", found, lines)
+		return found
+	}
+
+	// Swaggo routes are declared in comments, so they're added after
+	found := excludeCommentedRoutes(".go", g.fallback.extractLines(filePath, lines), lines)
+	found = applySwagAnnotations(found, lines, filePath)
+	markConditionalRoutes(".go", found, lines)
+	return found
+}
+
+// parseGoMatch handles (method, path) patterns and method-less HandleFunc
+func parseGoMatch(line string, matches []string) (string, string, bool) {
+	if len(matches) >= 3 {
+		return strings.ToUpper(matches[1]), matches[2], true
+	}
+	// HandleFunc - no method specified
+	return "ANY", matches[1], true
+}
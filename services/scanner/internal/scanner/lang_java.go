@@ -0,0 +1,151 @@
+// Package scanner - Java extractor (Spring Boot, Micronaut, JAX-RS/Quarkus)
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	javaIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`@(GetMapping|PostMapping|PutMapping|PatchMapping|DeleteMapping|RequestMapping)`),
+		regexp.MustCompile(`@RestController`),
+		regexp.MustCompile(`@Controller`),
+		regexp.MustCompile(`@Path\s*\(`),
+	}
+
+	javaKeywords = []string{"Mapping", "Controller", "@Path"}
+
+	javaPatterns = []*regexp.Regexp{
+		// Spring Boot method-level mappings
+		regexp.MustCompile(`@(GetMapping|PostMapping|PutMapping|PatchMapping|DeleteMapping)\s*\(\s*(?:value\s*=\s*)?["']([^"'\)]+)["']`),
+		// Method-level @RequestMapping naming its method, as openapi-generator
+		// stubs declare routes; a bare @RequestMapping is a class prefix
+		regexp.MustCompile(`@RequestMapping\s*\(([^)]*\bmethod\s*=\s*\{?\s*RequestMethod\.\w+[^)]*)\)`),
+		// Micronaut: @Get("/{id}"), @Post(uri = "/"), or a bare @Delete on the controller path
+		regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Head|Options)\b(?:\s*\(\s*(?:(?:value|uri)\s*=\s*)?["']([^"']*)["'])?`),
+	}
+
+	// @RequestMapping arguments: method = RequestMethod.POST, value = "/pet"
+	requestMethodPattern  = regexp.MustCompile(`\bRequestMethod\.(\w+)`)
+	requestMappingPattern = regexp.MustCompile(`\b(?:value|path)\s*=\s*\{?\s*"([^"]*)"`)
+
+	// Server stubs from openapi-generator or swagger-codegen:
+	// @Generated(value = "org.openapitools.codegen.languages.SpringCodegen")
+	generatedStubPattern = regexp.MustCompile(`@(?:javax\.annotation\.|jakarta\.annotation\.)?Generated\s*\(\s*(?:value\s*=\s*)?\{?\s*"(?:org\.openapitools|io\.swagger)\.codegen|(?i)auto[- ]generated by (?:the )?(?:OpenAPI Generator|Swagger Codegen)`)
+	// Operation summaries: @ApiOperation(value = "Add a pet"), @Operation(summary = "Add a pet")
+	apiOperationSummaryPattern = regexp.MustCompile(`^@(?:ApiOperation\s*\(\s*(?:value\s*=\s*)?|Operation\s*\(.*?\bsummary\s*=\s*)"([^"]+)"`)
+
+	// Class-level prefixes: Spring @RequestMapping("/api"), @RequestMapping(path = {"/api"}),
+	// Micronaut @Controller("/api") (a Spring @Controller's value is a bean
+	// name, so only paths count) and JAX-RS @Path("/api")
+	javaClassPrefixPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^\s*@RequestMapping\s*\(\s*(?:(?:value|path)\s*=\s*)?\{?\s*["']([^"']*)["']`),
+		regexp.MustCompile(`^\s*@Controller\s*\(\s*(?:value\s*=\s*)?["']((?:/|\$\{)[^"']*)["']`),
+		regexp.MustCompile(`^\s*@Path\s*\(\s*(?:value\s*=\s*)?"([^"]*)"`),
+	}
+	// Class declarations, anchored so "class" in a comment isn't one
+	javaClassPattern = regexp.MustCompile(`^\s*(?:(?:public|protected|private|abstract|final|static)\s+)*(?:class|interface)\s+\w+`)
+)
+
+func init() {
+	RegisterExtractor(&regexExtractor{
+		name:           "Java",
+		extensions:     []string{".java"},
+		indicators:     javaIndicators,
+		keywords:       javaKeywords,
+		patterns:       javaPatterns,
+		parse:          parseJavaMatch,
+		allowEmptyPath: true, // Micronaut @Get() inherits the controller path
+		joinDecorators: true,
+		refine:         refineJavaRoutes,
+	})
+}
+
+// refineJavaRoutes adds JAX-RS routes, composes class prefixes and captures
+// the operation summaries and generated-stub marker openapi-generator emits
+func refineJavaRoutes(filePath string, found []Endpoint, lines []string) []Endpoint {
+	found = append(found, extractJAXRSRoutes(filePath, lines)...)
+	found = applyJavaClassPaths(filePath, found, lines)
+	generated := generatedStubPattern.MatchString(strings.Join(lines, "\n"))
+	for i := range found {
+		found[i].Generated = generated
+		for _, annotation := range decoratorBlock(lines, found[i].LineNumber-1) {
+			if m := apiOperationSummaryPattern.FindStringSubmatch(annotation); m != nil {
+				found[i].Summary = m[1]
+			}
+		}
+	}
+	return found
+}
+
+// applyJavaClassPaths prefixes handler routes with the @RequestMapping,
+// @Controller or @Path path of the class declaring them
+func applyJavaClassPaths(filePath string, found []Endpoint, lines []string) []Endpoint {
+	for i := range found {
+		ep := &found[i]
+		prefix, line, ok := javaClassPath(lines, ep.LineNumber-1)
+		if !ok {
+			continue
+		}
+		ep.Path = joinPathSegments(prefix, ep.Path)
+		ep.PrefixLine = line
+		ep.Tags = []string{extractTag(filePath, ep.Path)}
+	}
+	return found
+}
+
+// javaClassPath returns the path and 1-based line of the prefix annotation
+// on the nearest class declared above lines[i]
+func javaClassPath(lines []string, i int) (string, int, bool) {
+	for j := min(i, len(lines)-1); j >= 0; j-- {
+		if !javaClassPattern.MatchString(lines[j]) {
+			continue
+		}
+		// The class's own annotations sit directly above it
+		for k := j - 1; k >= 0; k-- {
+			code := strings.TrimSpace(lines[k])
+			for _, pattern := range javaClassPrefixPatterns {
+				if m := pattern.FindStringSubmatch(lines[k]); m != nil {
+					return resolvePropertyDefault(m[1]), k + 1, true
+				}
+			}
+			if code != "" && !strings.HasPrefix(code, "@") && !strings.HasPrefix(code, "//") {
+				break
+			}
+		}
+		return "", 0, false
+	}
+	return "", 0, false
+}
+
+// springPlaceholderPattern matches a property placeholder with a default:
+// ${openapi.petstore.base-path:/v2}
+var springPlaceholderPattern = regexp.MustCompile(`\$\{[^:}]*:([^}]*)\}`)
+
+// resolvePropertyDefault replaces property placeholders with their defaults,
+// as generated stubs declare their base path
+func resolvePropertyDefault(path string) string {
+	return springPlaceholderPattern.ReplaceAllString(path, "$1")
+}
+
+// parseJavaMatch derives the method from the mapping annotation (GetMapping -> GET)
+func parseJavaMatch(line string, matches []string) (string, string, bool) {
+	if strings.HasPrefix(matches[0], "@RequestMapping") {
+		method := requestMethodPattern.FindStringSubmatch(matches[1])
+		path := requestMappingPattern.FindStringSubmatch(matches[1])
+		if method == nil || path == nil {
+			return "", "", false
+		}
+		return method[1], path[1], true
+	}
+	if len(matches) < 3 {
+		// @RequestMapping with just a path
+		return "GET", matches[1], true
+	}
+	annotation := matches[1]
+	if strings.HasSuffix(annotation, "Mapping") {
+		return strings.ToUpper(strings.TrimSuffix(annotation, "Mapping")), matches[2], true
+	}
+	return strings.ToUpper(annotation), matches[2], true
+}
@@ -0,0 +1,91 @@
+// Package scanner - JavaScript/TypeScript extractor (Express, Fastify, NestJS, AdonisJS, tRPC, swagger-jsdoc)
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	jsIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`\.(get|post|put|patch|delete|options|head|all)\s*\(`),
+		regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Options|Head|All|Controller)\b`),
+		regexp.MustCompile(`\b(Router|express|fastify)\s*\(`),
+		regexp.MustCompile(`from\s+['"](@nestjs|express|fastify)`),
+		regexp.MustCompile(`import.*\{.*Router.*\}`),
+		regexp.MustCompile(`\bRoute\.resource\s*\(`),
+		regexp.MustCompile(`\b(?:router|createTRPCRouter|mergeRouters)\s*\(`),
+	}
+
+	jsKeywords = []string{".get", ".post", ".put", ".patch", ".delete", ".options", ".head", ".all", "@", "Router", "express", "fastify", "Route.resource", "router"}
+
+	jsPatterns = []*regexp.Regexp{
+		// Express/Fastify - any variable name
+		regexp.MustCompile(`\w+\.(get|post|put|patch|delete|options|head|all)\s*\(\s*["'\x60]([^"'\x60]+)["'\x60]`),
+		// NestJS decorators
+		regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Options|Head|All)\s*\(\s*["']?([^"'\)]*?)["']?\s*\)`),
+	}
+
+	// NestJS: @Controller('users'), @Controller({ path: 'users' }), @Controller()
+	nestControllerPattern = regexp.MustCompile(`@Controller\s*\(\s*(?:\{[^}]*?\bpath\s*:\s*)?(?:["'\x60]([^"'\x60]*)["'\x60])?`)
+)
+
+func init() {
+	RegisterExtractor(&regexExtractor{
+		name:           "JavaScript/TypeScript",
+		extensions:     []string{".js", ".ts", ".jsx", ".tsx"},
+		indicators:     jsIndicators,
+		keywords:       jsKeywords,
+		patterns:       jsPatterns,
+		parse:          parseJSMatch,
+		allowEmptyPath: true,
+		joinDecorators: true,
+		refine:         applyNestControllerPaths,
+		annotations:    applyJSDocAnnotations,
+		extra: func(filePath string, lines []string) []Endpoint {
+			ext := extOf(filePath)
+			found := append(extractResourceRoutes(ext, filePath, lines), extractRegexRoutes(ext, filePath, lines)...)
+			return append(found, extractTRPCRoutes(filePath, lines)...)
+		},
+	})
+}
+
+// parseJSMatch handles patterns capturing (method, path). NestJS @All
+// matches every method.
+func parseJSMatch(line string, matches []string) (string, string, bool) {
+	if len(matches) >= 3 && matches[1] == "All" {
+		return "ANY", matches[2], true
+	}
+	return methodPathParser(line, matches)
+}
+
+// applyNestControllerPaths prefixes NestJS handler routes with the path of
+// the controller declared above them. An empty or "/" handler path is the
+// controller's own path.
+func applyNestControllerPaths(filePath string, found []Endpoint, lines []string) []Endpoint {
+	for i := range found {
+		ep := &found[i]
+		if ep.LineNumber < 1 || !strings.HasPrefix(strings.TrimSpace(lines[ep.LineNumber-1]), "@") {
+			continue // Express and other call-style routes
+		}
+		prefix, line, ok := nestControllerPath(lines, ep.LineNumber-1)
+		if !ok && ep.Path != "" {
+			continue
+		}
+		ep.Path = joinPathSegments(prefix, ep.Path)
+		ep.PrefixLine = line
+		ep.Tags = []string{extractTag(filePath, ep.Path)}
+	}
+	return found
+}
+
+// nestControllerPath returns the path and 1-based line of the nearest
+// @Controller above lines[i]
+func nestControllerPath(lines []string, i int) (string, int, bool) {
+	for j := i; j >= 0; j-- {
+		if m := nestControllerPattern.FindStringSubmatch(lines[j]); m != nil {
+			return m[1], j + 1, true
+		}
+	}
+	return "", 0, false
+}
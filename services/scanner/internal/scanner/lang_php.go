@@ -0,0 +1,34 @@
+// Package scanner - PHP extractor (Laravel)
+package scanner
+
+import (
+	"regexp"
+)
+
+var (
+	phpIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`\bRoute::\w+\s*\(`),
+		regexp.MustCompile(`use\s+Illuminate\\Support\\Facades\\Route`),
+	}
+
+	phpKeywords = []string{"Route::", "Illuminate"}
+
+	phpPatterns = []*regexp.Regexp{
+		// Laravel route facade (resources are expanded separately)
+		regexp.MustCompile(`Route::(get|post|put|patch|delete|options|any)\s*\(\s*['"]([^'"]+)['"]`),
+	}
+)
+
+func init() {
+	RegisterExtractor(&regexExtractor{
+		name:       "PHP",
+		extensions: []string{".php"},
+		indicators: phpIndicators,
+		keywords:   phpKeywords,
+		patterns:   phpPatterns,
+		parse:      methodPathParser,
+		extra: func(filePath string, lines []string) []Endpoint {
+			return extractResourceRoutes(".php", filePath, lines)
+		},
+	})
+}
@@ -0,0 +1,31 @@
+// Package scanner - Protobuf extractor (gRPC-Gateway)
+package scanner
+
+import (
+	"regexp"
+)
+
+var (
+	protoIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`google\.api\.http`),
+	}
+
+	protoKeywords = []string{"google.api.http"}
+)
+
+// protoLanguage extracts grpc-gateway bindings, which span several lines
+type protoLanguage struct{}
+
+func init() {
+	RegisterExtractor(protoLanguage{})
+}
+
+func (protoLanguage) Name() string                 { return "Proto (gRPC-Gateway)" }
+func (protoLanguage) Extensions() []string         { return []string{".proto"} }
+func (protoLanguage) Indicators() []*regexp.Regexp { return protoIndicators }
+func (protoLanguage) Keywords() []string           { return protoKeywords }
+
+// Extract implements LanguageExtractor
+func (protoLanguage) Extract(filePath, content string) []Endpoint {
+	return extractProtoRoutes(filePath, sourceLines(content))
+}
@@ -0,0 +1,117 @@
+// Package scanner - Python extractor (FastAPI, Flask, flask-smorest, Django)
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	pythonIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`@\w+\.(get|post|put|patch|delete|options|head)`),
+		regexp.MustCompile(`@\w+\.route`),
+		regexp.MustCompile(`\.(add_url_rule|add_api_route)\s*\(`),
+		regexp.MustCompile(`\b(path|re_path)\s*\(`),
+		regexp.MustCompile(`\b(APIRouter|Blueprint)\b`),
+		regexp.MustCompile(`from\s+fastapi\s+import`),
+		regexp.MustCompile(`from\s+flask\s+import`),
+	}
+
+	pythonKeywords = []string{"@", "path", "APIRouter", "Blueprint", "fastapi", "flask", "add_url_rule", "add_api_route"}
+
+	pythonPatterns = []*regexp.Regexp{
+		// FastAPI - flexible variable names, positional or path= keyword (f-strings are flagged dynamic)
+		regexp.MustCompile(`@\w+\.(get|post|put|patch|delete|options|head)\s*\(\s*(?:[^)]*?\bpath\s*=\s*)?[rRfF]{0,2}["']([^"']+)["']`),
+		// Flask - route with methods, positional or rule= keyword
+		regexp.MustCompile(`@\w+\.route\s*\(\s*(?:[^)]*?\brule\s*=\s*)?[rRfF]{0,2}["']([^"']+)["'].*?methods\s*=\s*\[["']([^"'\]]+)["']`),
+		// Flask - simple route (defaults to GET)
+		regexp.MustCompile(`@\w+\.route\s*\(\s*(?:[^)]*?\brule\s*=\s*)?[rRfF]{0,2}["']([^"']+)["']`),
+		// Django URL patterns (re_path is handled as a regex route)
+		regexp.MustCompile(`\bpath\s*\(\s*[fF]?["']([^"']+)["']`),
+	}
+
+	// Imperative registration: app.add_url_rule('/x', view_func=v, methods=['POST']),
+	// router.add_api_route('/x', handler, methods=['GET'])
+	pythonImperativePattern  = regexp.MustCompile(`\b\w+\.(?:add_url_rule|add_api_route)\s*\(\s*(?:[^)]*?\b(?:rule|path)\s*=\s*)?["']([^"']+)["']`)
+	pythonMethodsListPattern = regexp.MustCompile(`\bmethods\s*=\s*[\[({]([^\])}]*)`)
+)
+
+func init() {
+	RegisterExtractor(&regexExtractor{
+		name:           "Python",
+		extensions:     []string{".py"},
+		indicators:     pythonIndicators,
+		keywords:       pythonKeywords,
+		patterns:       pythonPatterns,
+		parse:          parsePythonMatch,
+		joinDecorators: true,
+		refine:         refinePythonRoutes,
+		extra: func(filePath string, lines []string) []Endpoint {
+			return append(extractImperativeRoutes(filePath, lines), extractRegexRoutes(".py", filePath, lines)...)
+		},
+	})
+}
+
+// parsePythonMatch works out which Python pattern matched
+func parsePythonMatch(line string, matches []string) (string, string, bool) {
+	switch {
+	case strings.Contains(line, ".route") && strings.Contains(line, "methods") && len(matches) == 3:
+		// Flask with methods: @bp.route('/path', methods=['GET'])
+		// Pattern captures: path, method
+		return strings.ToUpper(matches[2]), matches[1], true
+	case strings.Contains(line, "@") && strings.Contains(line, ".route") && len(matches) == 2:
+		// Flask simple route (no method specified), Flask defaults to GET
+		return "GET", matches[1], true
+	case len(matches) >= 3:
+		// FastAPI/Flask: @app.get('/path')
+		// Pattern captures: method, path
+		return strings.ToUpper(matches[1]), matches[2], true
+	case strings.Contains(line, "path("):
+		// Django path - no method in pattern
+		// Default GET (Django views specify method in view function)
+		return "GET", matches[1], true
+	}
+	return "", "", false
+}
+
+// extractImperativeRoutes finds Flask add_url_rule and FastAPI add_api_route
+// registrations, emitting one endpoint per listed method (GET when none are)
+func extractImperativeRoutes(filePath string, lines []string) []Endpoint {
+	var found []Endpoint
+	for i, line := range lines {
+		if !strings.Contains(line, "add_url_rule") && !strings.Contains(line, "add_api_route") {
+			continue
+		}
+		// The path and methods may be on the call's continuation lines
+		call := balancedLine(lines, i)
+		m := pythonImperativePattern.FindStringSubmatchIndex(call)
+		if m == nil || m[0] >= len(line) {
+			continue
+		}
+		path := call[m[2]:m[3]]
+
+		methods := []string{"GET"}
+		if mm := pythonMethodsListPattern.FindStringSubmatch(call); mm != nil {
+			methods = nil
+			for _, q := range quotedStringPattern.FindAllStringSubmatch(mm[1], -1) {
+				method := strings.ToUpper(q[1])
+				if !containsString(methods, method) {
+					methods = append(methods, method)
+				}
+			}
+		}
+
+		for _, method := range methods {
+			found = append(found, Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, i+1),
+				Path:       path,
+				Method:     method,
+				FilePath:   filePath,
+				LineNumber: i + 1,
+				Tags:       []string{extractTag(filePath, path)},
+			})
+		}
+	}
+	return found
+}
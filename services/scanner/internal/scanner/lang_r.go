@@ -0,0 +1,81 @@
+// Package scanner - R extractor (Plumber)
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	rIndicators = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^\s*#[*']\s*@(?:get|post|put|patch|delete|head|options)\s+/`),
+		regexp.MustCompile(`\bpr_(?:get|post|put|patch|delete|head|options)\s*\(`),
+	}
+
+	rKeywords = []string{"#*", "#'", "pr_"}
+
+	rPatterns = []*regexp.Regexp{
+		// Plumber annotations: #* @get /data
+		regexp.MustCompile(`^\s*#[*']\s*@(get|post|put|patch|delete|head|options)\s+(/\S*)`),
+		// Programmatic routers: pr() %>% pr_get("/data", handler), pr_post(pr, "/data", handler)
+		regexp.MustCompile(`\bpr_(get|post|put|patch|delete|head|options)\s*\(\s*(?:[\w.]+\s*,\s*)?["']([^"']+)["']`),
+	}
+
+	// Plumber block lines: #* text or #* @tag value
+	plumberCommentPattern = regexp.MustCompile(`^\s*#[*']\s?(.*)$`)
+	plumberTagPattern     = regexp.MustCompile(`^@tag\s+(\S+)`)
+	// Typed dynamic segments name the parameter first: <id:int>
+	plumberTypedParamPattern = regexp.MustCompile(`<(\w+):\w+>`)
+)
+
+func init() {
+	RegisterExtractor(&regexExtractor{
+		name:       "R",
+		extensions: []string{".r"},
+		indicators: rIndicators,
+		keywords:   rKeywords,
+		patterns:   rPatterns,
+		parse:      methodPathParser,
+		refine:     applyPlumberBlocks,
+	})
+}
+
+// applyPlumberBlocks reads the Plumber comment block around each annotated
+// route: its first plain line is the summary and @tag lines its tags. Types
+// are dropped from dynamic segments, which otherwise read as Flask's
+// <type:name>.
+func applyPlumberBlocks(filePath string, found []Endpoint, lines []string) []Endpoint {
+	for i := range found {
+		ep := &found[i]
+		ep.Path = plumberTypedParamPattern.ReplaceAllString(ep.Path, "<$1>")
+		idx := ep.LineNumber - 1
+		if !plumberCommentPattern.MatchString(lines[idx]) {
+			continue // pr_get() and friends
+		}
+		start, end := idx, idx
+		for start > 0 && plumberCommentPattern.MatchString(lines[start-1]) {
+			start--
+		}
+		for end+1 < len(lines) && plumberCommentPattern.MatchString(lines[end+1]) {
+			end++
+		}
+
+		var tags []string
+		for _, line := range lines[start : end+1] {
+			text := strings.TrimSpace(plumberCommentPattern.FindStringSubmatch(line)[1])
+			switch {
+			case text == "":
+			case strings.HasPrefix(text, "@"):
+				if m := plumberTagPattern.FindStringSubmatch(text); m != nil {
+					tags = append(tags, m[1])
+				}
+			case ep.Summary == "":
+				ep.Summary = text
+			}
+		}
+		if len(tags) > 0 {
+			ep.Tags = tags
+		}
+	}
+	return found
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+const rPlumber = `library(plumber)
+
+#* Echo back the input
+#* @tag echo
+#* @param msg The message to echo
+#* @get /echo
+function(msg = "") {
+  list(msg = paste0("The message is: '", msg, "'"))
+}
+
+#* Return the sum of two numbers
+#* @post /sum
+#* @put /sum
+function(a, b) {
+  as.numeric(a) + as.numeric(b)
+}
+
+#' @get /data/<id:int>
+function(id) {
+  data[id, ]
+}
+
+# @get /not-an-annotation
+pr() %>%
+  pr_delete("/cache", function() clear_cache())
+`
+
+// TestPlumberRoutes verifies Plumber annotations and pr_*() routes are
+// extracted with their block's summary and tags
+func TestPlumberRoutes(t *testing.T) {
+	if !hasAPIIndicators("api/plumber.R", rPlumber) {
+		t.Fatal("Plumber file should have API indicators")
+	}
+
+	var got []string
+	for _, ep := range ScanFile("api/plumber.R", rPlumber) {
+		got = append(got, ep.Method+" "+ep.Path)
+		switch ep.Method + " " + ep.Path {
+		case "GET /echo":
+			if ep.Summary != "Echo back the input" || !reflect.DeepEqual(ep.Tags, []string{"echo"}) {
+				t.Errorf("GET /echo Summary = %q, Tags = %v", ep.Summary, ep.Tags)
+			}
+		case "PUT /sum":
+			if ep.Summary != "Return the sum of two numbers" {
+				t.Errorf("PUT /sum Summary = %q", ep.Summary)
+			}
+		}
+	}
+	want := []string{"GET /echo", "POST /sum", "PUT /sum", "GET /data/{id}", "DELETE /cache"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+}
+
+// TestDisabledLanguages verifies a disabled extractor claims no files
+func TestDisabledLanguages(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.DisabledLanguages = map[string]bool{"r": true}
+
+	if _, ok := extractorFor("api/plumber.R"); ok {
+		t.Error("disabled R extractor still claims .R files")
+	}
+	if eps := ScanFile("api/plumber.R", rPlumber); len(eps) != 0 {
+		t.Errorf("ScanFile() = %d endpoints, want none", len(eps))
+	}
+	if _, ok := extractorFor("main.go"); !ok {
+		t.Error("other extractors should stay enabled")
+	}
+}
@@ -0,0 +1,118 @@
+// Package scanner - Endpoint distribution across languages
+package scanner
+
+import (
+	"sort"
+	"strings"
+)
+
+// LanguageReport summarises a polyglot scan: how many endpoints each
+// language declares and which paths more than one language implements
+type LanguageReport struct {
+	Languages     []LanguageCount `json:"languages"`      // most endpoints first
+	CrossLanguage []PathGroup     `json:"cross_language"` // paths implemented in several languages
+}
+
+// LanguageCount is one language's share of the endpoints
+type LanguageCount struct {
+	Language  string `json:"language"`
+	Endpoints int    `json:"endpoints"`
+	Files     int    `json:"files"`
+}
+
+// PathGroup is a normalized path and every declaration of it
+type PathGroup struct {
+	Path            string               `json:"path"`
+	Languages       []string             `json:"languages"`
+	Implementations []PathImplementation `json:"implementations"`
+}
+
+// PathImplementation is one declaration within a PathGroup
+type PathImplementation struct {
+	Language   string `json:"language"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	FilePath   string `json:"file_path"`
+	LineNumber int    `json:"line_number"`
+}
+
+// languageOf names the language a file was extracted as
+func languageOf(filePath string) string {
+	if x, ok := extractorFor(filePath); ok {
+		return x.Name()
+	}
+	return extOf(filePath)
+}
+
+// BuildLanguageReport groups endpoints by language and by normalized path.
+// Client calls are left out: a frontend calling a backend route isn't a
+// second implementation of it.
+func BuildLanguageReport(eps []Endpoint) LanguageReport {
+	counts := make(map[string]*LanguageCount)
+	files := make(map[string]bool)
+	groups := make(map[string]*PathGroup)
+
+	for _, ep := range eps {
+		if ep.Kind == KindClientCall {
+			continue
+		}
+		lang := languageOf(ep.FilePath)
+
+		count := counts[lang]
+		if count == nil {
+			count = &LanguageCount{Language: lang}
+			counts[lang] = count
+		}
+		count.Endpoints++
+		if !files[ep.FilePath] {
+			files[ep.FilePath] = true
+			count.Files++
+		}
+
+		// Django-style paths have no leading slash
+		key := canonicalPath("/" + strings.TrimPrefix(ep.Path, "/"))
+		group := groups[key]
+		if group == nil {
+			group = &PathGroup{Path: key}
+			groups[key] = group
+		}
+		if !containsString(group.Languages, lang) {
+			group.Languages = append(group.Languages, lang)
+		}
+		group.Implementations = append(group.Implementations, PathImplementation{
+			Language:   lang,
+			Method:     ep.Method,
+			Path:       ep.Path,
+			FilePath:   ep.FilePath,
+			LineNumber: ep.LineNumber,
+		})
+	}
+
+	report := LanguageReport{
+		Languages:     make([]LanguageCount, 0, len(counts)),
+		CrossLanguage: []PathGroup{},
+	}
+	for _, count := range counts {
+		report.Languages = append(report.Languages, *count)
+	}
+	sort.Slice(report.Languages, func(i, j int) bool {
+		a, b := report.Languages[i], report.Languages[j]
+		if a.Endpoints != b.Endpoints {
+			return a.Endpoints > b.Endpoints
+		}
+		return a.Language < b.Language
+	})
+
+	for _, group := range groups {
+		if len(group.Languages) < 2 {
+			continue
+		}
+		sort.Strings(group.Languages)
+		report.CrossLanguage = append(report.CrossLanguage, *group)
+	}
+	sort.Slice(report.CrossLanguage, func(i, j int) bool {
+		return report.CrossLanguage[i].Path < report.CrossLanguage[j].Path
+	})
+
+	return report
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBuildLanguageReport verifies paths declared in Go and Python are grouped
+func TestBuildLanguageReport(t *testing.T) {
+	goCode := `package main
+
+func routes(r *gin.Engine) {
+	r.GET("/users/:id", getUser)
+	r.GET("/health", health)
+}
+`
+	pyCode := `from fastapi import APIRouter
+
+router = APIRouter()
+
+@router.get("/users/{user_id}")
+def get_user(user_id: int):
+    pass
+
+@router.post("/reports")
+def create_report():
+    pass
+`
+	var eps []Endpoint
+	eps = append(eps, ScanFile("gateway/main.go", goCode)...)
+	eps = append(eps, ScanFile("backend/users.py", pyCode)...)
+	eps = append(eps, Endpoint{Method: "GET", Path: "/reports", FilePath: "web/App.vue", Kind: KindClientCall})
+
+	report := BuildLanguageReport(eps)
+
+	wantLanguages := []LanguageCount{
+		{Language: "Go", Endpoints: 2, Files: 1},
+		{Language: "Python", Endpoints: 2, Files: 1},
+	}
+	if !reflect.DeepEqual(report.Languages, wantLanguages) {
+		t.Errorf("languages = %+v, want %+v", report.Languages, wantLanguages)
+	}
+
+	if len(report.CrossLanguage) != 1 {
+		t.Fatalf("cross-language groups = %+v, want one", report.CrossLanguage)
+	}
+	group := report.CrossLanguage[0]
+	if group.Path != "/users/{}" {
+		t.Errorf("group path = %q, want /users/{}", group.Path)
+	}
+	if !reflect.DeepEqual(group.Languages, []string{"Go", "Python"}) {
+		t.Errorf("group languages = %v, want [Go Python]", group.Languages)
+	}
+	if len(group.Implementations) != 2 {
+		t.Errorf("group implementations = %+v, want 2", group.Implementations)
+	}
+}
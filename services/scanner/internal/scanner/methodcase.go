@@ -0,0 +1,80 @@
+// Package scanner - Output casing and matching of HTTP methods
+package scanner
+
+import "strings"
+
+// Method casing options. Extractors always produce uppercase methods, which
+// stay the canonical form for matching; casing is applied on output.
+const (
+	MethodCaseUpper    = "upper"    // GET
+	MethodCaseLower    = "lower"    // get
+	MethodCasePreserve = "preserve" // as written at the declaration, e.g. get in @app.get
+)
+
+// applyMethodCase rewrites each endpoint's method in the requested casing
+func applyMethodCase(found []Endpoint, lines []string, methodCase string) {
+	for i := range found {
+		switch methodCase {
+		case MethodCaseLower:
+			found[i].Method = strings.ToLower(found[i].Method)
+		case MethodCasePreserve:
+			if n := found[i].LineNumber; n > 0 && n <= len(lines) {
+				found[i].Method = sourceMethod(lines[n-1], found[i].Method)
+			}
+		default:
+			found[i].Method = strings.ToUpper(found[i].Method)
+		}
+	}
+}
+
+// sourceMethod returns method as spelled on a declaration line, or method
+// unchanged when the line doesn't name it as a word of its own
+func sourceMethod(line, method string) string {
+	n := len(method)
+	if n == 0 {
+		return method
+	}
+	for i := 0; i+n <= len(line); i++ {
+		if !strings.EqualFold(line[i:i+n], method) {
+			continue
+		}
+		if (i == 0 || !isWordByte(line[i-1])) && (i+n == len(line) || !isWordByte(line[i+n])) {
+			return line[i : i+n]
+		}
+	}
+	return method
+}
+
+// isWordByte reports whether c is a letter, digit or underscore
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// sameMethod compares methods regardless of output casing
+func sameMethod(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// knownMethods are the methods extractors report: HTTP verbs, ANY/ALL for
+// routes matching every method, and tRPC procedure types
+var knownMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true,
+	"OPTIONS": true, "TRACE": true, "CONNECT": true, "ANY": true, "ALL": true,
+	TRPCQuery: true, TRPCMutation: true, TRPCSubscription: true,
+}
+
+// KnownMethod reports whether method, in any casing, is one extractors report
+func KnownMethod(method string) bool {
+	return knownMethods[strings.ToUpper(method)]
+}
+
+// FilterByMethod returns the endpoints with the given method, in any casing
+func FilterByMethod(eps []Endpoint, method string) []Endpoint {
+	matched := []Endpoint{}
+	for _, ep := range eps {
+		if sameMethod(ep.Method, method) {
+			matched = append(matched, ep)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"testing"
+)
+
+// TestMethodCase verifies endpoint methods follow the configured casing
+func TestMethodCase(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	code := `@app.get("/users")
+def list_users():
+    pass
+
+@app.route("/orders", methods=["Post"])
+def create_order():
+    pass
+`
+	tests := []struct {
+		methodCase string
+		want       []string
+	}{
+		{MethodCaseUpper, []string{"GET", "POST"}},
+		{MethodCaseLower, []string{"get", "post"}},
+		{MethodCasePreserve, []string{"get", "Post"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.methodCase, func(t *testing.T) {
+			config.MethodCase = tt.methodCase
+			endpoints := ScanFile("app.py", code)
+			if len(endpoints) != len(tt.want) {
+				t.Fatalf("ScanFile() found %d endpoints, want %d", len(endpoints), len(tt.want))
+			}
+			for i, ep := range endpoints {
+				if ep.Method != tt.want[i] {
+					t.Errorf("endpoint %d method = %q, want %q", i, ep.Method, tt.want[i])
+				}
+			}
+
+			// Matching stays case-insensitive whatever the output casing
+			tree := BuildPathTree(append(endpoints, Endpoint{Method: "GET", Path: "/users"}))
+			for _, node := range tree.Children {
+				if node.Segment == "users" && len(node.Methods) != 1 {
+					t.Errorf("tree methods for /users = %v, want one", node.Methods)
+				}
+			}
+		})
+	}
+}
+
+// TestSourceMethod verifies the method is found only as a word of its own
+func TestSourceMethod(t *testing.T) {
+	tests := []struct{ line, method, want string }{
+		{`@app.get("/users")`, "GET", "get"},
+		{`methods=["Post"]`, "POST", "Post"},
+		{`router.getUsers("/users")`, "GET", "GET"},
+		{`r.forget("/x"); r.Get("/y")`, "GET", "Get"},
+		{`Get`, "GET", "Get"},
+		{`app.get(`, "", ""},
+	}
+	for _, tt := range tests {
+		if got := sourceMethod(tt.line, tt.method); got != tt.want {
+			t.Errorf("sourceMethod(%q, %q) = %q, want %q", tt.line, tt.method, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package scanner - Ordered middleware chains applied to routes
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// The first call on a line whose first argument is a path literal:
+	// router.post('/users', ...), r.GET("/users", ...), mux.Handle("/users", ...)
+	routeCallPattern = regexp.MustCompile(`\.(\w+)\s*\(\s*["'\x60]`)
+	// chi: r.With(logger, auth).Get("/users", h)
+	chiWithPattern = regexp.MustCompile(`\.With\s*\(`)
+	// NestJS: @UseGuards(AuthGuard('jwt'), RolesGuard)
+	nestMiddlewarePattern = regexp.MustCompile(`^@Use(Guards|Interceptors|Pipes)\s*\(`)
+
+	// A middleware reference: auth, passport.authenticate, or the callee of
+	// rateLimit({ max: 5 }), new ValidationPipe()
+	middlewareIdentPattern  = regexp.MustCompile(`^(?:new\s+)?([\w$]+(?:\.[\w$]+)*)\s*(\(|$)`)
+	echoImportPattern       = regexp.MustCompile(`"github\.com/labstack/echo`)
+	goHandleFuncs           = map[string]bool{"Handle": true, "HandleFunc": true}
+	goHandlerAdapters       = map[string]bool{"http.HandlerFunc": true}
+	nestMiddlewareKindOrder = []string{"Guards", "Interceptors", "Pipes"}
+)
+
+// applyMiddleware sets each route's Middleware to the ordered chain a
+// request passes through before reaching its handler. Best-effort: Express
+// and Fastify arguments between the path and the handler, NestJS guards,
+// interceptors and pipes in the order Nest runs them (controller before
+// route within each kind), and Go middleware arguments, chi's With and
+// net/http handler wrapping from the outside in. Router-wide use() calls are
+// not attributed to routes.
+func applyMiddleware(ext string, found []Endpoint, lines []string) {
+	switch ext {
+	case ".js", ".ts":
+		classLevel := nestClassMiddleware(lines)
+		for i := range found {
+			idx := found[i].LineNumber - 1
+			if idx < 0 || idx >= len(lines) {
+				continue
+			}
+			if strings.HasPrefix(strings.TrimSpace(lines[idx]), "@") {
+				found[i].Middleware = nestMiddleware(classLevel, lines, idx)
+				continue
+			}
+			if args := routeCallArgs(balancedLine(lines, idx)); len(args) > 2 {
+				found[i].Middleware = middlewareNames(args[1 : len(args)-1])
+			}
+		}
+
+	case ".go":
+		echo := false
+		for _, line := range lines {
+			if echoImportPattern.MatchString(line) {
+				echo = true
+				break
+			}
+		}
+		for i := range found {
+			idx := found[i].LineNumber - 1
+			if idx < 0 || idx >= len(lines) {
+				continue
+			}
+			found[i].Middleware = goMiddleware(balancedLine(lines, idx), echo)
+		}
+	}
+}
+
+// routeCallArgs returns the top-level arguments of the first call in line
+// taking a path literal first, or nil
+func routeCallArgs(line string) []string {
+	m := routeCallPattern.FindStringIndex(line)
+	if m == nil {
+		return nil
+	}
+	return callArgs(line, strings.IndexByte(line[m[0]:], '(')+m[0])
+}
+
+// callArgs returns the trimmed top-level arguments of the call whose
+// parenthesis is at s[open]
+func callArgs(s string, open int) []string {
+	end := jsBlockEnd(s, open)
+	if end < 0 {
+		end = len(s)
+	}
+	var args []string
+	for _, span := range splitTopLevel(s, open+1, end) {
+		args = append(args, strings.TrimSpace(s[span[0]:span[1]]))
+	}
+	return args
+}
+
+// middlewareNames names the middleware arguments, flattening arrays and
+// leaving out inline functions
+func middlewareNames(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "[") && strings.HasSuffix(arg, "]") {
+			names = append(names, middlewareNames(callArgs("("+arg[1:len(arg)-1]+")", 0))...)
+			continue
+		}
+		if m := middlewareIdentPattern.FindStringSubmatch(arg); m != nil && m[1] != "function" && m[1] != "async" {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// nestDecorator is a @UseGuards/@UseInterceptors/@UsePipes decorator
+type nestDecorator struct {
+	line  int // index of the decorator's line
+	kind  string
+	names []string
+}
+
+// nestClassMiddleware returns the middleware decorators applied to classes
+func nestClassMiddleware(lines []string) []nestDecorator {
+	var decorators []nestDecorator
+	for i, line := range lines {
+		if !nestMiddlewarePattern.MatchString(strings.TrimSpace(line)) || !annotatesClass(lines, i) {
+			continue
+		}
+		if d, ok := parseNestDecorator(strings.TrimSpace(balancedLine(lines, i))); ok {
+			d.line = i
+			decorators = append(decorators, d)
+		}
+	}
+	return decorators
+}
+
+// parseNestDecorator reads a decorator joined onto one line
+func parseNestDecorator(decorator string) (nestDecorator, bool) {
+	m := nestMiddlewarePattern.FindStringSubmatchIndex(decorator)
+	if m == nil {
+		return nestDecorator{}, false
+	}
+	return nestDecorator{
+		kind:  decorator[m[2]:m[3]],
+		names: middlewareNames(callArgs(decorator, m[1]-1)),
+	}, true
+}
+
+// nestMiddleware orders the guards, interceptors and pipes of the handler
+// decorated at lines[idx] as Nest runs them: by kind, and within a kind the
+// controller's before the handler's
+func nestMiddleware(classLevel []nestDecorator, lines []string, idx int) []string {
+	route := make(map[string][]string)
+	for _, decorator := range decoratorBlock(lines, idx) {
+		if d, ok := parseNestDecorator(decorator); ok {
+			route[d.kind] = append(route[d.kind], d.names...)
+		}
+	}
+
+	var chain []string
+	for _, kind := range nestMiddlewareKindOrder {
+		for _, d := range classLevel {
+			if d.kind == kind && d.line < idx {
+				chain = append(chain, d.names...)
+			}
+		}
+		chain = append(chain, route[kind]...)
+	}
+	return chain
+}
+
+// goMiddleware reads the middleware of a Go route registration: chi's With,
+// then the arguments around the handler (after it for Echo, before it
+// otherwise), or the wrappers around an http.Handle handler
+func goMiddleware(line string, echo bool) []string {
+	m := routeCallPattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return nil
+	}
+	var chain []string
+	if with := chiWithPattern.FindStringIndex(line[:m[0]+1]); with != nil {
+		chain = middlewareNames(callArgs(line, with[1]-1))
+	}
+
+	args := callArgs(line, strings.IndexByte(line[m[0]:], '(')+m[0])
+	switch {
+	case len(args) < 2:
+	case goHandleFuncs[line[m[2]:m[3]]]:
+		chain = append(chain, goHandlerWrappers(args[1])...)
+	case echo:
+		chain = append(chain, middlewareNames(args[2:])...)
+	default:
+		chain = append(chain, middlewareNames(args[1:len(args)-1])...)
+	}
+	return chain
+}
+
+// goHandlerWrappers unwraps logging(auth(http.HandlerFunc(h))) into the
+// single-argument calls around the handler, outermost first
+func goHandlerWrappers(handler string) []string {
+	var chain []string
+	for {
+		m := middlewareIdentPattern.FindStringSubmatch(handler)
+		if m == nil || m[2] != "(" {
+			return chain
+		}
+		args := callArgs(handler, strings.IndexByte(handler, '('))
+		if len(args) != 1 {
+			return chain
+		}
+		if !goHandlerAdapters[m[1]] {
+			chain = append(chain, m[1])
+		}
+		handler = args[0]
+	}
+}
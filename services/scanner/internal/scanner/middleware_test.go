@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMiddlewareChains verifies the ordered middleware of routes across
+// frameworks
+func TestMiddlewareChains(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    map[string][]string // by method and path
+	}{
+		{
+			name: "express",
+			file: "routes/users.js",
+			content: `const express = require('express');
+const router = express.Router();
+
+router.get('/users', requestLogger, listUsers);
+router.post('/users', requestLogger, rateLimit({ windowMs: 60000, max: 5 }), validate(createUserSchema), passport.authenticate('jwt', { session: false }), createUser);
+router.delete('/users/:id',
+  [requestLogger, requireAdmin],
+  async (req, res) => {
+    res.sendStatus(204);
+  });
+router.put('/users/:id', (req, res, next) => next(), updateUser);
+router.get('/health', (req, res) => res.send('ok'));
+`,
+			want: map[string][]string{
+				"GET /users":         {"requestLogger"},
+				"POST /users":        {"requestLogger", "rateLimit", "validate", "passport.authenticate"},
+				"DELETE /users/{id}": {"requestLogger", "requireAdmin"},
+				"PUT /users/{id}":    nil,
+				"GET /health":        nil,
+			},
+		},
+		{
+			name: "nestjs",
+			file: "src/users.controller.ts",
+			content: `import { Controller, Get, Post, UseGuards, UseInterceptors, UsePipes } from '@nestjs/common';
+
+@Controller('users')
+@UseInterceptors(LoggingInterceptor)
+@UseGuards(AuthGuard('jwt'))
+export class UsersController {
+  @Get()
+  findAll() {}
+
+  @Post()
+  @UsePipes(new ValidationPipe({ whitelist: true }))
+  @UseGuards(
+    RolesGuard,
+    ThrottlerGuard,
+  )
+  create() {}
+}
+`,
+			want: map[string][]string{
+				"GET /users":  {"AuthGuard", "LoggingInterceptor"},
+				"POST /users": {"AuthGuard", "RolesGuard", "ThrottlerGuard", "LoggingInterceptor", "ValidationPipe"},
+			},
+		},
+		{
+			name: "gin",
+			file: "main.go",
+			content: `package main
+
+import "github.com/gin-gonic/gin"
+
+func main() {
+	r := gin.Default()
+	r.GET("/users", listUsers)
+	r.POST("/users", middleware.RateLimit(5), authRequired(), createUser)
+	r.Run()
+}
+`,
+			want: map[string][]string{
+				"GET /users":  nil,
+				"POST /users": {"middleware.RateLimit", "authRequired"},
+			},
+		},
+		{
+			name: "net/http",
+			file: "server.go",
+			content: `package main
+
+import "net/http"
+
+func main() {
+	mux := http.NewServeMux()
+	mux.Handle("/users", logging(auth(http.HandlerFunc(listUsers))))
+	http.ListenAndServe(":8080", mux)
+}
+`,
+			want: map[string][]string{
+				"ANY /users": {"logging", "auth"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string][]string)
+			for _, ep := range ScanFile(tt.file, tt.content) {
+				got[ep.Method+" "+ep.Path] = ep.Middleware
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Middleware = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package scanner - OpenAPI and Swagger 2.0 document parsing and drift validation
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// openAPIMethods are the operation keys of an OpenAPI path item
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// specOperation is one method+path declared by an OpenAPI document
+type specOperation struct {
+	Method string
+	Path   string
+	Op     map[string]any
+}
+
+// parseSpecDocument decodes an OpenAPI/Swagger document in JSON or YAML
+func parseSpecDocument(data []byte) (map[string]any, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: empty")
+	}
+	if _, ok := doc["paths"]; !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: missing paths")
+	}
+	return doc, nil
+}
+
+// isSwagger2 reports whether a document is a Swagger 2.0 one rather than OpenAPI 3
+func isSwagger2(doc map[string]any) bool {
+	// swagger: 2.0 unquoted in YAML decodes as a number
+	return strings.HasPrefix(fmt.Sprint(doc["swagger"]), "2")
+}
+
+// specBasePath returns the basePath a Swagger 2.0 document serves its paths
+// under. OpenAPI 3 documents declare servers instead, which aren't prefixed.
+func specBasePath(doc map[string]any) string {
+	if !isSwagger2(doc) {
+		return ""
+	}
+	base, _ := doc["basePath"].(string)
+	return strings.TrimSuffix(base, "/")
+}
+
+// specSchemaName returns the name of the schema a schema object refers to,
+// in #/definitions (Swagger 2.0) or #/components/schemas (OpenAPI 3).
+// Arrays of a named schema are named Schema[].
+func specSchemaName(schema any) string {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if ref, ok := obj["$ref"].(string); ok {
+		for _, prefix := range []string{"#/definitions/", "#/components/schemas/"} {
+			if name, found := strings.CutPrefix(ref, prefix); found {
+				return name
+			}
+		}
+		return ""
+	}
+	if name := specSchemaName(obj["items"]); name != "" {
+		return name + "[]"
+	}
+	return ""
+}
+
+// specOperationModels returns the schema names of an operation's request
+// body and successful response. Swagger 2.0 declares the body as an in: body
+// parameter and the response schema directly; OpenAPI 3 nests both under
+// content by media type.
+func specOperationModels(op map[string]any) (input, response string) {
+	params, _ := op["parameters"].([]any)
+	for _, p := range params {
+		if param, _ := p.(map[string]any); param["in"] == "body" {
+			input = specSchemaName(param["schema"])
+		}
+	}
+	if body, ok := op["requestBody"].(map[string]any); ok && input == "" {
+		input = specContentSchemaName(body["content"])
+	}
+
+	responses, _ := op["responses"].(map[string]any)
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		r, _ := responses[code].(map[string]any)
+		if response = specSchemaName(r["schema"]); response == "" {
+			response = specContentSchemaName(r["content"])
+		}
+		if response != "" {
+			break
+		}
+	}
+	return input, response
+}
+
+// specContentSchemaName returns the schema name of the first media type of
+// an OpenAPI 3 content map that names one
+func specContentSchemaName(content any) string {
+	media, _ := content.(map[string]any)
+	types := make([]string, 0, len(media))
+	for mediaType := range media {
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+	for _, mediaType := range types {
+		m, _ := media[mediaType].(map[string]any)
+		if name := specSchemaName(m["schema"]); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// specOperations lists the operations of a parsed document in path order,
+// under the basePath of a Swagger 2.0 document
+func specOperations(doc map[string]any) []specOperation {
+	paths, _ := doc["paths"].(map[string]any)
+	base := specBasePath(doc)
+
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+
+	var ops []specOperation
+	for _, path := range keys {
+		item, _ := paths[path].(map[string]any)
+		for _, method := range openAPIMethods {
+			if op, ok := item[method].(map[string]any); ok {
+				ops = append(ops, specOperation{Method: strings.ToUpper(method), Path: joinRoutePath(base, path), Op: op})
+			}
+		}
+	}
+	return ops
+}
+
+// RouteKey identifies a route by method and path
+type RouteKey struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// ValidationReport lists the drift between scanned code and an OpenAPI document
+type ValidationReport struct {
+	Valid           bool       `json:"valid"`
+	MissingFromSpec []RouteKey `json:"missing_from_spec"` // in code, not documented
+	MissingFromCode []RouteKey `json:"missing_from_code"` // documented, not implemented
+}
+
+// ValidateAgainstSpec compares endpoints with an OpenAPI document, keyed on
+// method and normalized path. Code routes with method ANY match any method.
+func ValidateAgainstSpec(eps []Endpoint, spec []byte) (*ValidationReport, error) {
+	doc, err := parseSpecDocument(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	specRoutes := make(map[string]RouteKey)
+	specPaths := make(map[string]bool)
+	for _, op := range specOperations(doc) {
+		specRoutes[op.Method+" "+canonicalPath(op.Path)] = RouteKey{op.Method, op.Path}
+		specPaths[canonicalPath(op.Path)] = true
+	}
+
+	report := &ValidationReport{
+		MissingFromSpec: []RouteKey{},
+		MissingFromCode: []RouteKey{},
+	}
+	matched := make(map[string]bool)
+	anyPaths := make(map[string]bool)
+	seen := make(map[string]bool)
+
+	for _, ep := range eps {
+		path := canonicalPath(ep.Path)
+		method := strings.ToUpper(ep.Method)
+		if method == "ANY" {
+			anyPaths[path] = true
+			if !specPaths[path] && !seen["ANY "+path] {
+				report.MissingFromSpec = append(report.MissingFromSpec, RouteKey{method, ep.Path})
+			}
+			seen["ANY "+path] = true
+			continue
+		}
+
+		key := method + " " + path
+		if _, ok := specRoutes[key]; ok {
+			matched[key] = true
+		} else if !seen[key] {
+			report.MissingFromSpec = append(report.MissingFromSpec, RouteKey{method, ep.Path})
+		}
+		seen[key] = true
+	}
+
+	for key, route := range specRoutes {
+		if !matched[key] && !anyPaths[canonicalPath(route.Path)] {
+			report.MissingFromCode = append(report.MissingFromCode, route)
+		}
+	}
+	sort.Slice(report.MissingFromCode, func(i, j int) bool {
+		a, b := report.MissingFromCode[i], report.MissingFromCode[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	report.Valid = len(report.MissingFromSpec) == 0 && len(report.MissingFromCode) == 0
+	return report, nil
+}
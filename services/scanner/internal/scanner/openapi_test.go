@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const openAPIUsersSpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users:
+    get:
+      summary: List users
+  /users/{user_id}:
+    post:
+      summary: Create user
+  /users/{user_id}/avatar:
+    get:
+      summary: Get avatar
+`
+
+// TestValidateAgainstSpec verifies drift is reported in both directions
+func TestValidateAgainstSpec(t *testing.T) {
+	eps := ScanFile("routes/users.py", pythonFastAPI)
+	eps = append(eps, Endpoint{Method: "DELETE", Path: "/users/:id"})
+
+	report, err := ValidateAgainstSpec(eps, []byte(openAPIUsersSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Valid {
+		t.Error("report should not be valid")
+	}
+	if len(report.MissingFromSpec) != 1 || report.MissingFromSpec[0] != (RouteKey{"DELETE", "/users/:id"}) {
+		t.Errorf("MissingFromSpec = %v, want DELETE /users/:id", report.MissingFromSpec)
+	}
+	if len(report.MissingFromCode) != 1 || report.MissingFromCode[0] != (RouteKey{"GET", "/users/{user_id}/avatar"}) {
+		t.Errorf("MissingFromCode = %v, want GET /users/{user_id}/avatar", report.MissingFromCode)
+	}
+}
+
+// TestValidateAgainstSpecInSync verifies matching code and spec is valid
+func TestValidateAgainstSpecInSync(t *testing.T) {
+	spec := `{"openapi": "3.0.0", "paths": {"/users": {"get": {}}, "/users/{user_id}": {"post": {}}}}`
+
+	report, err := ValidateAgainstSpec(ScanFile("routes/users.py", pythonFastAPI), []byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid {
+		t.Errorf("report = %+v, want valid", report)
+	}
+
+	if _, err := ValidateAgainstSpec(nil, []byte("not: [a spec")); err == nil {
+		t.Error("ValidateAgainstSpec() should reject invalid documents")
+	}
+}
+
+// TestValidateAgainstSwagger2 verifies Swagger 2.0 paths are compared under
+// the document's basePath
+func TestValidateAgainstSwagger2(t *testing.T) {
+	spec := `swagger: 2.0
+basePath: /api/
+paths:
+  /users:
+    get: {}
+  /users/{id}:
+    delete: {}
+`
+	eps := []Endpoint{{Method: "GET", Path: "/api/users"}, {Method: "DELETE", Path: "/api/users/:id"}}
+	report, err := ValidateAgainstSpec(eps, []byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid {
+		t.Errorf("report = %+v, want valid", report)
+	}
+}
@@ -0,0 +1,30 @@
+// Package scanner - Output style of path parameters
+package scanner
+
+// Path parameter styles. Paths keep the canonical form (canonicalPath) for
+// matching whatever the style; the style is applied on output.
+const (
+	ParamStyleBraces   = "braces"   // /users/{id}
+	ParamStyleColon    = "colon"    // /users/:id
+	ParamStyleAngle    = "angle"    // /users/<id>
+	ParamStylePreserve = "preserve" // as declared, e.g. <int:id> in Flask
+)
+
+// paramStyleFormats render a parameter name in each style
+var paramStyleFormats = map[string]string{
+	ParamStyleBraces: "{%s}",
+	ParamStyleColon:  ":%s",
+	ParamStyleAngle:  "<%s>",
+}
+
+// applyParamStyle rewrites the parameters of each endpoint's path in the
+// requested style
+func applyParamStyle(found []Endpoint, style string) {
+	format, ok := paramStyleFormats[style]
+	if !ok {
+		return
+	}
+	for i := range found {
+		found[i].Path, _ = rewriteParams(found[i].Path, format)
+	}
+}
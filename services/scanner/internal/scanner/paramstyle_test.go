@@ -0,0 +1,66 @@
+package scanner
+
+import "testing"
+
+// TestParamStyle verifies each style renders every parameter of a path,
+// whatever syntax the framework declared them in
+func TestParamStyle(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	sources := map[string]string{
+		"routes.js": `app.get('/users/:userId/posts/:postId', getPost)
+`,
+		"views.py": `@app.route('/users/<int:userId>/posts/<postId>')
+def get_post(userId, postId):
+    return {}
+`,
+		"PostController.java": `@RestController
+public class PostController {
+    @GetMapping("/users/{userId:[0-9]+}/posts/{postId}")
+    public Post get(@PathVariable Long userId, @PathVariable String postId) {
+        return posts.find(userId, postId);
+    }
+}
+`,
+	}
+	tests := []struct {
+		style string
+		want  map[string]string
+	}{
+		{ParamStyleBraces, map[string]string{
+			"routes.js": "/users/{userId}/posts/{postId}", "views.py": "/users/{userId}/posts/{postId}", "PostController.java": "/users/{userId}/posts/{postId}",
+		}},
+		{ParamStyleColon, map[string]string{
+			"routes.js": "/users/:userId/posts/:postId", "views.py": "/users/:userId/posts/:postId", "PostController.java": "/users/:userId/posts/:postId",
+		}},
+		{ParamStyleAngle, map[string]string{
+			"routes.js": "/users/<userId>/posts/<postId>", "views.py": "/users/<userId>/posts/<postId>", "PostController.java": "/users/<userId>/posts/<postId>",
+		}},
+		{ParamStylePreserve, map[string]string{
+			"routes.js": "/users/:userId/posts/:postId", "views.py": "/users/<int:userId>/posts/<postId>", "PostController.java": "/users/{userId:[0-9]+}/posts/{postId}",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			config.ParamStyle = tt.style
+			var canonical string
+			for file, source := range sources {
+				endpoints := ScanFile(file, source)
+				if len(endpoints) != 1 {
+					t.Fatalf("%s: ScanFile() found %d endpoints, want 1", file, len(endpoints))
+				}
+				if got := endpoints[0].Path; got != tt.want[file] {
+					t.Errorf("%s: Path = %q, want %q", file, got, tt.want[file])
+				}
+				// Every style keeps the same canonical form for matching
+				if canonical == "" {
+					canonical = canonicalPath(endpoints[0].Path)
+				} else if got := canonicalPath(endpoints[0].Path); got != canonical {
+					t.Errorf("%s: canonical path %q, want %q", file, got, canonical)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package scanner - Phoenix router extraction
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// get "/users/:id", UserController, :show
+	phoenixRoutePattern = regexp.MustCompile(`^\s*(get|post|put|patch|delete|options|head)\s*\(?\s*"([^"]*)"`)
+	// resources "/users", UserController, only: [:index, :show]
+	phoenixResourcesPattern = regexp.MustCompile(`^\s*resources\s*\(?\s*"([^"]*)"`)
+	// scope "/api", MyAppWeb.Api, as: :api do
+	phoenixScopePattern = regexp.MustCompile(`^\s*scope\s*\(?\s*"([^"]*)"`)
+	// only: [:index, :show] / except: [:delete]
+	phoenixActionFilterPattern = regexp.MustCompile(`\b(only|except):\s*\[([^\]]*)\]`)
+
+	// Block structure: a trailing do opens a block, as does an anonymous fn
+	elixirDoPattern     = regexp.MustCompile(`\bdo\s*$`)
+	elixirFnPattern     = regexp.MustCompile(`\bfn\b`)
+	elixirEndPattern    = regexp.MustCompile(`\bend\b`)
+	elixirStringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// phoenixActions maps resource actions to Phoenix's action names
+var phoenixActions = map[string]string{
+	"index":   "index",
+	"create":  "new",
+	"store":   "create",
+	"show":    "show",
+	"edit":    "edit",
+	"update":  "update",
+	"destroy": "delete",
+}
+
+// extractPhoenixRoutes finds Phoenix route macros, prefixing each with the
+// paths of the scope and resources blocks enclosing it
+func extractPhoenixRoutes(filePath string, lines []string) []Endpoint {
+	var found []Endpoint
+	var blocks []string // path prefix each open block contributes
+
+	prefix := func() string {
+		path := ""
+		for _, block := range blocks {
+			path = joinPathSegments(path, block)
+		}
+		return path
+	}
+	add := func(i int, method, path, action string) {
+		id := fmt.Sprintf("%s-%s-%d", scanID(filePath), method, i+1)
+		if action != "" {
+			id += "-" + action
+		}
+		found = append(found, Endpoint{
+			ID:         id,
+			Path:       path,
+			Method:     method,
+			FilePath:   filePath,
+			LineNumber: i + 1,
+			Tags:       []string{extractTag(filePath, path)},
+		})
+	}
+
+	for i, line := range lines {
+		code := strings.TrimSpace(line)
+		if strings.HasPrefix(code, "#") {
+			continue
+		}
+
+		blockPrefix := ""
+		switch {
+		case phoenixScopePattern.MatchString(line):
+			blockPrefix = phoenixScopePattern.FindStringSubmatch(line)[1]
+
+		case phoenixResourcesPattern.MatchString(line):
+			resource := phoenixResourcesPattern.FindStringSubmatch(line)[1]
+			routes, _ := expandResource("phoenix", strings.Trim(resource, "/"), false)
+			keep := phoenixActionFilter(line)
+			for _, route := range routes {
+				action := phoenixActions[route.Action]
+				if !keep(action) {
+					continue
+				}
+				path := joinPathSegments(prefix(), route.Path)
+				add(i, route.Method, path, action)
+				// Phoenix routes PUT to update as well as PATCH
+				if action == "update" {
+					add(i, "PUT", path, action)
+				}
+			}
+			// Nested resources hang off the member path: /users/:user_id/posts
+			name := strings.Trim(resource, "/")
+			blockPrefix = name + "/:" + singularize(name[strings.LastIndex(name, "/")+1:]) + "_id"
+
+		default:
+			if m := phoenixRoutePattern.FindStringSubmatch(line); m != nil {
+				add(i, strings.ToUpper(m[1]), joinPathSegments(prefix(), m[2]), "")
+			}
+		}
+
+		// Track blocks so prefixes apply until the matching end
+		code = elixirStringPattern.ReplaceAllString(code, `""`)
+		code, _, _ = strings.Cut(code, "#")
+		for range elixirFnPattern.FindAllString(code, -1) {
+			blocks = append(blocks, "")
+		}
+		if elixirDoPattern.MatchString(code) {
+			blocks = append(blocks, blockPrefix)
+		}
+		for range elixirEndPattern.FindAllString(code, -1) {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		}
+	}
+
+	return found
+}
+
+// phoenixActionFilter returns whether an action survives a resources
+// declaration's only:/except: options
+func phoenixActionFilter(line string) func(action string) bool {
+	m := phoenixActionFilterPattern.FindStringSubmatch(line)
+	if m == nil {
+		return func(string) bool { return true }
+	}
+	listed := make(map[string]bool)
+	for _, atom := range strings.Split(m[2], ",") {
+		listed[strings.TrimPrefix(strings.TrimSpace(atom), ":")] = true
+	}
+	only := m[1] == "only"
+	return func(action string) bool { return listed[action] == only }
+}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"testing"
+)
+
+// TestPhoenixRoutes verifies scope prefixes and resources expansion
+func TestPhoenixRoutes(t *testing.T) {
+	router := `defmodule MyAppWeb.Router do
+  use MyAppWeb, :router
+
+  pipeline :api do
+    plug :accepts, ["json"]
+  end
+
+  scope "/", MyAppWeb do
+    pipe_through :browser
+
+    get "/", PageController, :home
+  end
+
+  scope "/api", MyAppWeb.Api, as: :api do
+    pipe_through :api
+
+    get "/status", StatusController, :show
+    post("/events", EventController, :create)
+
+    scope "/v1" do
+      resources "/users", UserController, except: [:new, :edit] do
+        resources "/posts", PostController, only: [:index]
+      end
+      delete "/sessions/:id", SessionController, :delete # logout
+    end
+  end
+end
+`
+	want := []struct{ method, path string }{
+		{"GET", "/"},
+		{"GET", "/api/status"},
+		{"POST", "/api/events"},
+		{"GET", "/api/v1/users"},
+		{"POST", "/api/v1/users"},
+		{"GET", "/api/v1/users/{id}"},
+		{"PATCH", "/api/v1/users/{id}"},
+		{"PUT", "/api/v1/users/{id}"},
+		{"DELETE", "/api/v1/users/{id}"},
+		{"GET", "/api/v1/users/{user_id}/posts"},
+		{"DELETE", "/api/v1/sessions/{id}"},
+	}
+
+	if !hasAPIIndicators("lib/my_app_web/router.ex", router) {
+		t.Fatal("router not detected as an API file")
+	}
+	endpoints := ScanFile("lib/my_app_web/router.ex", router)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path {
+			t.Errorf("endpoint %d = %s %s, want %s %s", i, ep.Method, ep.Path, want[i].method, want[i].path)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package scanner - External post-processing of extracted endpoints
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultPostProcessTimeout bounds the post-processing command, in seconds
+const DefaultPostProcessTimeout = 30
+
+// postProcess pipes a scan's endpoints through the configured command: the
+// endpoints JSON array on stdin, the transformed array read from stdout. A
+// command that fails, times out or writes anything else fails the scan
+// rather than storing the results it was meant to transform.
+func postProcess(eps []Endpoint) ([]Endpoint, error) {
+	if config.PostProcessCommand == "" {
+		return eps, nil
+	}
+	if eps == nil {
+		eps = []Endpoint{}
+	}
+	input, err := json.Marshal(eps)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(config.PostProcessTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = DefaultPostProcessTimeout * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, config.PostProcessCommand)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on children still holding the pipes once the command is killed
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("post-process command timed out after %v: %w", timeout, ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("post-process command failed: %v: %s", err, msg)
+		}
+		return nil, fmt.Errorf("post-process command failed: %v", err)
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	if !bytes.HasPrefix(output, []byte("[")) {
+		return nil, errors.New("post-process command must write a JSON array of endpoints")
+	}
+	var out []Endpoint
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("post-process command wrote invalid endpoints JSON: %w", err)
+	}
+	return out, nil
+}
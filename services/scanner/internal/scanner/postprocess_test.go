@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript writes an executable shell script for the post-process hook
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestPostProcessHook verifies a scan's endpoints are stored as the
+// configured command transforms them
+func TestPostProcessHook(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	// Redact the internal prefix from every path
+	config.PostProcessCommand = writeScript(t, `sed 's#"/internal/#"/#g'`)
+
+	rootDir := t.TempDir()
+	source := `@app.get("/internal/users")
+def list_users():
+    return []
+`
+	if err := os.WriteFile(filepath.Join(rootDir, "main.py"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scanID := "postprocess-test"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+	endpoints[scanID] = []Endpoint{}
+	mu.Unlock()
+
+	scanCheckout(scanID, rootDir)
+
+	status, _ := GetStatus(scanID)
+	if status.Status != "completed" {
+		t.Fatalf("status = %s (%s), want completed", status.Status, status.Error)
+	}
+	eps, _ := GetEndpoints(scanID)
+	if len(eps) != 1 || eps[0].Path != "/users" {
+		t.Errorf("stored endpoints = %+v, want /users with the prefix redacted", eps)
+	}
+}
+
+// TestPostProcessFailures verifies a failing, slow or garbled command is
+// reported instead of its output being used
+func TestPostProcessFailures(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.PostProcessTimeout = 1
+
+	tests := []struct {
+		name, script, want string
+	}{
+		{"exit status", "echo 'no such field' >&2; exit 3", "no such field"},
+		{"invalid JSON", "echo '[{'", "invalid endpoints JSON"},
+		{"not an array", "echo '{}'", "JSON array"},
+		{"timeout", "sleep 5", "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.PostProcessCommand = writeScript(t, tt.script)
+			_, err := postProcess([]Endpoint{{Path: "/users", Method: "GET"}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("postProcess() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+
+	// Disabled, endpoints pass through untouched
+	config.PostProcessCommand = ""
+	if eps, err := postProcess([]Endpoint{{Path: "/users"}}); err != nil || len(eps) != 1 {
+		t.Errorf("postProcess() = %v, %v with no command", eps, err)
+	}
+}
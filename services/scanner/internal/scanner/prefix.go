@@ -0,0 +1,43 @@
+// Package scanner - Stripping a gateway prefix from reported paths
+package scanner
+
+import (
+	"strings"
+)
+
+// stripPathPrefix removes prefix from path when it is the path's whole
+// leading segment(s): /internal strips /internal/users but not /internals
+func stripPathPrefix(path, prefix string) (string, bool) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return path, false
+	}
+	p := "/" + strings.TrimPrefix(path, "/")
+	switch {
+	case p == prefix || p == prefix+"/":
+		return "/", true
+	case strings.HasPrefix(p, prefix+"/"):
+		return p[len(prefix):], true
+	}
+	return path, false
+}
+
+// applyStripPrefix strips the configured prefix from each endpoint's path,
+// keeping the path as declared in RawPath
+func applyStripPrefix(found []Endpoint, prefix string) {
+	if prefix == "" {
+		return
+	}
+	for i := range found {
+		ep := &found[i]
+		path, ok := stripPathPrefix(ep.Path, prefix)
+		if !ok {
+			continue
+		}
+		if ep.RawPath == "" {
+			ep.RawPath = ep.Path
+		}
+		ep.Path = path
+		ep.Tags = []string{extractTag(ep.FilePath, path)}
+	}
+}
@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"testing"
+)
+
+// TestStripPathPrefix verifies only a whole leading segment is stripped
+func TestStripPathPrefix(t *testing.T) {
+	tests := []struct {
+		path, prefix string
+		want         string
+		stripped     bool
+	}{
+		{"/internal/users", "/internal", "/users", true},
+		{"/internal/users/:id", "internal/", "/users/:id", true},
+		{"/internal", "/internal", "/", true},
+		{"internal/orders", "/internal", "/orders", true},
+		{"/internals/users", "/internal", "/internals/users", false},
+		{"/api/internal/users", "/internal", "/api/internal/users", false},
+		{"/internal/v1/users", "/internal/v1", "/users", true},
+		{"/users", "/", "/users", false},
+	}
+	for _, tt := range tests {
+		got, stripped := stripPathPrefix(tt.path, tt.prefix)
+		if got != tt.want || stripped != tt.stripped {
+			t.Errorf("stripPathPrefix(%q, %q) = %q, %v; want %q, %v", tt.path, tt.prefix, got, stripped, tt.want, tt.stripped)
+		}
+	}
+}
+
+// TestStripPrefixKeepsRawPath verifies scanned endpoints report the stripped path
+func TestStripPrefixKeepsRawPath(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.StripPrefix = "/internal"
+
+	code := `app.get('/internal/users/:id', getUser)
+app.get('/internals/status', status)
+`
+	endpoints := ScanFile("routes.js", code)
+	if len(endpoints) != 2 {
+		t.Fatalf("ScanFile() found %d endpoints, want 2", len(endpoints))
+	}
+	if ep := endpoints[0]; ep.Path != "/users/{id}" || ep.RawPath != "/internal/users/:id" {
+		t.Errorf("got path %q raw %q, want /users/{id} raw /internal/users/:id", ep.Path, ep.RawPath)
+	}
+	if ep := endpoints[1]; ep.Path != "/internals/status" || ep.RawPath != "" {
+		t.Errorf("got path %q raw %q, want /internals/status unchanged", ep.Path, ep.RawPath)
+	}
+}
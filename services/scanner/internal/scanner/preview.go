@@ -0,0 +1,142 @@
+// Package scanner - Dry-run repository preview (discovery and Stage 1 only)
+package scanner
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// RepoPreview estimates a scan's size without extracting endpoints
+type RepoPreview struct {
+	Commit         string            `json:"commit,omitempty"`
+	CodeFiles      int               `json:"code_files"`      // files in supported languages
+	CandidateFiles int               `json:"candidate_files"` // files passing Stage 1, which a scan would extract
+	Languages      []LanguagePreview `json:"languages"`       // most code files first
+	Frameworks     []FrameworkCount  `json:"frameworks"`      // among candidate files, most files first
+	DurationMs     int64             `json:"duration_ms"`
+}
+
+// LanguagePreview is one language's share of a repository's files
+type LanguagePreview struct {
+	Language       string `json:"language"`
+	CodeFiles      int    `json:"code_files"`
+	CandidateFiles int    `json:"candidate_files"`
+}
+
+// FrameworkCount is how many candidate files use a framework
+type FrameworkCount struct {
+	Framework string `json:"framework"`
+	Files     int    `json:"files"`
+}
+
+// frameworkMarkers identify a candidate file's framework from its imports
+var frameworkMarkers = []struct {
+	name   string
+	marker *regexp.Regexp
+}{
+	{"FastAPI", regexp.MustCompile(`\bfrom\s+fastapi\b|\bimport\s+fastapi\b`)},
+	{"Flask", regexp.MustCompile(`\bfrom\s+flask\b|\bimport\s+flask\b`)},
+	{"Django", regexp.MustCompile(`\bdjango\.(?:urls|conf\.urls)\b|\brest_framework\b`)},
+	{"Express", regexp.MustCompile(`require\(\s*["']express["']\s*\)|from\s+["']express["']`)},
+	{"Fastify", regexp.MustCompile(`require\(\s*["']fastify["']\s*\)|from\s+["']fastify["']`)},
+	{"NestJS", regexp.MustCompile(`from\s+["']@nestjs/`)},
+	{"tRPC", regexp.MustCompile(`["']@trpc/server["']`)},
+	{"Gin", regexp.MustCompile(`"github\.com/gin-gonic/gin"`)},
+	{"Echo", regexp.MustCompile(`"github\.com/labstack/echo`)},
+	{"Fiber", regexp.MustCompile(`"github\.com/gofiber/fiber`)},
+	{"gorilla/mux", regexp.MustCompile(`"github\.com/gorilla/mux"`)},
+	{"chi", regexp.MustCompile(`"github\.com/go-chi/chi`)},
+	{"net/http", regexp.MustCompile(`"net/http"`)},
+	{"Spring", regexp.MustCompile(`\borg\.springframework\.`)},
+	{"ASP.NET Core", regexp.MustCompile(`\bMicrosoft\.AspNetCore\.`)},
+	{"Laravel", regexp.MustCompile(`\bIlluminate\\|\bRoute::`)},
+	{"Phoenix", regexp.MustCompile(`\bPhoenix\.Router\b|\buse\s+\w+Web,\s*:router\b`)},
+	{"gRPC-Gateway", regexp.MustCompile(`google\.api\.http`)},
+}
+
+// PreviewRepository clones a repository and runs discovery and Stage 1
+// only, reporting how many files a scan would read and extract. Nothing is
+// recorded and the clone is removed afterwards.
+func PreviewRepository(url, branch, token string) (*RepoPreview, error) {
+	started := time.Now()
+	tmpDir, err := cloneOutsideQueue(url, branch, token)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	preview, err := previewCheckout(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	preview.Commit = headCommit(tmpDir)
+	preview.DurationMs = time.Since(started).Milliseconds()
+	return preview, nil
+}
+
+// previewCheckout counts a checkout's code and candidate files by language
+// and framework
+func previewCheckout(rootDir string) (*RepoPreview, error) {
+	allFiles, err := getCodeFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	apiFiles, err := getLikelyAPIFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &RepoPreview{CodeFiles: len(allFiles), CandidateFiles: len(apiFiles)}
+	languages := make(map[string]*LanguagePreview)
+	language := func(path string) *LanguagePreview {
+		name := languageOf(path)
+		if languages[name] == nil {
+			languages[name] = &LanguagePreview{Language: name}
+		}
+		return languages[name]
+	}
+	for _, path := range allFiles {
+		language(path).CodeFiles++
+	}
+
+	frameworks := make(map[string]int)
+	for _, path := range apiFiles {
+		language(path).CandidateFiles++
+		content, err := readFile(path)
+		if err != nil {
+			continue
+		}
+		for _, fw := range frameworkMarkers {
+			if fw.marker.Match(content) {
+				frameworks[fw.name]++
+			}
+		}
+	}
+
+	preview.Languages = []LanguagePreview{}
+	for _, lang := range languages {
+		preview.Languages = append(preview.Languages, *lang)
+	}
+	sort.Slice(preview.Languages, func(i, j int) bool {
+		a, b := preview.Languages[i], preview.Languages[j]
+		if a.CodeFiles != b.CodeFiles {
+			return a.CodeFiles > b.CodeFiles
+		}
+		return a.Language < b.Language
+	})
+
+	preview.Frameworks = []FrameworkCount{}
+	for name, files := range frameworks {
+		preview.Frameworks = append(preview.Frameworks, FrameworkCount{Framework: name, Files: files})
+	}
+	sort.Slice(preview.Frameworks, func(i, j int) bool {
+		a, b := preview.Frameworks[i], preview.Frameworks[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Framework < b.Framework
+	})
+	return preview, nil
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"os"
+	"testing"
+)
+
+// TestPreviewRepository verifies a preview counts files without extracting or recording anything
+func TestPreviewRepository(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.TempDir = t.TempDir()
+
+	repoDir, _ := newFixtureRepo(t, map[string]string{
+		"routes/users.py":  pythonFastAPI,
+		"models/user.py":   pythonModel,
+		"server.js":        "const express = require('express')\nconst app = express()\napp.get('/health', (req, res) => res.send('ok'))\n",
+		"README.md":        "# fixture\n",
+		"web/src/index.ts": "export const answer = 42\n",
+	})
+
+	mu.RLock()
+	scansBefore := len(scans)
+	mu.RUnlock()
+
+	preview, err := PreviewRepository(repoDir, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if preview.CodeFiles != 4 || preview.CandidateFiles != 2 {
+		t.Errorf("code/candidate files = %d/%d, want 4/2", preview.CodeFiles, preview.CandidateFiles)
+	}
+	langs := make(map[string]LanguagePreview)
+	for _, l := range preview.Languages {
+		langs[l.Language] = l
+	}
+	if py := langs[languageOf("x.py")]; py.CodeFiles != 2 || py.CandidateFiles != 1 {
+		t.Errorf("Python breakdown = %+v, want 2 code / 1 candidate", py)
+	}
+	frameworks := make(map[string]int)
+	for _, f := range preview.Frameworks {
+		frameworks[f.Framework] = f.Files
+	}
+	if frameworks["FastAPI"] != 1 || frameworks["Express"] != 1 {
+		t.Errorf("frameworks = %v, want FastAPI and Express", preview.Frameworks)
+	}
+	if preview.Commit == "" {
+		t.Error("preview has no commit")
+	}
+
+	mu.RLock()
+	scansAfter := len(scans)
+	mu.RUnlock()
+	if scansAfter != scansBefore {
+		t.Error("preview recorded a scan")
+	}
+	if left, _ := os.ReadDir(config.TempDir); len(left) != 0 {
+		t.Errorf("preview left %d entries in the temp dir", len(left))
+	}
+}
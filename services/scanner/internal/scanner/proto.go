@@ -0,0 +1,72 @@
+// Package scanner - gRPC-Gateway HTTP annotations in .proto files
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Proto patterns
+var (
+	protoServicePattern = regexp.MustCompile(`^\s*service\s+(\w+)\s*\{`)
+	protoRPCPattern     = regexp.MustCompile(`^\s*rpc\s+(\w+)\s*\(`)
+	// get: "/v1/users/{id}" (also inside additional_bindings { ... })
+	protoBindingPattern = regexp.MustCompile(`\b(get|put|post|delete|patch)\s*:\s*"([^"]+)"`)
+	// option (google.api.http).get = "/v1/users";
+	protoShorthandPattern = regexp.MustCompile(`\(google\.api\.http\)\.(get|put|post|delete|patch)\s*=\s*"([^"]+)"`)
+	// custom: { kind: "HEAD" path: "/v1/users" }
+	protoCustomPattern = regexp.MustCompile(`\bcustom\s*:?\s*\{\s*kind\s*:\s*"(\w+)"\s*,?\s*path\s*:\s*"([^"]+)"`)
+)
+
+// extractProtoRoutes emits the REST endpoints mapped onto RPCs by
+// google.api.http options, including additional_bindings
+func extractProtoRoutes(filePath string, lines []string) []Endpoint {
+	var found []Endpoint
+	service := ""
+	rpc := ""
+	depth := 0
+	rpcDepth := -1
+
+	for i, line := range lines {
+		code := line
+		if idx := strings.Index(code, "//"); idx >= 0 {
+			code = code[:idx]
+		}
+
+		if m := protoServicePattern.FindStringSubmatch(code); m != nil {
+			service = m[1]
+		}
+		if m := protoRPCPattern.FindStringSubmatch(code); m != nil {
+			rpc = m[1]
+			rpcDepth = depth
+		}
+
+		if rpc != "" {
+			bindings := protoBindingPattern.FindAllStringSubmatch(code, -1)
+			bindings = append(bindings, protoShorthandPattern.FindAllStringSubmatch(code, -1)...)
+			bindings = append(bindings, protoCustomPattern.FindAllStringSubmatch(code, -1)...)
+			for _, b := range bindings {
+				method := strings.ToUpper(b[1])
+				found = append(found, Endpoint{
+					ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, i+1),
+					Path:       b[2],
+					Method:     method,
+					FilePath:   filePath,
+					LineNumber: i + 1,
+					Tags:       []string{extractTag(filePath, b[2])},
+					RPC:        service + "." + rpc,
+				})
+			}
+		}
+
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		// The RPC ends when its braces close, or at ';' for option-less RPCs
+		if rpc != "" && depth <= rpcDepth && (strings.Contains(code, "}") || strings.Contains(code, ";")) {
+			rpc = ""
+			rpcDepth = -1
+		}
+	}
+
+	return found
+}
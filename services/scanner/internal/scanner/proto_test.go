@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const protoGateway = `syntax = "proto3";
+
+package users.v1;
+
+import "google/api/annotations.proto";
+
+service UserService {
+  rpc GetUser(GetUserRequest) returns (User) {
+    option (google.api.http) = {
+      get: "/v1/users/{id}"
+      additional_bindings {
+        get: "/v1/orgs/{org_id}/users/{id}"
+      }
+    };
+  }
+
+  rpc CreateUser(CreateUserRequest) returns (User) {
+    option (google.api.http) = {
+      post: "/v1/users"
+      body: "*"
+    };
+  }
+
+  // Internal only, no HTTP mapping
+  rpc SyncUsers(SyncRequest) returns (SyncResponse);
+
+  rpc ListUsers(ListUsersRequest) returns (ListUsersResponse) {
+    option (google.api.http).get = "/v1/users";
+  }
+}
+`
+
+// TestProtoGatewayRoutes verifies google.api.http bindings become REST endpoints
+func TestProtoGatewayRoutes(t *testing.T) {
+	if !hasAPIIndicators("api/users.proto", protoGateway) {
+		t.Fatal("proto file should have API indicators")
+	}
+
+	endpoints := ScanFile("api/users.proto", protoGateway)
+	want := []struct{ method, path, rpc string }{
+		{"GET", "/v1/users/{id}", "UserService.GetUser"},
+		{"GET", "/v1/orgs/{org_id}/users/{id}", "UserService.GetUser"},
+		{"POST", "/v1/users", "UserService.CreateUser"},
+		{"GET", "/v1/users", "UserService.ListUsers"},
+	}
+
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.RPC != w.rpc {
+			t.Errorf("endpoint %d = %s %s (%s), want %s %s (%s)", i, ep.Method, ep.Path, ep.RPC, w.method, w.path, w.rpc)
+		}
+	}
+}
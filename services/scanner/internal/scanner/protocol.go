@@ -0,0 +1,112 @@
+// Package scanner - Clone protocol preference (HTTPS or SSH)
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Clone protocols, selected with Config.CloneProtocol. Empty clones URLs as given.
+const (
+	CloneProtocolHTTPS = "https"
+	CloneProtocolSSH   = "ssh"
+)
+
+// rewriteCloneURL converts a remote URL to the preferred protocol:
+// git@github.com:org/repo.git <-> https://github.com/org/repo.git.
+// URLs it can't convert (local paths, other schemes) are returned unchanged.
+func rewriteCloneURL(rawURL, protocol string) string {
+	host, path, ok := remoteHostPath(rawURL)
+	if !ok {
+		return rawURL
+	}
+	switch protocol {
+	case CloneProtocolHTTPS:
+		if isSSHURL(rawURL) {
+			return "https://" + host + "/" + path
+		}
+	case CloneProtocolSSH:
+		if !isSSHURL(rawURL) {
+			return gitssh.DefaultUsername + "@" + host + ":" + path
+		}
+	}
+	return rawURL
+}
+
+// isSSHURL reports whether a remote is reached over SSH: ssh:// or scp-style user@host:path
+func isSSHURL(rawURL string) bool {
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "" {
+		return u.Scheme == "ssh" || u.Scheme == "git+ssh"
+	}
+	at := strings.Index(rawURL, "@")
+	colon := strings.Index(rawURL, ":")
+	return at > 0 && colon > at
+}
+
+// remoteHostPath splits an http(s) or SSH remote into its host and
+// repository path. Ports are dropped: they don't carry over between protocols.
+func remoteHostPath(rawURL string) (string, string, bool) {
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "" {
+		switch u.Scheme {
+		case "http", "https", "ssh", "git+ssh":
+			path := strings.TrimPrefix(u.Path, "/")
+			return u.Hostname(), path, u.Hostname() != "" && path != ""
+		}
+		return "", "", false
+	}
+	if !isSSHURL(rawURL) {
+		return "", "", false
+	}
+	_, rest, _ := strings.Cut(rawURL, "@")
+	host, path, _ := strings.Cut(rest, ":")
+	path = strings.TrimPrefix(path, "/")
+	return host, path, host != "" && path != ""
+}
+
+// cloneAuth picks the credentials for a remote: the token as HTTP basic auth
+// for http(s), or the configured key (else the SSH agent) for SSH remotes.
+// SSH servers are verified against SSH_KNOWN_HOSTS; without it SSH remotes
+// are refused rather than trusting any host key.
+func cloneAuth(rawURL, token string) (transport.AuthMethod, error) {
+	if !isSSHURL(rawURL) {
+		if token == "" {
+			return nil, nil
+		}
+		return &http.BasicAuth{Username: "x-access-token", Password: token}, nil
+	}
+	if config.SSHKnownHosts == "" {
+		return nil, errors.New("SSH_KNOWN_HOSTS must be set to clone over SSH")
+	}
+	hostKeys, err := knownhosts.New(config.SSHKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("loading SSH known hosts: %w", err)
+	}
+
+	user := gitssh.DefaultUsername
+	if u, err := url.Parse(rawURL); err == nil && u.User != nil {
+		user = u.User.Username()
+	} else if name, _, ok := strings.Cut(rawURL, "@"); ok && !strings.Contains(name, "/") {
+		user = name
+	}
+	if config.SSHKeyPath != "" {
+		auth, err := gitssh.NewPublicKeysFromFile(user, config.SSHKeyPath, config.SSHKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("loading SSH key: %w", err)
+		}
+		auth.HostKeyCallback = hostKeys
+		return auth, nil
+	}
+	auth, err := gitssh.NewSSHAgentAuth(user)
+	if err != nil {
+		return nil, fmt.Errorf("no SSH_KEY_PATH set and no SSH agent available: %w", err)
+	}
+	auth.HostKeyCallback = hostKeys
+	return auth, nil
+}
@@ -0,0 +1,58 @@
+// Package scanner - On-demand removal of a scan's data
+package scanner
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+// ErrScanInProgress is returned when purging a scan that hasn't finished
+var ErrScanInProgress = errors.New("scan is still queued or running")
+
+// PurgeScan deletes everything held for a scan: its status (including
+// resource accounting), its endpoints, any cached checkout and its saved
+// state. It leaves any batch it belonged to, which drops the clone token
+// held for it, and a batch left empty is deleted. Queued or running scans
+// are refused, since their worker would recreate the data.
+func PurgeScan(scanID string) error {
+	mu.Lock()
+	status, exists := scans[scanID]
+	if !exists {
+		mu.Unlock()
+		return ErrScanNotFound
+	}
+	if status.Status == "queued" || status.Status == "scanning" {
+		mu.Unlock()
+		return ErrScanInProgress
+	}
+	delete(scans, scanID)
+	delete(endpoints, scanID)
+	forgetBatchScan(scanID)
+	mu.Unlock()
+
+	dropCachedClone(scanID)
+	removeScanState(scanID)
+
+	log.Printf("🧹 Purged data for scan %s", scanID)
+	return nil
+}
+
+// dropCachedClone removes a scan's cached checkout from disk and the cache
+func dropCachedClone(scanID string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	clone, exists := cloneCache[scanID]
+	if !exists {
+		return
+	}
+	os.RemoveAll(clone.Dir)
+	delete(cloneCache, scanID)
+	for i, id := range cacheOrder {
+		if id == scanID {
+			cacheOrder = append(cacheOrder[:i], cacheOrder[i+1:]...)
+			break
+		}
+	}
+}
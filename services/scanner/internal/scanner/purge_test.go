@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestPurgeScan verifies status, endpoints, resources and the cached checkout are removed
+func TestPurgeScan(t *testing.T) {
+	rootDir := filepath.Join(t.TempDir(), "checkout")
+	if err := os.MkdirAll(rootDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "users.py"), []byte(pythonFastAPI), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	scanID := "purge-test"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+	endpoints[scanID] = []Endpoint{}
+	mu.Unlock()
+
+	if err := PurgeScan(scanID); !errors.Is(err, ErrScanInProgress) {
+		t.Fatalf("PurgeScan() while scanning error = %v, want ErrScanInProgress", err)
+	}
+
+	recordCloneSize(scanID, dirSize(rootDir))
+	scanCheckout(scanID, rootDir)
+	cacheClone(scanID, rootDir, "abc123")
+	if eps, _ := GetEndpoints(scanID); len(eps) == 0 {
+		t.Fatal("scan found no endpoints before purge")
+	}
+
+	if err := PurgeScan(scanID); err != nil {
+		t.Fatalf("PurgeScan() error = %v", err)
+	}
+
+	if _, err := GetStatus(scanID); !errors.Is(err, ErrScanNotFound) {
+		t.Errorf("GetStatus() after purge error = %v, want ErrScanNotFound", err)
+	}
+	if _, err := GetEndpoints(scanID); !errors.Is(err, ErrScanNotFound) {
+		t.Errorf("GetEndpoints() after purge error = %v, want ErrScanNotFound", err)
+	}
+	if _, cached := cachedCloneFor(scanID); cached {
+		t.Error("cached checkout still registered after purge")
+	}
+	if _, err := os.Stat(rootDir); !os.IsNotExist(err) {
+		t.Errorf("cached checkout still on disk: %v", err)
+	}
+	cacheMu.Lock()
+	for _, id := range cacheOrder {
+		if id == scanID {
+			t.Error("scan still in cache eviction order")
+		}
+	}
+	cacheMu.Unlock()
+
+	if err := PurgeScan(scanID); !errors.Is(err, ErrScanNotFound) {
+		t.Errorf("second PurgeScan() error = %v, want ErrScanNotFound", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Package scanner - Prioritised scan queue and worker pool
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// DefaultMaxConcurrentScans is the default worker pool size
+const DefaultMaxConcurrentScans = 4
+
+// DefaultMaxClonesPerHost is the default number of concurrent scans per git host
+const DefaultMaxClonesPerHost = 2
+
+// Priority controls the order in which pending scans are picked up
+type Priority string
+
+// Scan priorities, highest first
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
+
+// priorityOrder is the order in which workers drain the pending lists
+var priorityOrder = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// ParsePriority validates a priority string, defaulting to normal when empty
+func ParsePriority(s string) (Priority, error) {
+	p := Priority(strings.ToLower(strings.TrimSpace(s)))
+	switch p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (expected high, normal or low)", s)
+}
+
+// ScanJob describes a repository scan waiting for a worker
+type ScanJob struct {
+	ScanID   string
+	URL      string
+	Branch   string
+	Commit   string // optional SHA to scan instead of the branch head
+	Token    string
+	Priority Priority
+	Rescan   bool   // re-extract the scan's cached checkout instead of cloning
+	Checkout string // resume an interrupted scan from this checkout instead of cloning
+
+	MinConfidence float64  // drop endpoints scoring lower; 0 keeps everything
+	AllBranches   bool     // scan every branch head instead of Branch
+	DropPaths     []string // path globs dropped from the results; nil uses DROP_PATHS
+
+	CallbackURL      string             // optional; receives status callbacks
+	CallbackTemplate *template.Template // optional; see ParseCallbackTemplate
+}
+
+// ScanQueue runs scan jobs on a fixed pool of workers, always picking the
+// oldest job of the highest pending priority (FIFO within a priority).
+// Jobs whose git host is at its concurrency cap are passed over and stay
+// pending until one of that host's scans finishes.
+type ScanQueue struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pending   map[Priority][]ScanJob
+	run       func(ScanJob)
+	active    map[string]int        // running jobs by host
+	hostLimit func(host string) int // cap per host; 0 means unlimited
+}
+
+// NewScanQueue creates a queue and starts its workers
+func NewScanQueue(workers int, run func(ScanJob)) *ScanQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	q := &ScanQueue{
+		pending: make(map[Priority][]ScanJob),
+		run:     run,
+		active:  make(map[string]int),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// Push adds a job to the back of its priority's pending list
+func (q *ScanQueue) Push(job ScanJob) {
+	if job.Priority == "" {
+		job.Priority = PriorityNormal
+	}
+
+	q.mu.Lock()
+	q.pending[job.Priority] = append(q.pending[job.Priority], job)
+	q.mu.Unlock()
+	// Clones outside the queue wait on the same condition; wake every waiter
+	// so a worker is sure to see the job
+	q.cond.Broadcast()
+}
+
+// SetHostLimit caps how many jobs may run at once against each git host
+func (q *ScanQueue) SetHostLimit(limit func(host string) int) {
+	q.mu.Lock()
+	q.hostLimit = limit
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// Len returns the number of jobs waiting for a worker
+func (q *ScanQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	total := 0
+	for _, jobs := range q.pending {
+		total += len(jobs)
+	}
+	return total
+}
+
+// pop blocks until a job is available and returns the next one to run
+func (q *ScanQueue) pop() ScanJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		for _, p := range priorityOrder {
+			for i, job := range q.pending[p] {
+				host := hostOf(job.URL)
+				if q.hostFull(host) {
+					continue
+				}
+				q.pending[p] = append(q.pending[p][:i:i], q.pending[p][i+1:]...)
+				q.active[host]++
+				return job
+			}
+		}
+		q.cond.Wait()
+	}
+}
+
+// hostFull reports whether host is at its cap. Callers must hold q.mu.
+func (q *ScanQueue) hostFull(host string) bool {
+	if q.hostLimit == nil || host == "" {
+		return false
+	}
+	limit := q.hostLimit(host)
+	return limit > 0 && q.active[host] >= limit
+}
+
+// done releases a finished job's host slot
+func (q *ScanQueue) done(job ScanJob) {
+	q.releaseHost(hostOf(job.URL))
+}
+
+// acquireHost blocks until host is under its cap, then takes one of its
+// slots, for work against the host that doesn't run as a queued job
+func (q *ScanQueue) acquireHost(host string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.hostFull(host) {
+		q.cond.Wait()
+	}
+	q.active[host]++
+}
+
+// releaseHost gives back a slot of host taken by pop or acquireHost
+func (q *ScanQueue) releaseHost(host string) {
+	q.mu.Lock()
+	if q.active[host]--; q.active[host] <= 0 {
+		delete(q.active, host)
+	}
+	q.mu.Unlock()
+
+	// Any waiting worker may now be able to take a job for this host
+	q.cond.Broadcast()
+}
+
+// work is the worker loop
+func (q *ScanQueue) work() {
+	for {
+		job := q.pop()
+		q.run(job)
+		q.done(job)
+	}
+}
+
+// hostOf returns the lowercase host of a clone URL, including scp-style
+// git@host:org/repo addresses. Local paths have no host.
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname())
+	}
+	if _, rest, ok := strings.Cut(rawURL, "@"); ok {
+		if host, _, ok := strings.Cut(rest, ":"); ok {
+			return strings.ToLower(host)
+		}
+	}
+	return ""
+}
+
+// Default queue used by the HTTP handlers
+var scanQueue *ScanQueue
+
+// StartWorkers creates the default scan queue with the given worker count
+func StartWorkers(workers int) {
+	scanQueue = NewScanQueue(workers, runJob)
+	scanQueue.SetHostLimit(config.cloneLimit)
+	log.Printf("⚙️  Scan queue started with %d worker(s)", workers)
+}
+
+// cloneOutsideQueue clones a repository for work that doesn't run as a
+// queued job (exports, previews), waiting for a slot of its host so the
+// per-host cap holds for every clone
+func cloneOutsideQueue(url, branch, token string) (string, error) {
+	if q := scanQueue; q != nil {
+		host := hostOf(url)
+		q.acquireHost(host)
+		defer q.releaseHost(host)
+	}
+	return cloneRepository(url, branch, token)
+}
+
+// runJob runs a queued scan, rescan or resumed scan
+func runJob(job ScanJob) {
+	switch {
+	case job.Rescan:
+		runRescan(job.ScanID)
+	case job.Checkout != "":
+		resumeScan(job.ScanID, job.Checkout)
+	default:
+		StartScan(job)
+	}
+}
+
+// Enqueue registers a scan as queued and hands it to the worker pool
+func Enqueue(job ScanJob) {
+	mu.Lock()
+	scans[job.ScanID] = &ScanStatus{
+		ID:        job.ScanID,
+		Status:    "queued",
+		URL:       redact(job.URL),
+		Branch:    job.Branch,
+		Priority:  string(job.Priority),
+		Commit:    job.Commit,
+		StartedAt: time.Now(),
+
+		MinConfidence:    job.MinConfidence,
+		AllBranches:      job.AllBranches,
+		DropPaths:        job.DropPaths,
+		CallbackURL:      job.CallbackURL,
+		CallbackTemplate: job.CallbackTemplate,
+	}
+	endpoints[job.ScanID] = []Endpoint{}
+	mu.Unlock()
+	saveScanState(job.ScanID, "")
+
+	scanQueue.Push(job)
+}
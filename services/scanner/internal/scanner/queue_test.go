@@ -0,0 +1,186 @@
+package scanner
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestScanQueuePriority verifies a high priority job overtakes an earlier low priority one
+func TestScanQueuePriority(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	done := make(chan struct{}, 4)
+
+	q := NewScanQueue(1, func(job ScanJob) {
+		if job.ScanID == "blocker" {
+			<-release
+		}
+		mu.Lock()
+		order = append(order, job.ScanID)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	// Occupy the only worker, then queue work behind it
+	q.Push(ScanJob{ScanID: "blocker"})
+	for q.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	q.Push(ScanJob{ScanID: "low-1", Priority: PriorityLow})
+	q.Push(ScanJob{ScanID: "low-2", Priority: PriorityLow})
+	q.Push(ScanJob{ScanID: "high", Priority: PriorityHigh})
+	close(release)
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for queued jobs")
+		}
+	}
+
+	want := []string{"blocker", "high", "low-1", "low-2"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("run order = %v, want %v", order, want)
+		}
+	}
+}
+
+// TestParsePriority verifies priority validation and the default
+func TestParsePriority(t *testing.T) {
+	if p, err := ParsePriority(""); err != nil || p != PriorityNormal {
+		t.Errorf("ParsePriority(\"\") = %v, %v; want normal", p, err)
+	}
+	if p, err := ParsePriority("HIGH"); err != nil || p != PriorityHigh {
+		t.Errorf("ParsePriority(\"HIGH\") = %v, %v; want high", p, err)
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Error("ParsePriority(\"urgent\") should fail")
+	}
+}
+
+// TestScanQueueHostLimit verifies scans beyond a host's cap stay queued
+// without holding up scans against other hosts
+func TestScanQueueHostLimit(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan ScanJob, 8)
+	var mu sync.Mutex
+	running := make(map[string]int)
+	peak := make(map[string]int)
+
+	q := NewScanQueue(4, func(job ScanJob) {
+		host := hostOf(job.URL)
+		mu.Lock()
+		running[host]++
+		peak[host] = max(peak[host], running[host])
+		mu.Unlock()
+
+		started <- job
+		<-release
+
+		mu.Lock()
+		running[host]--
+		mu.Unlock()
+	})
+	cfg := DefaultConfig()
+	cfg.MaxClonesPerHost = 2
+	cfg.HostCloneLimits = parseIntPairs("gitlab.example.com=1")
+	q.SetHostLimit(cfg.cloneLimit)
+
+	for i := 0; i < 5; i++ {
+		q.Push(ScanJob{ScanID: "gh-" + string(rune('a'+i)), URL: "https://github.com/org/repo" + string(rune('a'+i))})
+	}
+	q.Push(ScanJob{ScanID: "gl-1", URL: "git@gitlab.example.com:org/one.git"})
+	q.Push(ScanJob{ScanID: "gl-2", URL: "https://GitLab.example.com/org/two"})
+
+	// Two github.com scans and one gitlab scan start; the rest wait
+	for i := 0; i < 3; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for scans under the host caps to start")
+		}
+	}
+	select {
+	case job := <-started:
+		t.Fatalf("scan %s started beyond its host cap", job.ScanID)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := q.Len(); n != 4 {
+		t.Errorf("queue length = %d, want 4 scans waiting", n)
+	}
+
+	close(release)
+	for i := 0; i < 4; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for queued scans")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if peak["github.com"] != 2 || peak["gitlab.example.com"] != 1 {
+		t.Errorf("peak concurrency = %v, want github.com=2 gitlab.example.com=1", peak)
+	}
+}
+
+// TestCloneOutsideQueueHostLimit verifies exports and previews wait for a
+// slot of their host like queued scans do
+func TestCloneOutsideQueueHostLimit(t *testing.T) {
+	prev, prevQueue := config, scanQueue
+	t.Cleanup(func() { config, scanQueue = prev, prevQueue })
+	// Without known hosts, SSH clones fail right after taking their slot
+	config.SSHKnownHosts = ""
+	config.MaxClonesPerHost = 1
+	scanQueue = NewScanQueue(1, func(ScanJob) {})
+	scanQueue.SetHostLimit(config.cloneLimit)
+
+	const url = "git@git.example.com:org/repo.git"
+	scanQueue.acquireHost(hostOf(url))
+
+	finished := make(chan error, 2)
+	go func() { finished <- StreamScan(url, "", "", 0, nil, make(chan Endpoint, 1)) }()
+	go func() {
+		_, err := PreviewRepository(url, "", "")
+		finished <- err
+	}()
+
+	select {
+	case err := <-finished:
+		t.Fatalf("clone ran while its host was at its cap: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	scanQueue.releaseHost(hostOf(url))
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-finished:
+			if !errors.Is(err, ErrCloneAuth) {
+				t.Errorf("clone error = %v, want ErrCloneAuth", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for clones once the host slot was free")
+		}
+	}
+}
+
+// TestHostOf verifies hosts are taken from URL and scp-style addresses
+func TestHostOf(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/repo":         "github.com",
+		"https://token@GitHub.com:443/org/r":  "github.com",
+		"git@gitlab.example.com:org/repo.git": "gitlab.example.com",
+		"/tmp/fixture-repo":                   "",
+	}
+	for in, want := range tests {
+		if got := hostOf(in); got != want {
+			t.Errorf("hostOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package scanner - Rate-limit aware HTTP client for git provider calls
+package scanner
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry bounds for rate-limited provider requests
+const (
+	maxRateLimitRetries = 3
+	maxRateLimitWait    = time.Minute     // longer waits are returned to the caller instead
+	rateLimitBackoff    = 2 * time.Second // first wait when the provider gives no hint
+)
+
+// rateLimitTransport retries requests a provider rejected for rate limiting,
+// waiting as long as its Retry-After or X-RateLimit-Reset header asks
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+	now        func() time.Time
+}
+
+// newRateLimitTransport wraps base with bounded rate-limit retries
+func newRateLimitTransport(base http.RoundTripper) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{
+		base:       base,
+		maxRetries: maxRateLimitRetries,
+		maxWait:    maxRateLimitWait,
+		now:        time.Now,
+	}
+}
+
+// providerClient is shared by every call to a git provider, clones
+// included. Configure rebuilds it with the configured clone headers.
+var providerClient = &http.Client{Transport: newRateLimitTransport(http.DefaultTransport)}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || !rateLimited(resp) || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		wait := retryDelay(resp.Header, t.now(), rateLimitBackoff<<attempt)
+		if wait > t.maxWait {
+			return resp, nil
+		}
+		// A consumed body can only be resent if it can be recreated
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimited reports whether a response is a rate-limit rejection. GitHub
+// answers 403 rather than 429 once the primary limit is exhausted.
+func rateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return false
+}
+
+// retryDelay works out how long a provider asked us to wait, from
+// Retry-After (seconds or an HTTP date) or X-RateLimit-Reset (Unix time)
+func retryDelay(h http.Header, now time.Time, fallback time.Duration) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return max(time.Duration(secs)*time.Second, 0)
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return max(at.Sub(now), 0)
+		}
+	}
+	if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if epoch, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return max(time.Unix(epoch, 0).Sub(now), 0)
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,227 @@
+// Package scanner - Routes declared as regular expressions
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regexRoutePattern matches a route whose path is a regular expression
+type regexRoutePattern struct {
+	pattern *regexp.Regexp
+	method  string // fixed method, or empty when captured as the first group
+}
+
+// regexRoutePatterns by file extension
+var regexRoutePatterns = map[string]regexRoutePattern{
+	// Express: app.get(/\/users\/(\d+)/, handler)
+	".js": {pattern: regexp.MustCompile(`\b\w+\.(get|post|put|patch|delete|options|head|all)\s*\(\s*/((?:\\.|[^/\\\n])+)/[a-z]*\s*[,)]`)},
+	".ts": {pattern: regexp.MustCompile(`\b\w+\.(get|post|put|patch|delete|options|head|all)\s*\(\s*/((?:\\.|[^/\\\n])+)/[a-z]*\s*[,)]`)},
+	// Django: re_path(r'^users/(?P<pk>\d+)/$', view)
+	".py": {pattern: regexp.MustCompile(`\bre_path\s*\(\s*r?["']([^"']+)["']`), method: "GET"},
+}
+
+// extractRegexRoutes finds regex routes and normalizes them into readable
+// paths, keeping the original expression in RawPath
+func extractRegexRoutes(ext, filePath string, lines []string) []Endpoint {
+	rp, ok := regexRoutePatterns[ext]
+	if !ok {
+		return nil
+	}
+
+	var found []Endpoint
+	for i, line := range lines {
+		m := rp.pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		method, raw := rp.method, m[len(m)-1]
+		if method == "" {
+			method = strings.ToUpper(m[1])
+		}
+
+		path, params := normalizeRegexRoute(raw)
+		found = append(found, Endpoint{
+			ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), method, i+1),
+			Path:       path,
+			RawPath:    raw,
+			PathParams: params,
+			Method:     method,
+			FilePath:   filePath,
+			LineNumber: i + 1,
+			Tags:       []string{extractTag(filePath, path)},
+		})
+	}
+
+	return found
+}
+
+// regexNormalizer rewrites a route expression as a path template
+type regexNormalizer struct {
+	params     []string
+	positional int
+}
+
+// normalizeRegexRoute turns a route expression into a readable path:
+// named groups become {name}, positional groups {param1}, {param2}, ...
+// and other wildcards become *. Only the first top-level alternative is kept.
+func normalizeRegexRoute(expr string) (string, []string) {
+	expr = strings.TrimPrefix(expr, "^")
+	expr = strings.TrimSuffix(expr, "$")
+
+	n := &regexNormalizer{}
+	path := n.normalize(expr)
+
+	for strings.Contains(path, "**") {
+		path = strings.ReplaceAll(path, "**", "*")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path, n.params
+}
+
+// normalize rewrites expr, recording the parameters it declares
+func (n *regexNormalizer) normalize(expr string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch c {
+		case '\\':
+			if i+1 < len(expr) {
+				next := expr[i+1]
+				if isRegexClassEscape(next) {
+					// \d, \w, ... match variable text
+					b.WriteByte('*')
+				} else {
+					b.WriteByte(next)
+				}
+			}
+			i = skipQuantifier(expr, i+2)
+
+		case '(':
+			end := closingParen(expr, i)
+			if end < 0 {
+				// An unclosed group: the rest is kept as written
+				b.WriteString(expr[i:])
+				return b.String()
+			}
+			inner := expr[i+1 : end]
+			i = end + 1
+			optional := i < len(expr) && (expr[i] == '?' || expr[i] == '*')
+			i = skipQuantifier(expr, i)
+
+			switch {
+			case strings.HasPrefix(inner, "?P<") || (strings.HasPrefix(inner, "?<") && !strings.HasPrefix(inner, "?<=") && !strings.HasPrefix(inner, "?<!")):
+				name := inner[strings.Index(inner, "<")+1:]
+				name = name[:max(strings.Index(name, ">"), 0)]
+				n.params = append(n.params, name)
+				b.WriteString("{" + name + "}")
+			case strings.HasPrefix(inner, "?:"):
+				// Optional literal suffixes such as (?:\.json)? are dropped
+				if !optional {
+					b.WriteString(n.normalize(inner[2:]))
+				}
+			case strings.HasPrefix(inner, "?"):
+				// Lookarounds and flags don't match path text
+			default:
+				n.positional++
+				name := fmt.Sprintf("param%d", n.positional)
+				n.params = append(n.params, name)
+				b.WriteString("{" + name + "}")
+			}
+
+		case '[':
+			i = skipQuantifier(expr, closingBracket(expr, i)+1)
+			b.WriteByte('*')
+
+		case '.':
+			i = skipQuantifier(expr, i+1)
+			b.WriteByte('*')
+
+		case '|':
+			// Keep the first alternative
+			return b.String()
+
+		case '?', '*', '+', '{':
+			i = skipQuantifier(expr, i)
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// isRegexClassEscape reports whether \c is a character class such as \d
+func isRegexClassEscape(c byte) bool {
+	return strings.IndexByte("dDwWsSbB", c) >= 0
+}
+
+// skipQuantifier returns the index after any quantifier starting at i
+func skipQuantifier(expr string, i int) int {
+	if i >= len(expr) {
+		return i
+	}
+	switch expr[i] {
+	case '?', '*', '+':
+		i++
+	case '{':
+		if end := strings.IndexByte(expr[i:], '}'); end >= 0 {
+			i += end + 1
+		} else {
+			return i + 1
+		}
+	default:
+		return i
+	}
+	// Lazy or possessive modifier
+	if i < len(expr) && (expr[i] == '?' || expr[i] == '+') {
+		i++
+	}
+	return i
+}
+
+// closingParen returns the index of the parenthesis closing the group at
+// open, or -1 when the group is never closed
+func closingParen(expr string, open int) int {
+	depth := 0
+	for i := open; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case '[':
+			i = closingBracket(expr, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// closingBracket returns the index of the bracket closing the class at open
+func closingBracket(expr string, open int) int {
+	for i := open + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case ']':
+			if i > open+1 {
+				return i
+			}
+		}
+	}
+	return len(expr) - 1
+}
@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNormalizeRegexRoute verifies capture groups become path parameters
+func TestNormalizeRegexRoute(t *testing.T) {
+	tests := []struct {
+		expr   string
+		path   string
+		params []string
+	}{
+		{`^users/(?P<pk>\d+)/$`, "/users/{pk}", []string{"pk"}},
+		{`\/users\/(\d+)`, "/users/{param1}", []string{"param1"}},
+		{`^articles/(?P<year>[0-9]{4})/(?P<slug>[\w-]+)/$`, "/articles/{year}/{slug}", []string{"year", "slug"}},
+		{`^\/files\/(.*)\/(\d+)$`, "/files/{param1}/{param2}", []string{"param1", "param2"}},
+		{`^/reports(?:\.json)?$`, "/reports", nil},
+		{`^/(?:api|v1)/health`, "/api/health", nil},
+		{`^/assets/.*`, "/assets/*", nil},
+		{`^$`, "/", nil},
+		{`^users/(`, "/users/(", nil},
+		{`^users/(?P<pk>[0-9]+/$`, "/users/(?P<pk>[0-9]+", nil},
+	}
+
+	for _, tt := range tests {
+		path, params := normalizeRegexRoute(tt.expr)
+		if path != tt.path || !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("normalizeRegexRoute(%q) = %q, %v; want %q, %v", tt.expr, path, params, tt.path, tt.params)
+		}
+	}
+}
+
+// TestExpressRegexRoutes verifies regex literals in Express routes are normalized
+func TestExpressRegexRoutes(t *testing.T) {
+	source := `const app = express()
+app.get(/\/users\/(\d+)/, getUser)
+app.delete(/^\/sessions\/(?<token>[a-f0-9]+)$/i, endSession)
+app.get("/plain/:id", plain)
+`
+	endpoints := ScanFile("server.js", source)
+
+	want := []Endpoint{
+		{Method: "GET", Path: "/users/{param1}", RawPath: `\/users\/(\d+)`, PathParams: []string{"param1"}, LineNumber: 2},
+		{Method: "DELETE", Path: "/sessions/{token}", RawPath: `^\/sessions\/(?<token>[a-f0-9]+)$`, PathParams: []string{"token"}, LineNumber: 3},
+		{Method: "GET", Path: "/plain/{id}", LineNumber: 4},
+	}
+	checkRegexRoutes(t, endpoints, want)
+}
+
+// TestDjangoRePath verifies Django re_path routes are normalized
+func TestDjangoRePath(t *testing.T) {
+	source := `from django.urls import path, re_path
+
+urlpatterns = [
+    path('users/', views.users),
+    re_path(r'^users/(?P<pk>\d+)/$', views.user_detail),
+    re_path(r'^archive/(\d{4})/$', views.archive),
+    re_path(r'^broken/(', views.broken),
+]
+`
+	endpoints := ScanFile("app/urls.py", source)
+
+	want := []Endpoint{
+		{Method: "GET", Path: "users/", LineNumber: 4},
+		{Method: "GET", Path: "/users/{pk}", RawPath: `^users/(?P<pk>\d+)/$`, PathParams: []string{"pk"}, LineNumber: 5},
+		{Method: "GET", Path: "/archive/{param1}", RawPath: `^archive/(\d{4})/$`, PathParams: []string{"param1"}, LineNumber: 6},
+		{Method: "GET", Path: "/broken/(", RawPath: `^broken/(`, LineNumber: 7},
+	}
+	checkRegexRoutes(t, endpoints, want)
+}
+
+func checkRegexRoutes(t *testing.T, endpoints, want []Endpoint) {
+	t.Helper()
+	if len(endpoints) != len(want) {
+		t.Fatalf("found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.Method || ep.Path != w.Path || ep.RawPath != w.RawPath || ep.LineNumber != w.LineNumber || !reflect.DeepEqual(ep.PathParams, w.PathParams) {
+			t.Errorf("endpoint %d = %s %s raw=%q params=%v line=%d; want %s %s raw=%q params=%v line=%d",
+				i, ep.Method, ep.Path, ep.RawPath, ep.PathParams, ep.LineNumber, w.Method, w.Path, w.RawPath, w.PathParams, w.LineNumber)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package scanner - Expansion of RESTful resource helpers into CRUD routes
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// resourceConvention describes how a framework lays out a resource's routes
+type resourceConvention struct {
+	formSuffix   string // path of the "new" form: /create (Laravel, Adonis) or /new (Rails, Phoenix)
+	updateMethod string
+	param        func(resource string) string
+}
+
+// resourceConventions by framework
+var resourceConventions = map[string]resourceConvention{
+	"laravel": {formSuffix: "/create", updateMethod: "PUT", param: func(r string) string { return "{" + singularize(r) + "}" }},
+	"adonis":  {formSuffix: "/create", updateMethod: "PUT", param: func(string) string { return ":id" }},
+	"rails":   {formSuffix: "/new", updateMethod: "PATCH", param: func(string) string { return ":id" }},
+	"phoenix": {formSuffix: "/new", updateMethod: "PATCH", param: func(string) string { return ":id" }},
+}
+
+// resourceRoute is one route generated by a resource helper
+type resourceRoute struct {
+	Method string
+	Path   string
+	Action string
+}
+
+// expandResource returns the conventional CRUD routes for a resource.
+// Nested resources use dot notation ("photos.comments"). apiOnly omits the
+// HTML form routes (create/edit).
+func expandResource(framework, resource string, apiOnly bool) ([]resourceRoute, error) {
+	conv, ok := resourceConventions[framework]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource framework %q", framework)
+	}
+
+	// Parent segments of nested resources: /photos/{photo}/comments
+	parts := strings.Split(strings.Trim(resource, "/"), ".")
+	base := ""
+	for _, parent := range parts[:len(parts)-1] {
+		base += "/" + parent + "/" + conv.param(parent)
+	}
+	name := parts[len(parts)-1]
+	collection := base + "/" + name
+	member := collection + "/" + conv.param(name)
+
+	hasForms := !apiOnly
+
+	routes := []resourceRoute{{"GET", collection, "index"}}
+	if hasForms {
+		routes = append(routes, resourceRoute{"GET", collection + conv.formSuffix, "create"})
+	}
+	routes = append(routes,
+		resourceRoute{"POST", collection, "store"},
+		resourceRoute{"GET", member, "show"},
+	)
+	if hasForms {
+		routes = append(routes, resourceRoute{"GET", member + "/edit", "edit"})
+	}
+	routes = append(routes,
+		resourceRoute{conv.updateMethod, member, "update"},
+		resourceRoute{"DELETE", member, "destroy"},
+	)
+
+	return routes, nil
+}
+
+// singularize makes a best-effort English singular for route parameter names
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ses"), strings.HasSuffix(word, "xes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// Resource helper patterns by extension
+var resourcePatterns = map[string]struct {
+	framework string
+	pattern   *regexp.Regexp
+}{
+	// Laravel: Route::resource('photos', PhotoController::class) / Route::apiResource(...)
+	".php": {"laravel", regexp.MustCompile(`Route::(resource|apiResource)\s*\(\s*['"]([^'"]+)['"]`)},
+	// AdonisJS: Route.resource('posts', 'PostsController').apiOnly()
+	".js": {"adonis", regexp.MustCompile(`\bRoute\.(resource)\s*\(\s*['"]([^'"]+)['"]`)},
+	".ts": {"adonis", regexp.MustCompile(`\bRoute\.(resource)\s*\(\s*['"]([^'"]+)['"]`)},
+}
+
+// extractResourceRoutes expands resource helper declarations found in lines
+func extractResourceRoutes(ext, filePath string, lines []string) []Endpoint {
+	rp, ok := resourcePatterns[ext]
+	if !ok {
+		return nil
+	}
+
+	var found []Endpoint
+	for i, line := range lines {
+		m := rp.pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		apiOnly := m[1] == "apiResource" || strings.Contains(line, ".apiOnly()")
+
+		routes, err := expandResource(rp.framework, m[2], apiOnly)
+		if err != nil {
+			continue
+		}
+		for _, route := range routes {
+			found = append(found, Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d-%s", scanID(filePath), route.Method, i+1, route.Action),
+				Path:       route.Path,
+				Method:     route.Method,
+				FilePath:   filePath,
+				LineNumber: i + 1,
+				Tags:       []string{extractTag(filePath, route.Path)},
+			})
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const phpLaravelRoutes = `<?php
+
+use Illuminate\Support\Facades\Route;
+
+Route::get('/status', [StatusController::class, 'show']);
+Route::resource('photos', PhotoController::class);
+Route::apiResource('photos.comments', CommentController::class);
+`
+
+// TestLaravelResourceExpansion verifies Route::resource expands to the seven conventional routes
+func TestLaravelResourceExpansion(t *testing.T) {
+	if !hasAPIIndicators("routes/web.php", phpLaravelRoutes) {
+		t.Fatal("Laravel routes file should have API indicators")
+	}
+
+	endpoints := ScanFile("routes/web.php", phpLaravelRoutes)
+
+	want := []struct{ method, path string }{
+		{"GET", "/status"},
+		// Route::resource('photos')
+		{"GET", "/photos"},
+		{"GET", "/photos/create"},
+		{"POST", "/photos"},
+		{"GET", "/photos/{photo}"},
+		{"GET", "/photos/{photo}/edit"},
+		{"PUT", "/photos/{photo}"},
+		{"DELETE", "/photos/{photo}"},
+		// Route::apiResource('photos.comments') has no form routes
+		{"GET", "/photos/{photo}/comments"},
+		{"POST", "/photos/{photo}/comments"},
+		{"GET", "/photos/{photo}/comments/{comment}"},
+		{"PUT", "/photos/{photo}/comments/{comment}"},
+		{"DELETE", "/photos/{photo}/comments/{comment}"},
+	}
+
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	seen := make(map[string]bool)
+	for i, w := range want {
+		if endpoints[i].Method != w.method || endpoints[i].Path != w.path {
+			t.Errorf("endpoint %d = %s %s, want %s %s", i, endpoints[i].Method, endpoints[i].Path, w.method, w.path)
+		}
+		if seen[endpoints[i].ID] {
+			t.Errorf("duplicate endpoint ID %s", endpoints[i].ID)
+		}
+		seen[endpoints[i].ID] = true
+	}
+}
+
+// TestExpandResourceConventions verifies framework-specific conventions
+func TestExpandResourceConventions(t *testing.T) {
+	routes, err := expandResource("rails", "users", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 7 || routes[1].Path != "/users/new" || routes[5].Method != "PATCH" {
+		t.Errorf("rails routes = %+v", routes)
+	}
+
+	routes, err = expandResource("adonis", "posts", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 5 || routes[2].Path != "/posts/:id" {
+		t.Errorf("adonis api-only routes = %+v", routes)
+	}
+
+	if _, err := expandResource("unknown", "users", false); err == nil {
+		t.Error("expandResource() should reject unknown frameworks")
+	}
+}
@@ -0,0 +1,98 @@
+// Package scanner - Heuristic risk score for review prioritisation
+package scanner
+
+import (
+	"strings"
+)
+
+// Risk signals, the keys of a weights map
+const (
+	RiskUnauthenticated         = "unauthenticated"          // no auth scheme, roles or scopes detected
+	RiskMutating                = "mutating"                 // POST, PUT, PATCH, DELETE or any method
+	RiskUnauthenticatedMutating = "unauthenticated_mutating" // both of the above
+	RiskFileUpload              = "file_upload"              // accepts uploaded files
+	RiskWildcardPath            = "wildcard_path"            // catch-all or regex path
+	RiskAdminPath               = "admin_path"               // path names an admin or internal area
+	RiskAnyOrigin               = "any_origin"               // CORS allows every origin
+)
+
+// DefaultRiskWeights are the points each signal adds when nothing is configured
+var DefaultRiskWeights = map[string]int{
+	RiskUnauthenticated:         1,
+	RiskMutating:                2,
+	RiskUnauthenticatedMutating: 4,
+	RiskFileUpload:              3,
+	RiskWildcardPath:            2,
+	RiskAdminPath:               3,
+	RiskAnyOrigin:               1,
+}
+
+// adminPathSegments mark paths that reach privileged areas
+var adminPathSegments = []string{"admin", "internal", "debug", "manage", "management", "sudo", "superuser"}
+
+// mutatingMethods change server state
+var mutatingMethods = map[string]bool{"POST": true, "PUT": true, "PATCH": true, "DELETE": true, "ANY": true, "ALL": true, TRPCMutation: true}
+
+// riskSignals lists the signals an endpoint shows
+func riskSignals(ep Endpoint) []string {
+	var signals []string
+
+	unauthenticated := ep.Auth == nil && len(ep.RequiredScopes) == 0
+	mutating := mutatingMethods[strings.ToUpper(ep.Method)]
+	if unauthenticated {
+		signals = append(signals, RiskUnauthenticated)
+	}
+	if mutating {
+		signals = append(signals, RiskMutating)
+	}
+	if unauthenticated && mutating {
+		signals = append(signals, RiskUnauthenticatedMutating)
+	}
+	if ep.FileUpload {
+		signals = append(signals, RiskFileUpload)
+	}
+	if ep.RawPath != "" && ep.RawPath != ep.Path || strings.Contains(ep.Path, "*") || strings.Contains(ep.Path, "<path:") || strings.Contains(ep.Path, ":path}") {
+		signals = append(signals, RiskWildcardPath)
+	}
+	if isAdminPath(ep.Path) {
+		signals = append(signals, RiskAdminPath)
+	}
+	if ep.CORS != nil && containsString(ep.CORS.Origins, AnyOrigin) {
+		signals = append(signals, RiskAnyOrigin)
+	}
+
+	return signals
+}
+
+// isAdminPath reports whether any path segment names an admin area,
+// e.g. /admin/users or /api/admin-tools
+func isAdminPath(path string) bool {
+	for _, segment := range strings.Split(strings.ToLower(path), "/") {
+		for _, admin := range adminPathSegments {
+			if segment == admin || strings.HasPrefix(segment, admin+"-") || strings.HasPrefix(segment, admin+"_") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// riskScore sums the weights of an endpoint's signals
+func riskScore(ep Endpoint, weights map[string]int) int {
+	score := 0
+	for _, signal := range riskSignals(ep) {
+		score += weights[signal]
+	}
+	return score
+}
+
+// scoreEndpoints sets the risk score of each endpoint. Client calls
+// aren't served by the repository and aren't scored.
+func scoreEndpoints(found []Endpoint, weights map[string]int) {
+	for i := range found {
+		if found[i].Kind == KindClientCall {
+			continue
+		}
+		found[i].RiskScore = riskScore(found[i], weights)
+	}
+}
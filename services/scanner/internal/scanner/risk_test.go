@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"testing"
+)
+
+// TestRiskScore verifies riskier endpoints outrank plumbing
+func TestRiskScore(t *testing.T) {
+	code := `app.get('/health', health)
+app.delete('/admin/users/:id', deleteUser)
+app.post('/uploads', upload.single('file'), saveUpload)
+app.get('/users', requiredScopes('read:users'), listUsers)
+app.delete('/admin/sessions/:id', passport.authenticate('jwt'), deleteSession)
+`
+	endpoints := ScanFile("routes.js", code)
+	if len(endpoints) != 5 {
+		t.Fatalf("ScanFile() found %d endpoints, want 5", len(endpoints))
+	}
+	health, adminDelete, upload, scoped, jwtDelete := endpoints[0], endpoints[1], endpoints[2], endpoints[3], endpoints[4]
+
+	if adminDelete.RiskScore <= health.RiskScore {
+		t.Errorf("unauthenticated admin DELETE scored %d, want more than health check's %d", adminDelete.RiskScore, health.RiskScore)
+	}
+	if !upload.FileUpload || upload.RiskScore <= health.RiskScore {
+		t.Errorf("upload endpoint file_upload=%v score=%d, want flagged and above %d", upload.FileUpload, upload.RiskScore, health.RiskScore)
+	}
+	if scoped.RiskScore != 0 {
+		t.Errorf("authenticated GET scored %d, want 0", scoped.RiskScore)
+	}
+	// A detected auth scheme counts as authenticated
+	if jwtDelete.Auth == nil || jwtDelete.RiskScore >= adminDelete.RiskScore {
+		t.Errorf("JWT-secured admin DELETE auth=%v score=%d, want authenticated and below %d", jwtDelete.Auth, jwtDelete.RiskScore, adminDelete.RiskScore)
+	}
+	if want := DefaultRiskWeights[RiskMutating] + DefaultRiskWeights[RiskAdminPath]; jwtDelete.RiskScore != want {
+		t.Errorf("JWT-secured admin DELETE scored %d, want %d", jwtDelete.RiskScore, want)
+	}
+
+	// Weights are configurable
+	weights := map[string]int{RiskAdminPath: 10}
+	if got := riskScore(adminDelete, weights); got != 10 {
+		t.Errorf("riskScore() with admin_path=10 only = %d, want 10", got)
+	}
+}
+
+// TestIsAdminPath verifies admin areas match whole segments only
+func TestIsAdminPath(t *testing.T) {
+	tests := map[string]bool{
+		"/admin":             true,
+		"/api/v1/admin/keys": true,
+		"/internal/metrics":  true,
+		"/admin-tools/jobs":  true,
+		"/administrators":    false,
+		"/users/debugger":    false,
+		"/orders":            false,
+	}
+	for path, want := range tests {
+		if got := isAdminPath(path); got != want {
+			t.Errorf("isAdminPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
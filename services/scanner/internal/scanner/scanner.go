@@ -2,20 +2,21 @@
 package scanner
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
 // Configuration constants
@@ -27,26 +28,97 @@ const (
 
 // Endpoint represents a detected API endpoint
 type Endpoint struct {
-	ID          string   `json:"id"`
-	Path        string   `json:"path"`
-	Method      string   `json:"method"`
-	Summary     string   `json:"summary"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
-	FilePath    string   `json:"file_path"`
-	LineNumber  int      `json:"line_number"`
+	ID           string   `json:"id"`
+	Path         string   `json:"path"`
+	RawPath      string   `json:"raw_path,omitempty"` // original expression for regex routes, or the path before STRIP_PATH_PREFIX
+	Method       string   `json:"method"`
+	Summary      string   `json:"summary"`
+	Description  string   `json:"description"`
+	Tags         []string `json:"tags"`
+	FilePath     string   `json:"file_path"`
+	LineNumber   int      `json:"line_number"`
+	APIVersion   string   `json:"api_version,omitempty"`
+	ResponseType string   `json:"response_type,omitempty"`
+
+	PathParams    []string `json:"path_params,omitempty"` // parameters parsed from regex routes
+	QueryParams   []string `json:"query_params,omitempty"`
+	ResponseCodes []int    `json:"response_codes,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
+	Dynamic       bool     `json:"dynamic,omitempty"`        // path built by interpolation; placeholders are best-effort params
+	Conditional   bool     `json:"conditional,omitempty"`    // registered inside an if/switch block
+	EnvRestricted bool     `json:"env_restricted,omitempty"` // only registered in some environments or behind a feature flag
+	EnvCondition  string   `json:"env_condition,omitempty"`  // the environment or flag check, e.g. if (process.env.NODE_ENV !== 'production')
+	RPC           string   `json:"rpc,omitempty"`            // gRPC method behind a grpc-gateway route
+	Upstream      string   `json:"upstream,omitempty"`       // backend a gateway route forwards to
+	Integration   string   `json:"integration,omitempty"`    // API Gateway integration type, e.g. aws_proxy or http_proxy
+	PrefixLine    int      `json:"prefix_line,omitempty"`    // line declaring the controller/router prefix composed into Path
+	Generated     bool     `json:"generated,omitempty"`      // declared in a server stub generated from an OpenAPI spec
+	Commented     bool     `json:"commented,omitempty"`      // declared in a comment, reported when REPORT_COMMENTED_ROUTES is on
+	Branches      []string `json:"branches,omitempty"`       // branches declaring the route, in an all-branches scan
+
+	Pagination      *Pagination `json:"pagination,omitempty"`
+	RequiredScopes  []string    `json:"required_scopes,omitempty"`  // roles/scopes declared by security annotations or middleware
+	Auth            *AuthScheme `json:"auth,omitempty"`             // how callers authenticate, when the route is secured
+	Middleware      []string    `json:"middleware,omitempty"`       // middleware, guards and interceptors run before the handler, in order
+	Examples        []Example   `json:"examples,omitempty"`         // payloads sent by tests, when EXTRACT_EXAMPLES is on
+	Deprecated      bool        `json:"deprecated,omitempty"`       // handler sets a Deprecation or Sunset header
+	Sunset          string      `json:"sunset,omitempty"`           // Sunset header date, as YYYY-MM-DD when parseable
+	CORS            *CORSPolicy `json:"cors,omitempty"`             // CORS declared on the route or its controller
+	ResponseHeaders []string    `json:"response_headers,omitempty"` // headers the handler sets on its responses
+	Streaming       bool        `json:"streaming,omitempty"`        // responds with SSE or a chunked stream
+	Async           bool        `json:"async,omitempty"`            // enqueues a background job or long-polls instead of answering directly
+	FileUpload      bool        `json:"file_upload,omitempty"`      // accepts multipart file uploads
+	ValidatedInput  bool        `json:"validated_input,omitempty"`  // request input is checked by a schema or validator
+	InputModel      string      `json:"input_model,omitempty"`      // validated model, DTO or schema name when known
+	Idempotent      bool        `json:"idempotent,omitempty"`       // safe to retry: by method, or an Idempotency-Key is honoured
+	Cacheable       bool        `json:"cacheable,omitempty"`        // sets a caching Cache-Control header or is cache-annotated
+	RiskScore       int         `json:"risk_score"`                 // review priority heuristic, higher is riskier
+	Owners          []string    `json:"owners,omitempty"`           // CODEOWNERS owners of the file
+	Confidence      float64     `json:"confidence"`                 // 0-1, how surely the match is a real route
+	Category        string      `json:"category"`                   // infra or business
+	ActionName      string      `json:"action_name,omitempty"`      // e.g. "Get user", when ACTION_NAMES is on
+	SourceHash      string      `json:"source_hash,omitempty"`      // hash of the handler's source, when SOURCE_HASHES is on
+	LastModified    *time.Time  `json:"last_modified,omitempty"`    // date of the commit last changing the declaration line, when WITH_BLAME is on
+	LastAuthor      string      `json:"last_author,omitempty"`      // author of that commit
+	Kind            string      `json:"kind,omitempty"`             // client-call for frontend API calls, webhook or callback for requests the API sends, empty for served routes
+	Event           string      `json:"event,omitempty"`            // name of the webhook or callback
+}
+
+// Pagination describes the paging parameters a handler accepts
+type Pagination struct {
+	Params          []string `json:"params"`
+	DefaultPageSize int      `json:"default_page_size,omitempty"`
 }
 
 // ScanStatus represents the status of a scan
 type ScanStatus struct {
-	ID           string     `json:"id"`
-	Status       string     `json:"status"` // queued, scanning, completed, failed
-	URL          string     `json:"url"`
-	FilesScanned int        `json:"files_scanned"`
-	Endpoints    int        `json:"endpoint_count"`
-	StartedAt    time.Time  `json:"started_at"`
-	CompletedAt  *time.Time `json:"completed_at,omitempty"`
-	Error        string     `json:"error,omitempty"`
+	ID            string     `json:"id"`
+	Status        string     `json:"status"` // queued, scanning, completed, failed
+	URL           string     `json:"url"`
+	Branch        string     `json:"branch,omitempty"`
+	Priority      string     `json:"priority,omitempty"`
+	Commit        string     `json:"commit,omitempty"`
+	FilesScanned  int        `json:"files_scanned"`
+	Endpoints     int        `json:"endpoint_count"`
+	StartedAt     time.Time  `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	ErrorCategory string     `json:"error_category,omitempty"` // why a failed scan failed; see ErrorCategoryClone etc.
+	Partial       bool       `json:"partial,omitempty"`        // failed scan with endpoints found before the failure
+	Warning       string     `json:"warning,omitempty"`        // completed scan that found nothing to document, and why
+	Signature     string     `json:"signature,omitempty"`      // HMAC of the completed result when RESULT_SIGNING_KEY is set; see SignResult
+
+	MinConfidence float64  `json:"min_confidence,omitempty"` // endpoints scoring lower were dropped
+	AllBranches   bool     `json:"all_branches,omitempty"`   // every branch head is scanned; see extractBranches
+	DropPaths     []string `json:"drop_paths,omitempty"`     // path globs dropped from the results; DROP_PATHS when nil
+	DroppedPaths  int      `json:"dropped_paths,omitempty"`  // endpoints removed by the drop globs
+	Branches      []string `json:"branches,omitempty"`       // branches scanned by an all-branches scan
+
+	Resources *ScanResources `json:"resources,omitempty"`
+	CORS      *CORSPolicy    `json:"cors,omitempty"` // application-wide CORS setup, if any
+
+	CallbackURL      string             `json:"-"` // receives the configured callback events
+	CallbackTemplate *template.Template `json:"-"` // shapes callback bodies; nil sends CallbackPayload
 }
 
 var (
@@ -55,91 +127,18 @@ var (
 	mu        sync.RWMutex
 )
 
-// API Indicator patterns for Stage 1 (Pre-filtering)
-var (
-	pythonIndicators = []*regexp.Regexp{
-		regexp.MustCompile(`@\w+\.(get|post|put|patch|delete|options|head)`),
-		regexp.MustCompile(`@\w+\.route`),
-		regexp.MustCompile(`\b(path|re_path)\s*\(`),
-		regexp.MustCompile(`\b(APIRouter|Blueprint)\b`),
-		regexp.MustCompile(`from\s+fastapi\s+import`),
-		regexp.MustCompile(`from\s+flask\s+import`),
-	}
-
-	jsIndicators = []*regexp.Regexp{
-		regexp.MustCompile(`\.(get|post|put|patch|delete|options|head|all)\s*\(`),
-		regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Options|Head|Controller)\b`),
-		regexp.MustCompile(`\b(Router|express|fastify)\s*\(`),
-		regexp.MustCompile(`from\s+['"](@nestjs|express|fastify)`),
-		regexp.MustCompile(`import.*\{.*Router.*\}`),
-	}
-
-	goIndicators = []*regexp.Regexp{
-		regexp.MustCompile(`\.(GET|POST|PUT|PATCH|DELETE|OPTIONS|HEAD)\s*\(`),
-		regexp.MustCompile(`\bHandleFunc\s*\(`),
-		regexp.MustCompile(`\bServeHTTP\b`),
-		regexp.MustCompile(`"github\.com/(gin-gonic|labstack|gofiber)`),
-	}
-
-	javaIndicators = []*regexp.Regexp{
-		regexp.MustCompile(`@(GetMapping|PostMapping|PutMapping|PatchMapping|DeleteMapping|RequestMapping)`),
-		regexp.MustCompile(`@RestController`),
-		regexp.MustCompile(`@Controller`),
-	}
+// ErrScanNotFound is returned when no scan exists for an ID
+var ErrScanNotFound = errors.New("scan not found")
 
-	csharpIndicators = []*regexp.Regexp{
-		regexp.MustCompile(`\[(HttpGet|HttpPost|HttpPut|HttpPatch|HttpDelete)\]`),
-		regexp.MustCompile(`\[Route\(`),
-		regexp.MustCompile(`\[ApiController\]`),
-	}
+// Warnings for scans that complete without endpoints, telling a repository
+// with nothing to scan (wrong branch, empty repo) from one without routes
+const (
+	WarningNoSupportedFiles = "No supported source files found: check the branch, or that the repository is in a supported language"
+	WarningNoEndpoints      = "Supported source files found, but no endpoints were detected in them"
 )
 
-// Endpoint extraction patterns for Stage 2 (Deep extraction)
-var (
-	// Python patterns
-	pythonPatterns = []*regexp.Regexp{
-		// FastAPI - flexible variable names
-		regexp.MustCompile(`@\w+\.(get|post|put|patch|delete|options|head)\s*\(\s*["']([^"']+)["']`),
-		// Flask - route with methods
-		regexp.MustCompile(`@\w+\.route\s*\(\s*["']([^"']+)["'].*?methods\s*=\s*\[["']([^"'\]]+)["']`),
-		// Flask - simple route (defaults to GET)
-		regexp.MustCompile(`@\w+\.route\s*\(\s*["']([^"']+)["']`),
-		// Django URL patterns
-		regexp.MustCompile(`(?:path|re_path)\s*\(\s*["']([^"']+)["']`),
-	}
-
-	// JavaScript/TypeScript patterns
-	jsPatterns = []*regexp.Regexp{
-		// Express/Fastify - any variable name
-		regexp.MustCompile(`\w+\.(get|post|put|patch|delete|options|head|all)\s*\(\s*["'\x60]([^"'\x60]+)["'\x60]`),
-		// NestJS decorators
-		regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Options|Head)\s*\(\s*["']?([^"'\)]*?)["']?\s*\)`),
-	}
-
-	// Go patterns
-	goPatterns = []*regexp.Regexp{
-		// Gin, Echo - method-specific
-		regexp.MustCompile(`\w+\.(GET|POST|PUT|PATCH|DELETE|OPTIONS|HEAD)\s*\(\s*["']([^"']+)["']`),
-		// Standard library
-		regexp.MustCompile(`HandleFunc\s*\(\s*["']([^"']+)["']`),
-		// Gorilla mux
-		regexp.MustCompile(`Handle(?:Func)?\s*\(\s*["']([^"']+)["'].*?\.Methods\s*\(\s*["']([^"']+)["']`),
-	}
-
-	// Java patterns
-	javaPatterns = []*regexp.Regexp{
-		// Spring Boot method-level mappings
-		regexp.MustCompile(`@(GetMapping|PostMapping|PutMapping|PatchMapping|DeleteMapping)\s*\(\s*(?:value\s*=\s*)?["']([^"'\)]+)["']`),
-		// Note: @RequestMapping is typically used at class level, not extracted as endpoint
-	}
-
-	// C# patterns
-	csharpPatterns = []*regexp.Regexp{
-		// ASP.NET Web API
-		regexp.MustCompile(`\[(HttpGet|HttpPost|HttpPut|HttpPatch|HttpDelete)\s*\(\s*"([^"]+)"\s*\)\]`),
-		regexp.MustCompile(`\[Route\s*\(\s*"([^"]+)"\s*\)\]`),
-	}
-)
+// readFile is used by Stage 2 to load file contents (overridable in tests)
+var readFile = os.ReadFile
 
 // Directories to skip during scanning
 var excludedDirs = map[string]bool{
@@ -160,26 +159,28 @@ var excludedDirs = map[string]bool{
 	"obj":          true,
 }
 
-// Supported file extensions
-var supportedExtensions = map[string]bool{
-	".py":   true,
-	".js":   true,
-	".ts":   true,
-	".jsx":  true,
-	".tsx":  true,
-	".go":   true,
-	".java": true,
-	".cs":   true,
+// skipDir reports whether the walk skips a directory. With IncludeHidden
+// set, excluded dot-directories such as .env are walked; .git never is.
+func skipDir(name string) bool {
+	if config.IncludeHidden && name != ".git" && strings.HasPrefix(name, ".") {
+		return false
+	}
+	return excludedDirs[name]
 }
 
+// Supported file extensions, filled in as language extractors register
+var supportedExtensions = make(map[string]bool)
+
 // Initialize sets up the scanner
 func Initialize() {
 	log.Println("🔍 Scanner initialized with enhanced patterns:")
-	log.Printf("   Python indicators: %d patterns", len(pythonIndicators))
-	log.Printf("   JavaScript/TypeScript indicators: %d patterns", len(jsIndicators))
-	log.Printf("   Go indicators: %d patterns", len(goIndicators))
-	log.Printf("   Java indicators: %d patterns", len(javaIndicators))
-	log.Printf("   C# indicators: %d patterns", len(csharpIndicators))
+	for _, x := range registeredExtractors() {
+		if languageDisabled(x) {
+			log.Printf("   %s disabled by DISABLED_LANGUAGES", x.Name())
+			continue
+		}
+		log.Printf("   %s indicators: %d patterns (%s)", x.Name(), len(x.Indicators()), strings.Join(handledFiles(x), ", "))
+	}
 }
 
 // GetStatus returns the status of a scan
@@ -189,7 +190,7 @@ func GetStatus(scanID string) (*ScanStatus, error) {
 
 	status, exists := scans[scanID]
 	if !exists {
-		return nil, fmt.Errorf("scan not found")
+		return nil, ErrScanNotFound
 	}
 	return status, nil
 }
@@ -201,7 +202,7 @@ func GetEndpoints(scanID string) ([]Endpoint, error) {
 
 	eps, exists := endpoints[scanID]
 	if !exists {
-		return nil, fmt.Errorf("scan not found")
+		return nil, ErrScanNotFound
 	}
 	return eps, nil
 }
@@ -209,8 +210,16 @@ func GetEndpoints(scanID string) ([]Endpoint, error) {
 // cloneRepository clones a Git repository to a temporary directory
 // It tries the specified branch first, then falls back to main, master, and finally no branch (default)
 func cloneRepository(url, branch, token string) (string, error) {
+	token = cloneToken(url, token)
+	url = rewriteCloneURL(url, config.CloneProtocol)
+	logURL := redact(url, token)
+	auth, err := cloneAuth(url, token)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCloneAuth, err)
+	}
+
 	// Create temp directory
-	tmpDir, err := os.MkdirTemp("", "scanner-*")
+	tmpDir, err := os.MkdirTemp(config.TempDir, "scanner-*")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp dir: %w", err)
 	}
@@ -237,7 +246,7 @@ func cloneRepository(url, branch, token string) (string, error) {
 	for _, tryBranch := range uniqueBranches {
 		// Clean up previous attempt
 		os.RemoveAll(tmpDir)
-		tmpDir, err = os.MkdirTemp("", "scanner-*")
+		tmpDir, err = os.MkdirTemp(config.TempDir, "scanner-*")
 		if err != nil {
 			return "", fmt.Errorf("failed to create temp dir: %w", err)
 		}
@@ -245,23 +254,16 @@ func cloneRepository(url, branch, token string) (string, error) {
 		// Prepare clone options
 		cloneOptions := &git.CloneOptions{
 			URL:      url,
+			Auth:     auth,
 			Progress: nil, // Silent clone
 		}
 
 		// Add branch if specified
 		if tryBranch != "" {
 			cloneOptions.ReferenceName = plumbing.NewBranchReferenceName(tryBranch)
-			log.Printf("📦 Cloning repository: %s (branch: %s)", url, tryBranch)
+			log.Printf("📦 Cloning repository: %s (branch: %s)", logURL, tryBranch)
 		} else {
-			log.Printf("📦 Cloning repository: %s (default branch)", url)
-		}
-
-		// Add authentication if token provided
-		if token != "" {
-			cloneOptions.Auth = &http.BasicAuth{
-				Username: "x-access-token", // GitHub token auth
-				Password: token,
-			}
+			log.Printf("📦 Cloning repository: %s (default branch)", logURL)
 		}
 
 		// Clone the repository
@@ -277,41 +279,68 @@ func cloneRepository(url, branch, token string) (string, error) {
 		}
 
 		lastErr = err
-		log.Printf("⚠️ Failed to clone with branch '%s': %v", tryBranch, err)
+		log.Printf("⚠️ Failed to clone with branch '%s': %s", tryBranch, redact(err.Error(), token))
 	}
 
 	// All attempts failed
 	os.RemoveAll(tmpDir) // Cleanup
-	return "", fmt.Errorf("failed to clone repository: %w", lastErr)
+	return "", fmt.Errorf("failed to clone repository: %w", redactedError{lastErr, []string{token}})
+}
+
+// headCommit returns the SHA checked out in dir, or "" if it can't be read
+func headCommit(dir string) string {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
 }
 
 // hasAPIIndicators performs Stage 1 pre-filtering
 func hasAPIIndicators(filePath, content string) bool {
-	ext := strings.ToLower(filepath.Ext(filePath))
+	indicators, keywords := indicatorsFor(filePath)
+	if indicators == nil {
+		return false
+	}
 
-	var indicators []*regexp.Regexp
-	switch ext {
-	case ".py":
-		indicators = pythonIndicators
-	case ".js", ".ts", ".jsx", ".tsx":
-		indicators = jsIndicators
-	case ".go":
-		indicators = goIndicators
-	case ".java":
-		indicators = javaIndicators
-	case ".cs":
-		indicators = csharpIndicators
-	default:
+	// Cheap substring check first: every indicator needs one of the keywords
+	if !containsAnyKeyword(content, keywords) {
 		return false
 	}
 
-	// Quick scan for any indicator
+	return matchesAnyIndicator(content, indicators)
+}
+
+// indicatorsFor returns the Stage 1 indicators and prefilter keywords for a file
+func indicatorsFor(filePath string) ([]*regexp.Regexp, []string) {
+	x, ok := extractorFor(filePath)
+	if !ok {
+		return nil, nil
+	}
+	return x.Indicators(), x.Keywords()
+}
+
+// matchesAnyIndicator runs the full indicator regex set
+func matchesAnyIndicator(content string, indicators []*regexp.Regexp) bool {
 	for _, pattern := range indicators {
 		if pattern.MatchString(content) {
 			return true
 		}
 	}
+	return false
+}
 
+// containsAnyKeyword reports whether content contains any of the keywords
+func containsAnyKeyword(content string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(content, keyword) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -326,21 +355,20 @@ func getCodeFiles(rootDir string) ([]string, error) {
 
 		// Skip excluded directories
 		if d.IsDir() {
-			if excludedDirs[d.Name()] {
+			if skipDir(d.Name()) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Check if file has supported extension
-		ext := strings.ToLower(filepath.Ext(path))
-		if supportedExtensions[ext] {
+		// Check if file has a supported extension or name
+		if _, ok := extractorFor(path); ok {
 			files = append(files, path)
 		}
 
 		// Safety limit
 		if len(files) >= MaxFilesToScan {
-			return fmt.Errorf("max files limit reached")
+			return ErrMaxFiles
 		}
 
 		return nil
@@ -392,22 +420,37 @@ func getLikelyAPIFiles(rootDir string) ([]string, error) {
 }
 
 // StartScan begins scanning a repository
-func StartScan(scanID, url, branch, token string) {
+func StartScan(job ScanJob) {
+	scanID, url, branch := job.ScanID, job.URL, job.Branch
+	token := cloneToken(url, job.Token)
+	defer releaseBatchTokens(scanID)
+
 	// Initialize scan status
 	mu.Lock()
 	scans[scanID] = &ScanStatus{
 		ID:        scanID,
 		Status:    "scanning",
-		URL:       url,
+		URL:       redact(url),
+		Branch:    branch,
+		Priority:  string(job.Priority),
 		StartedAt: time.Now(),
+
+		MinConfidence:    job.MinConfidence,
+		AllBranches:      job.AllBranches,
+		DropPaths:        job.DropPaths,
+		CallbackURL:      job.CallbackURL,
+		CallbackTemplate: job.CallbackTemplate,
 	}
 	endpoints[scanID] = []Endpoint{}
 	mu.Unlock()
+	notifyCallback(scanID, CallbackStarted)
 
 	log.Printf("\n%s", strings.Repeat("=", 70))
 	log.Printf("🔍 SCAN STARTED: %s", scanID)
-	log.Printf("📦 Repository: %s", url)
-	if branch != "" {
+	log.Printf("📦 Repository: %s", redact(url))
+	if job.AllBranches {
+		log.Printf("🌿 Branch: all")
+	} else if branch != "" {
 		log.Printf("🌿 Branch: %s", branch)
 	}
 	log.Printf("%s", strings.Repeat("=", 70))
@@ -416,45 +459,67 @@ func StartScan(scanID, url, branch, token string) {
 	log.Printf("\n📥 STEP 1/4: Cloning repository...")
 	tmpDir, err := cloneRepository(url, branch, token)
 	if err != nil {
-		mu.Lock()
-		now := time.Now()
-		scans[scanID].Status = "failed"
-		scans[scanID].Error = fmt.Sprintf("Failed to clone repository: %v", err)
-		scans[scanID].CompletedAt = &now
-		mu.Unlock()
+		failScan(scanID, errorCategory(err, ErrorCategoryClone), fmt.Sprintf("Failed to clone repository: %v", err), nil)
 		log.Printf("❌ FAILED: Unable to clone repository - %v", err)
 		return
 	}
-	defer os.RemoveAll(tmpDir) // Cleanup temp directory
 	log.Printf("✅ Repository cloned to: %s", tmpDir)
 
+	if job.Commit != "" {
+		if err := checkoutCommit(tmpDir, job.Commit, token); err != nil {
+			os.RemoveAll(tmpDir)
+			msg := redact(err.Error(), token)
+			failScan(scanID, errorCategory(err, ErrorCategoryClone), "Failed to check out commit: "+msg, nil)
+			log.Printf("❌ FAILED: Unable to check out commit %s - %s", job.Commit, msg)
+			return
+		}
+		log.Printf("📌 Checked out commit %s", job.Commit)
+	}
+
+	commit := headCommit(tmpDir)
+	mu.Lock()
+	scans[scanID].Commit = commit
+	mu.Unlock()
+	recordCloneSize(scanID, dirSize(tmpDir))
+	saveScanState(scanID, tmpDir)
+
+	// Keep the checkout for re-extraction when the clone cache is enabled
+	if config.CloneCache {
+		defer cacheClone(scanID, tmpDir, commit)
+	} else {
+		defer os.RemoveAll(tmpDir) // Cleanup temp directory
+	}
+
+	scanCheckout(scanID, tmpDir)
+}
+
+// scanCheckout runs discovery, pre-filtering and extraction (steps 2-4)
+// against an already cloned checkout and records the outcome for scanID
+func scanCheckout(scanID, rootDir string) {
+	heap := newHeapSampler()
+	defer func() { recordPeakHeap(scanID, heap.Peak()) }()
+
 	// Step 2: Discover all code files
 	log.Printf("\n📂 STEP 2/4: Discovering code files...")
-	allFiles, err := getCodeFiles(tmpDir)
+	allFiles, err := getCodeFiles(rootDir)
 	if err != nil {
-		mu.Lock()
-		now := time.Now()
-		scans[scanID].Status = "failed"
-		scans[scanID].Error = fmt.Sprintf("Failed to discover files: %v", err)
-		scans[scanID].CompletedAt = &now
-		mu.Unlock()
+		failScan(scanID, errorCategory(err, ErrorCategoryExtraction), fmt.Sprintf("Failed to discover files: %v", err), nil)
 		log.Printf("❌ FAILED: Unable to discover files - %v", err)
 		return
 	}
 	log.Printf("📊 Found %d code files across supported languages", len(allFiles))
+	if len(allFiles) == 0 {
+		log.Printf("⚠️  %s", WarningNoSupportedFiles)
+	}
 
 	// Step 3: Pre-filter for API files (Stage 1)
 	log.Printf("\n🔍 STEP 3/4: Pre-filtering for API indicators...")
 	log.Printf("   Scanning files for API framework markers...")
 
-	apiFiles, err := getLikelyAPIFiles(tmpDir)
+	apiFiles, err := getLikelyAPIFiles(rootDir)
+	heap.sample()
 	if err != nil {
-		mu.Lock()
-		now := time.Now()
-		scans[scanID].Status = "failed"
-		scans[scanID].Error = fmt.Sprintf("Failed to scan files: %v", err)
-		scans[scanID].CompletedAt = &now
-		mu.Unlock()
+		failScan(scanID, errorCategory(err, ErrorCategoryExtraction), fmt.Sprintf("Failed to scan files: %v", err), nil)
 		log.Printf("❌ FAILED: Pre-filtering error - %v", err)
 		return
 	}
@@ -466,27 +531,57 @@ func StartScan(scanID, url, branch, token string) {
 
 	// Step 4: Extract endpoints from API files (Stage 2)
 	log.Printf("\n🎯 STEP 4/4: Extracting endpoints from API files...")
+	mu.RLock()
+	minConfidence := scans[scanID].MinConfidence
+	allBranches := scans[scanID].AllBranches
+	dropGlobs := scans[scanID].DropPaths
+	mu.RUnlock()
 	var allEndpoints []Endpoint
-	processedFiles := 0
+	var processedFiles int
+	if allBranches {
+		allEndpoints, processedFiles, err = extractBranches(scanID, rootDir, minConfidence)
+	} else {
+		allEndpoints, processedFiles, err = extractEndpoints(rootDir, apiFiles, minConfidence)
+	}
+	heap.sample()
+	if err != nil {
+		failScan(scanID, errorCategory(err, ErrorCategoryExtraction), fmt.Sprintf("Failed to extract endpoints: %v", err), allEndpoints)
+		log.Printf("❌ FAILED: Extraction error after %d endpoint(s) - %v", len(allEndpoints), err)
+		return
+	}
 
-	for _, filePath := range apiFiles {
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			continue
-		}
+	// Paths that are always noise, per scan or by the server's DROP_PATHS
+	if dropGlobs == nil {
+		dropGlobs = config.DropPaths
+	}
+	allEndpoints, dropped := dropPaths(allEndpoints, dropGlobs)
+	if dropped > 0 {
+		log.Printf("🗑️  Dropped %d endpoint(s) matching %s", dropped, strings.Join(dropGlobs, ", "))
+	}
 
-		// Extract relative path from repo root
-		relPath, _ := filepath.Rel(tmpDir, filePath)
+	// Branch scans attach these from each branch's own checkout
+	if !allBranches {
+		attachFileContext(rootDir, allFiles, allEndpoints)
+		heap.sample()
+	}
 
-		// Scan file for endpoints
-		fileEndpoints := ScanFile(relPath, string(content))
-		if len(fileEndpoints) > 0 {
-			allEndpoints = append(allEndpoints, fileEndpoints...)
-			processedFiles++
-			log.Printf("   📄 %s → %d endpoint(s)", relPath, len(fileEndpoints))
+	// Optional user transform, e.g. redacting internal paths, before anything is stored
+	if config.PostProcessCommand != "" {
+		if allEndpoints, err = postProcess(allEndpoints); err != nil {
+			failScan(scanID, errorCategory(err, ErrorCategoryExtraction), fmt.Sprintf("Failed to post-process endpoints: %v", err), nil)
+			log.Printf("❌ FAILED: Post-processing error - %v", err)
+			return
 		}
 	}
 
+	// Global CORS often lives in bootstrap or config files without routes
+	cors := scanGlobalCORS(rootDir, allFiles)
+
+	mu.RLock()
+	startedAt := scans[scanID].StartedAt
+	signature := signScan(scanID, scans[scanID].Commit, allEndpoints)
+	mu.RUnlock()
+
 	// Final summary
 	log.Printf("\n%s", strings.Repeat("=", 70))
 	log.Printf("✅ SCAN COMPLETED: %s", scanID)
@@ -495,7 +590,7 @@ func StartScan(scanID, url, branch, token string) {
 	log.Printf("   • Files with API indicators: %d (%.1f%%)", len(apiFiles), float64(len(apiFiles))/float64(len(allFiles))*100)
 	log.Printf("   • Files processed: %d", processedFiles)
 	log.Printf("   • Endpoints discovered: %d", len(allEndpoints))
-	log.Printf("   • Duration: %v", time.Since(scans[scanID].StartedAt).Round(time.Millisecond))
+	log.Printf("   • Duration: %v", time.Since(startedAt).Round(time.Millisecond))
 	log.Printf("%s\n", strings.Repeat("=", 70))
 
 	// Update final status
@@ -505,137 +600,135 @@ func StartScan(scanID, url, branch, token string) {
 	scans[scanID].FilesScanned = len(apiFiles)
 	scans[scanID].Endpoints = len(allEndpoints)
 	scans[scanID].CompletedAt = &now
+	scans[scanID].CORS = cors
+	scans[scanID].Warning = scanWarning(len(allFiles), len(allEndpoints))
+	scans[scanID].Signature = signature
+	scans[scanID].DroppedPaths = dropped
 	endpoints[scanID] = allEndpoints
 	mu.Unlock()
+	saveScanState(scanID, "")
+	notifyCallback(scanID, CallbackCompleted)
+}
+
+// scanWarning explains a completed scan that found no endpoints
+func scanWarning(codeFiles, endpointCount int) string {
+	switch {
+	case codeFiles == 0:
+		return WarningNoSupportedFiles
+	case endpointCount == 0:
+		return WarningNoEndpoints
+	}
+	return ""
+}
+
+// extractEndpoints performs Stage 2 over the pre-filtered files, keeping
+// endpoints scoring at least minConfidence. On error it returns the
+// endpoints extracted so far alongside the error.
+func extractEndpoints(rootDir string, apiFiles []string, minConfidence float64) ([]Endpoint, int, error) {
+	var allEndpoints []Endpoint
+	var processedFiles int
+	var err error
+
+	// The buffered path collects the same stream the export pipeline writes out
+	out := make(chan Endpoint)
+	go func() {
+		defer close(out)
+		// Dropped paths are counted by the caller
+		processedFiles, err = streamEndpoints(rootDir, apiFiles, minConfidence, nil, out)
+	}()
+	for ep := range out {
+		allEndpoints = append(allEndpoints, ep)
+	}
+	// Streamed endpoints are sent before the rest of their directory is read
+	flagShadowedAcrossFiles(allEndpoints)
+
+	return allEndpoints, processedFiles, err
+}
+
+// attachFileContext adds what the repository says about endpoints beyond
+// their declarations, each when enabled: payloads from tests, descriptions
+// from README.md/API.md, and when each declaration was last changed and by
+// whom. Files are read from the checkout at rootDir as it is now.
+func attachFileContext(rootDir string, allFiles []string, eps []Endpoint) {
+	// Link payloads from tests to the endpoints they exercise
+	if config.ExtractExamples {
+		attachExamples(rootDir, allFiles, eps)
+	}
+	if config.ExtractDocs {
+		attachDocs(rootDir, eps)
+	}
+	if config.WithBlame {
+		attachBlame(rootDir, eps)
+	}
+}
+
+// failScan marks a scan as failed with the category and message of the
+// failure, keeping any partial endpoints found before it
+func failScan(scanID, category, message string, partial []Endpoint) {
+	// Deferred first so it runs after the unlock below
+	defer notifyCallback(scanID, CallbackFailed)
+	defer saveScanState(scanID, "")
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now()
+	scans[scanID].Status = "failed"
+	scans[scanID].Error = message
+	scans[scanID].ErrorCategory = category
+	scans[scanID].CompletedAt = &now
+	if len(partial) > 0 {
+		scans[scanID].Partial = true
+		scans[scanID].Endpoints = len(partial)
+		endpoints[scanID] = partial
+	}
 }
 
 // ScanFile scans a single file for API endpoints (Stage 2 - Deep extraction)
 func ScanFile(filePath string, content string) []Endpoint {
-	var found []Endpoint
+	return scanFile(filePath, content, nil)
+}
+
+// scanFile is ScanFile with access to the other files of the repository
+// through load, for extractors following references across files
+func scanFile(filePath, content string, load FileLoader) []Endpoint {
 	ext := strings.ToLower(filepath.Ext(filePath))
 
-	var patterns []*regexp.Regexp
-	switch ext {
-	case ".py":
-		patterns = pythonPatterns
-	case ".js", ".ts", ".jsx", ".tsx":
-		patterns = jsPatterns
-	case ".go":
-		patterns = goPatterns
-	case ".java":
-		patterns = javaPatterns
-	case ".cs":
-		patterns = csharpPatterns
-	default:
-		return found
-	}
-
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	lineNum := 0
-
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-
-		for _, pattern := range patterns {
-			matches := pattern.FindStringSubmatch(line)
-			if len(matches) >= 2 {
-				var method, path string
-
-				// Handle different pattern formats per language
-				if ext == ".java" {
-					// Java Spring Boot: @GetMapping, @PostMapping, etc.
-					if len(matches) >= 3 {
-						// Extract method from annotation (GetMapping -> GET)
-						annotation := matches[1]
-						if strings.HasSuffix(annotation, "Mapping") {
-							method = strings.ToUpper(strings.TrimSuffix(annotation, "Mapping"))
-						} else {
-							method = strings.ToUpper(annotation)
-						}
-						path = matches[2]
-					} else if len(matches) == 2 {
-						// @RequestMapping with just path
-						method = "GET" // Default
-						path = matches[1]
-					}
-				} else if ext == ".py" {
-					// Python - check which pattern matched
-					if strings.Contains(line, ".route") && strings.Contains(line, "methods") && len(matches) == 3 {
-						// Flask with methods: @bp.route('/path', methods=['GET'])
-						// Pattern captures: path, method
-						path = matches[1]
-						method = strings.ToUpper(matches[2])
-					} else if strings.Contains(line, "@") && strings.Contains(line, ".route") && len(matches) == 2 {
-						// Flask simple route (no method specified)
-						method = "GET" // Flask defaults to GET
-						path = matches[1]
-					} else if len(matches) >= 3 {
-						// FastAPI/Flask: @app.get('/path')
-						// Pattern captures: method, path
-						method = strings.ToUpper(matches[1])
-						path = matches[2]
-					} else if strings.Contains(line, "path(") || strings.Contains(line, "re_path(") {
-						// Django path/re_path - no method in pattern
-						method = "GET" // Default (Django views specify method in view function)
-						path = matches[1]
-					} else {
-						continue
-					}
-				} else if ext == ".go" {
-					// Go patterns
-					if len(matches) >= 3 {
-						method = strings.ToUpper(matches[1])
-						path = matches[2]
-					} else if len(matches) == 2 {
-						// HandleFunc - no method specified
-						method = "ANY"
-						path = matches[1]
-					}
-				} else if ext == ".cs" {
-					// C# patterns
-					if len(matches) >= 3 {
-						// [HttpGet(...)] format
-						method = strings.ToUpper(strings.TrimPrefix(matches[1], "Http"))
-						path = matches[2]
-					} else if len(matches) == 2 {
-						// [Route(...)] format  - no method specified
-						method = "ANY"
-						path = matches[1]
-					}
-				} else {
-					// JavaScript/TypeScript and others
-					if len(matches) >= 3 {
-						method = strings.ToUpper(matches[1])
-						path = matches[2]
-					} else {
-						continue
-					}
-				}
-
-				// Skip invalid paths (empty paths are valid for decorators like @Get() in NestJS)
-				// For TypeScript/JS, allow empty paths; for others, skip
-				if path == "" && !strings.Contains(ext, ".ts") && !strings.Contains(ext, ".js") && !strings.Contains(ext, ".tsx") && !strings.Contains(ext, ".jsx") {
-					continue
-				}
-
-				// Generate endpoint ID
-				endpointID := fmt.Sprintf("%s-%s-%d", scanID(filePath), method, lineNum)
-
-				found = append(found, Endpoint{
-					ID:         endpointID,
-					Path:       path,
-					Method:     method,
-					FilePath:   filePath,
-					LineNumber: lineNum,
-					Tags:       []string{extractTag(filePath)},
-				})
-
-				// Break after finding first match to avoid duplicate endpoints from multiple patterns
-				break
-			}
-		}
+	x, ok := extractorFor(filePath)
+	if !ok {
+		return nil
+	}
+	var found []Endpoint
+	if rx, ok := x.(RefExtractor); ok {
+		found = rx.ExtractRefs(filePath, content, load)
+	} else {
+		found = x.Extract(filePath, content)
+	}
+
+	// Enrichment windows rely on declaration order
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].LineNumber < found[j].LineNumber
+	})
+
+	applyStripPrefix(found, config.StripPrefix)
+
+	lines := sourceLines(content)
+	enrichEndpoints(found, lines)
+	applyAuthSchemes(ext, found, lines)
+	applyClassCORS(ext, found, lines)
+	applyMiddleware(ext, found, lines)
+	flagShadowedRoutes(ext, found, lines)
+	classifyEndpoints(found, config.InfraPaths)
+	scoreEndpoints(found, config.RiskWeights)
+	applyMethodCase(found, lines, config.MethodCase)
+	applyParamStyle(found, config.ParamStyle)
+	if config.ActionNames {
+		applyActionNames(found)
+	}
+	if config.SourceHashes {
+		applySourceHashes(ext, found, lines)
 	}
+	scoreConfidence(ext, found, lines)
+	applyTagRewrites(found, config.TagRewrites)
 
 	return found
 }
@@ -645,11 +738,64 @@ func scanID(filePath string) string {
 	return strings.ReplaceAll(filepath.Base(filePath), ".", "-")
 }
 
-// Helper function to extract tag from file path
-func extractTag(filePath string) string {
+// extOf returns the lowercase extension of a file path
+func extOf(filePath string) string {
+	return strings.ToLower(filepath.Ext(filePath))
+}
+
+// extractTag derives an endpoint's tag using the configured strategy:
+// the file's parent directory, or the route's first meaningful path segment
+func extractTag(filePath, path string) string {
+	if config.TagStrategy == TagStrategyPath {
+		if tag := pathTag(path); tag != "" {
+			return tag
+		}
+	}
 	dir := filepath.Dir(filePath)
 	if dir == "." || dir == "/" {
 		return "api"
 	}
 	return filepath.Base(dir)
 }
+
+// versionSegmentPattern matches version prefixes such as v1 or v2.1
+var versionSegmentPattern = regexp.MustCompile(`(?i)^v\d+(?:\.\d+)?$`)
+
+// pathTag returns the first path segment that isn't an api/version prefix
+// or a parameter: /api/v1/users/{id} -> users
+func pathTag(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "", strings.EqualFold(segment, "api"),
+			versionSegmentPattern.MatchString(segment),
+			pathParamPattern.MatchString(segment), strings.ContainsAny(segment, "*("):
+			continue
+		}
+		return strings.ToLower(segment)
+	}
+	return ""
+}
+
+// pathParamPattern matches :id, {id} and <id> / <int:id> style path parameters
+var pathParamPattern = regexp.MustCompile(`:\w+|\{[^}/]+\}|<[^>/]+>`)
+
+// joinPathSegments joins a route prefix and path with a single leading
+// slash and no trailing slash: ("api/cats/", "/") is /api/cats
+func joinPathSegments(prefix, path string) string {
+	var parts []string
+	for _, part := range []string{prefix, path} {
+		if part = strings.Trim(part, "/"); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// canonicalPath reduces a path to a style-independent form for comparisons
+func canonicalPath(path string) string {
+	path = pathParamPattern.ReplaceAllString(path, "{}")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
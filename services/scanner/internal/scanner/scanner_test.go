@@ -1,7 +1,18 @@
 package scanner
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // Test data for pattern matching
@@ -320,7 +331,7 @@ func TestScanFile(t *testing.T) {
 			content:       javaSpring,
 			wantEndpoints: 3,
 			checkFirst: &Endpoint{
-				Path:   "/users",
+				Path:   "/api/users",
 				Method: "GET", // Corrected: GetMapping -> GET
 			},
 		},
@@ -383,6 +394,350 @@ func TestPythonPatternVariations(t *testing.T) {
 	}
 }
 
+// TestPythonKeywordPathArguments tests decorators passing the path by keyword
+func TestPythonKeywordPathArguments(t *testing.T) {
+	tests := []struct {
+		code   string
+		method string
+		path   string
+	}{
+		{`@router.get(path="/users")`, "GET", "/users"},
+		{`@router.post(response_model=UserOut, path="/users")`, "POST", "/users"},
+		{`@app.route(rule="/orders", methods=["POST"])`, "POST", "/orders"},
+		{`@bp.route(rule='/health')`, "GET", "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			endpoints := ScanFile("test.py", tt.code)
+			if len(endpoints) != 1 {
+				t.Fatalf("ScanFile() found %d endpoints, want 1", len(endpoints))
+			}
+			if endpoints[0].Method != tt.method || endpoints[0].Path != tt.path {
+				t.Errorf("got %s %s, want %s %s", endpoints[0].Method, endpoints[0].Path, tt.method, tt.path)
+			}
+		})
+	}
+}
+
+// TestPythonImperativeRoutes tests add_url_rule and add_api_route registrations
+func TestPythonImperativeRoutes(t *testing.T) {
+	code := `from flask import Flask
+from fastapi import APIRouter
+
+app = Flask(__name__)
+app.add_url_rule('/users', view_func=list_users)
+app.add_url_rule('/users/<int:id>', 'user', update_user, methods=['PUT', 'PATCH'])
+
+router = APIRouter()
+router.add_api_route(
+    "/orders",
+    create_order,
+    methods=["POST"],
+)
+router.add_api_route(path="/health", endpoint=health)
+`
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"GET", "/users", 5},
+		{"PUT", "/users/{id}", 6},
+		{"PATCH", "/users/{id}", 6},
+		{"POST", "/orders", 9},
+		{"GET", "/health", 14},
+	}
+
+	endpoints := ScanFile("app.py", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path || ep.LineNumber != want[i].line {
+			t.Errorf("endpoint %d = %s %s at line %d, want %s %s at line %d", i, ep.Method, ep.Path, ep.LineNumber, want[i].method, want[i].path, want[i].line)
+		}
+	}
+}
+
+// TestMultiLineDecorators tests decorators whose arguments span several lines
+func TestMultiLineDecorators(t *testing.T) {
+	tests := []struct {
+		file   string
+		code   string
+		method string
+		path   string
+		line   int
+	}{
+		{"users.py", `from fastapi import APIRouter
+
+router = APIRouter()
+
+@router.get(
+    "/users",
+    tags=["u"],
+    response_model=list[User],
+)
+def list_users():
+    pass
+`, "GET", "/users", 5},
+		{"orders.py", `@bp.route(
+    "/orders/<int:id>",  # order detail
+    methods=["PUT"])
+def update_order(id):
+    pass
+`, "PUT", "/orders/{id}", 1},
+		{"users.controller.ts", `@Controller('users')
+export class UsersController {
+  @Get(
+    ':id'
+  )
+  findOne() {}
+}
+`, "GET", "/users/{id}", 3},
+		{"UserController.java", `@RestController
+public class UserController {
+    @GetMapping(
+        value = "/users/{id}",
+        produces = "application/json")
+    public User get() { return null; }
+}
+`, "GET", "/users/{id}", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			endpoints := ScanFile(tt.file, tt.code)
+			if len(endpoints) != 1 {
+				t.Fatalf("ScanFile() found %d endpoints, want 1: %+v", len(endpoints), endpoints)
+			}
+			ep := endpoints[0]
+			if ep.Method != tt.method || ep.Path != tt.path || ep.LineNumber != tt.line {
+				t.Errorf("got %s %s at line %d, want %s %s at line %d", ep.Method, ep.Path, ep.LineNumber, tt.method, tt.path, tt.line)
+			}
+		})
+	}
+}
+
+// TestNestJSControllerPaths tests handler routes joined with the controller path
+func TestNestJSControllerPaths(t *testing.T) {
+	code := `import { All, Controller, Get, Options } from '@nestjs/common';
+
+@Controller('api/cats')
+export class CatsController {
+  @Get()
+  findAll() {}
+
+  @Get('/')
+  index() {}
+
+  @Get(':id')
+  findOne() {}
+
+  @All('proxy/*')
+  proxy() {}
+
+  @Options()
+  preflight() {}
+}
+
+@Controller({ path: '/dogs/', version: '1' })
+export class DogsController {
+  @Get()
+  list() {}
+}
+
+@Controller()
+export class RootController {
+  @All()
+  fallback() {}
+}
+`
+	want := []struct{ method, path string }{
+		{"GET", "/api/cats"},
+		{"GET", "/api/cats"},
+		{"GET", "/api/cats/{id}"},
+		{"ANY", "/api/cats/proxy/*"},
+		{"OPTIONS", "/api/cats"},
+		{"GET", "/dogs"},
+		{"ANY", "/"},
+	}
+
+	endpoints := ScanFile("cats.controller.ts", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path {
+			t.Errorf("endpoint %d = %s %s, want %s %s", i, ep.Method, ep.Path, want[i].method, want[i].path)
+		}
+	}
+}
+
+// TestSpringClassPrefix verifies class-level @RequestMapping paths are
+// composed onto handler routes and the prefix's line is recorded, with
+// "class" in a Javadoc not taken for a declaration
+func TestSpringClassPrefix(t *testing.T) {
+	code := `package com.example.api;
+
+@RestController
+@RequestMapping(path = "/api/v1")
+public class OrderController {
+
+    /**
+     * Lists every order; see the class OrderService for paging
+     */
+    @GetMapping("/orders")
+    public List<Order> list() { return orders; }
+
+    @PostMapping
+    public Order create() { return null; }
+}
+
+@RestController
+public class HealthController {
+    @GetMapping("/health")
+    public String health() { return "ok"; }
+}
+`
+	want := []struct {
+		method, path string
+		prefixLine   int
+	}{
+		{"GET", "/api/v1/orders", 4},
+		{"GET", "/health", 0},
+	}
+
+	endpoints := ScanFile("OrderController.java", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path || ep.PrefixLine != want[i].prefixLine {
+			t.Errorf("endpoint %d = %s %s (prefix line %d), want %s %s (prefix line %d)",
+				i, ep.Method, ep.Path, ep.PrefixLine, want[i].method, want[i].path, want[i].prefixLine)
+		}
+	}
+}
+
+// TestMicronautControllers verifies Micronaut HTTP annotations compose with
+// the @Controller path, and a Spring @Controller bean name isn't a prefix
+func TestMicronautControllers(t *testing.T) {
+	code := `package example.micronaut;
+
+import io.micronaut.http.annotation.*;
+
+@Controller("/books")
+public class BookController {
+
+    @Get
+    public List<Book> list() { return books; }
+
+    @Get("/{isbn}")
+    public Book show(String isbn) { return find(isbn); }
+
+    @Post(uri = "/", consumes = MediaType.APPLICATION_JSON)
+    public HttpResponse<Book> save(@Body Book book) { return HttpResponse.created(book); }
+
+    @Delete("/{isbn}")
+    public void delete(String isbn) { }
+}
+`
+	want := []struct{ method, path string }{
+		{"GET", "/books"},
+		{"GET", "/books/{isbn}"},
+		{"POST", "/books"},
+		{"DELETE", "/books/{isbn}"},
+	}
+
+	endpoints := ScanFile("BookController.java", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path || ep.PrefixLine != 5 {
+			t.Errorf("endpoint %d = %s %s (prefix line %d), want %s %s (prefix line 5)",
+				i, ep.Method, ep.Path, ep.PrefixLine, want[i].method, want[i].path)
+		}
+	}
+
+	spring := `@Controller("pageController")
+public class PageController {
+    @GetMapping("/home")
+    public String home() { return "home"; }
+}
+`
+	if eps := ScanFile("PageController.java", spring); len(eps) != 1 || eps[0].Path != "/home" {
+		t.Errorf("Spring bean-named controller = %+v, want GET /home", eps)
+	}
+}
+
+// TestOpenAPIGeneratedStub verifies routes in an openapi-generator Spring
+// interface are extracted, summarised and marked generated
+func TestOpenAPIGeneratedStub(t *testing.T) {
+	code := `/**
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech) (7.4.0).
+ */
+package org.openapitools.api;
+
+@Generated(value = "org.openapitools.codegen.languages.SpringCodegen", date = "2024-03-01T10:00:00Z")
+@Validated
+@Tag(name = "pet", description = "Everything about your Pets")
+@RequestMapping("${openapi.petstore.base-path:/v2}")
+public interface PetApi {
+
+    /**
+     * POST /pet : Add a new pet to the store
+     */
+    @Operation(
+        operationId = "addPet",
+        summary = "Add a new pet to the store",
+        tags = { "pet" }
+    )
+    @RequestMapping(
+        method = RequestMethod.POST,
+        value = "/pet",
+        produces = { "application/json" },
+        consumes = { "application/json" }
+    )
+    default ResponseEntity<Pet> addPet(@Valid @RequestBody Pet pet) {
+        return new ResponseEntity<>(HttpStatus.NOT_IMPLEMENTED);
+    }
+
+    @ApiOperation(value = "Find pet by ID", nickname = "getPetById", response = Pet.class, tags={ "pet", })
+    @RequestMapping(value = "/pet/{petId}", produces = { "application/json" }, method = RequestMethod.GET)
+    default ResponseEntity<Pet> getPetById(@PathVariable("petId") Long petId) {
+        return new ResponseEntity<>(HttpStatus.NOT_IMPLEMENTED);
+    }
+}
+`
+	want := []struct {
+		method, path, summary string
+	}{
+		{"POST", "/v2/pet", "Add a new pet to the store"},
+		{"GET", "/v2/pet/{petId}", "Find pet by ID"},
+	}
+
+	endpoints := ScanFile("src/main/java/org/openapitools/api/PetApi.java", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path || ep.Summary != want[i].summary {
+			t.Errorf("endpoint %d = %s %s %q, want %s %s %q", i, ep.Method, ep.Path, ep.Summary, want[i].method, want[i].path, want[i].summary)
+		}
+		if !ep.Generated {
+			t.Errorf("%s %s not marked generated", ep.Method, ep.Path)
+		}
+	}
+
+	// Hand-written controllers aren't generated
+	for _, ep := range ScanFile("OrderController.java", javaSpring) {
+		if ep.Generated {
+			t.Errorf("%s %s marked generated", ep.Method, ep.Path)
+		}
+	}
+}
+
 // TestJavaScriptPatternVariations tests flexible JS/TS patterns
 func TestJavaScriptPatternVariations(t *testing.T) {
 	variations := []string{
@@ -414,6 +769,53 @@ func BenchmarkHasAPIIndicators(b *testing.B) {
 	}
 }
 
+// TestKeywordPrefilterMatchesRegex verifies the keyword prefilter never changes Stage 1 results
+func TestKeywordPrefilterMatchesRegex(t *testing.T) {
+	fixtures := []string{
+		pythonFastAPI, pythonFlask, pythonDjango, pythonModel,
+		jsExpress, tsNestJS, jsFastify, jsUtil,
+		goGin, goEcho, goStdLib, goConfig,
+		javaSpring, csharpASPNet,
+		phpLaravelRoutes, protoGateway, goSwaggo, goConstPaths,
+		`@Controller() export class X {}`,
+		`r.Handle("GET", "/x", h)`,
+		`[Route("api")] public class C {}`,
+		"export const appRouter = router ({ getUser: publicProcedure.query(() => null) })",
+		`{"Routes": [{"UpstreamPathTemplate": "/x"}]}`,
+		`{"Paths": ["/x"], "PATH": "/y"}`,
+		"swagger: \"2.0\"\npaths: {}\n",
+	}
+	files := []string{"file.py", "file.js", "file.ts", "file.go", "file.java", "file.cs", "file.php", "file.proto",
+		"ocelot.json", "kong.yml", "routes.yaml", "swagger.json"}
+
+	for _, file := range files {
+		indicators, _ := indicatorsFor(file)
+		for i, content := range fixtures {
+			want := matchesAnyIndicator(content, indicators)
+			if got := hasAPIIndicators(file, content); got != want {
+				t.Errorf("fixture %d as %s: prefiltered = %v, regex-only = %v", i, file, got, want)
+			}
+		}
+	}
+}
+
+// BenchmarkHasAPIIndicatorsNonAPI benchmarks Stage 1 on a large file with no API markers
+func BenchmarkHasAPIIndicatorsNonAPI(b *testing.B) {
+	content := strings.Repeat(goConfig, 200)
+	indicators, _ := indicatorsFor(".go")
+
+	b.Run("Prefiltered", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			hasAPIIndicators("config.go", content)
+		}
+	})
+	b.Run("RegexOnly", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matchesAnyIndicator(content, indicators)
+		}
+	})
+}
+
 // BenchmarkScanFile benchmarks the deep extraction performance
 func BenchmarkScanFile(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -469,10 +871,376 @@ func TestExtractTag(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.filePath, func(t *testing.T) {
-			got := extractTag(tt.filePath)
+			got := extractTag(tt.filePath, "/api/v1/users")
 			if got != tt.want {
 				t.Errorf("extractTag(%s) = %v, want %v", tt.filePath, got, tt.want)
 			}
 		})
 	}
 }
+
+// TestPathTagStrategy verifies path-based tags skip api/version prefixes
+func TestPathTagStrategy(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.TagStrategy = TagStrategyPath
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/users/{id}", "users"},
+		{"/v2/Orders", "orders"},
+		{"/api/{tenant}/invoices", "invoices"},
+		{"users/", "users"},
+		{"/", "routes"}, // nothing meaningful: fall back to the directory
+	}
+	for _, tt := range tests {
+		if got := extractTag("routes/handlers.py", tt.path); got != tt.want {
+			t.Errorf("extractTag(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	endpoints := ScanFile("routes/users.py", pythonFastAPI)
+	for _, ep := range endpoints {
+		if len(ep.Tags) != 1 || ep.Tags[0] != "users" {
+			t.Errorf("%s %s tags = %v, want [users]", ep.Method, ep.Path, ep.Tags)
+		}
+	}
+}
+
+// TestPartialResultsOnExtractionFailure verifies endpoints found before a
+// Stage 2 failure are kept and retrievable
+func TestPartialResultsOnExtractionFailure(t *testing.T) {
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"a_users.py": pythonFastAPI,
+		"b_main.go":  goGin,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(rootDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Fail on the second file, after the first has been extracted
+	origReadFile := readFile
+	defer func() { readFile = origReadFile }()
+	readFile = func(name string) ([]byte, error) {
+		if filepath.Base(name) == "b_main.go" {
+			return nil, errors.New("disk vanished")
+		}
+		return origReadFile(name)
+	}
+
+	scanID := "partial-test"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+	endpoints[scanID] = []Endpoint{}
+	mu.Unlock()
+
+	scanCheckout(scanID, rootDir)
+
+	status, err := GetStatus(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "failed" || !status.Partial || status.Error == "" {
+		t.Errorf("status = %+v, want failed partial scan with error", status)
+	}
+
+	eps, err := GetEndpoints(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 2 {
+		t.Errorf("partial endpoints = %d, want 2", len(eps))
+	}
+}
+
+// TestEmptyScanWarnings verifies a scan finding no endpoints completes with a
+// warning saying whether there was anything to scan
+func TestEmptyScanWarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		warning string
+	}{
+		{"no supported files", map[string]string{"README.md": "# Empty\n", "docs/notes.txt": "todo\n"}, WarningNoSupportedFiles},
+		{"no endpoints", map[string]string{"util.py": "def add(a, b):\n    return a + b\n"}, WarningNoEndpoints},
+		{"endpoints", map[string]string{"main.py": pythonFastAPI}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(rootDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			scanID := "empty-test-" + strings.ReplaceAll(tt.name, " ", "-")
+			mu.Lock()
+			scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+			endpoints[scanID] = []Endpoint{}
+			mu.Unlock()
+
+			scanCheckout(scanID, rootDir)
+
+			status, err := GetStatus(scanID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Status != "completed" || status.Error != "" {
+				t.Errorf("status = %s (error %q), want completed", status.Status, status.Error)
+			}
+			if status.Warning != tt.warning {
+				t.Errorf("Warning = %q, want %q", status.Warning, tt.warning)
+			}
+		})
+	}
+}
+
+// newFixtureRepo creates a local git repository with one commit containing files
+func newFixtureRepo(t *testing.T, files map[string]string) (string, *git.Repository) {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFixtureFiles(t, repo, files)
+	return dir, repo
+}
+
+// commitFixtureFiles writes files into the repository's worktree and commits them
+func commitFixtureFiles(t *testing.T, repo *git.Repository, files map[string]string) string {
+	t.Helper()
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(wt.Filesystem.Root(), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hash, err := wt.Commit("fixture", &git.CommitOptions{
+		Author: &object.Signature{Name: "Fixture", Email: "fixture@example.com", When: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+// TestScanResourceAccounting verifies a scan of a fixture repo reports its clone size
+func TestScanResourceAccounting(t *testing.T) {
+	repoDir, _ := newFixtureRepo(t, map[string]string{
+		"routes/users.py": pythonFastAPI,
+		"main.go":         goGin,
+	})
+
+	scanID := "accounting-test"
+	StartScan(ScanJob{ScanID: scanID, URL: repoDir})
+
+	status, err := GetStatus(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "completed" {
+		t.Fatalf("status = %s (%s), want completed", status.Status, status.Error)
+	}
+	if status.Resources == nil || status.Resources.CloneSizeBytes < int64(len(pythonFastAPI)+len(goGin)) {
+		t.Errorf("Resources = %+v, want clone size of at least the fixture files", status.Resources)
+	}
+}
+
+// TestCloneIntoConfiguredTempDir verifies clones are created under SCANNER_TMPDIR
+func TestCloneIntoConfiguredTempDir(t *testing.T) {
+	repoDir, _ := newFixtureRepo(t, map[string]string{"main.go": goGin})
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.TempDir = t.TempDir()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	dir, err := cloneRepository(repoDir, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if filepath.Dir(dir) != config.TempDir {
+		t.Errorf("clone dir = %s, want it under %s", dir, config.TempDir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+		t.Errorf("cloned checkout missing main.go: %v", err)
+	}
+
+	config.TempDir = filepath.Join(config.TempDir, "missing")
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() accepted a missing temp dir")
+	}
+}
+
+// TestScanSpecificCommit verifies a scan pinned to a SHA sees that commit's tree
+func TestScanSpecificCommit(t *testing.T) {
+	repoDir, repo := newFixtureRepo(t, map[string]string{"routes/users.py": pythonFastAPI})
+	first, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFixtureFiles(t, repo, map[string]string{"app.js": jsFastify})
+
+	scanID := "pinned-commit"
+	StartScan(ScanJob{ScanID: scanID, URL: repoDir, Commit: first.Hash().String()[:10]})
+
+	status, err := GetStatus(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "completed" {
+		t.Fatalf("status = %s (%s), want completed", status.Status, status.Error)
+	}
+	if status.Commit != first.Hash().String() {
+		t.Errorf("Commit = %s, want %s", status.Commit, first.Hash())
+	}
+	eps, _ := GetEndpoints(scanID)
+	for _, ep := range eps {
+		if ep.FilePath == "app.js" {
+			t.Errorf("found %s %s from a later commit", ep.Method, ep.Path)
+		}
+	}
+	if len(eps) != 2 {
+		t.Errorf("found %d endpoints, want the 2 from the first commit", len(eps))
+	}
+
+	missing := "0123456789abcdef0123456789abcdef01234567"
+	StartScan(ScanJob{ScanID: "missing-commit", URL: repoDir, Commit: missing})
+	status, _ = GetStatus("missing-commit")
+	if status.Status != "failed" || !strings.Contains(status.Error, ErrCommitNotFound.Error()) || !strings.Contains(status.Error, missing) {
+		t.Errorf("status = %s (%q), want failure naming the missing commit", status.Status, status.Error)
+	}
+}
+
+// TestIncludeHidden verifies excluded dot-directories are only walked when
+// IncludeHidden is set, and .git never is
+func TestIncludeHidden(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+
+	rootDir := t.TempDir()
+	for _, name := range []string{"app/main.py", ".env/routes.py", ".git/hooks/server.py", ".routes.js"} {
+		path := filepath.Join(rootDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(pythonFastAPI), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		includeHidden bool
+		want          []string
+	}{
+		{false, []string{".routes.js", "app/main.py"}},
+		{true, []string{".env/routes.py", ".routes.js", "app/main.py"}},
+	}
+	for _, tt := range tests {
+		config.IncludeHidden = tt.includeHidden
+		files, err := getCodeFiles(rootDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, f := range files {
+			rel, _ := filepath.Rel(rootDir, f)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("IncludeHidden=%v: getCodeFiles() = %v, want %v", tt.includeHidden, got, tt.want)
+		}
+	}
+}
+
+// TestScanAllBranches verifies an all-branches scan aggregates every branch
+// head, listing the branches of each endpoint
+func TestScanAllBranches(t *testing.T) {
+	repoDir, repo := newFixtureRepo(t, map[string]string{"routes/users.py": pythonFastAPI})
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	commitFixtureFiles(t, repo, map[string]string{"app.js": jsFastify})
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.WithBlame = true
+
+	scanID := "all-branches"
+	StartScan(ScanJob{ScanID: scanID, URL: repoDir, AllBranches: true})
+
+	status, err := GetStatus(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "completed" {
+		t.Fatalf("status = %s (%s), want completed", status.Status, status.Error)
+	}
+	if !reflect.DeepEqual(status.Branches, []string{"master", "feature"}) {
+		t.Errorf("Branches = %v, want [master feature]", status.Branches)
+	}
+
+	eps, _ := GetEndpoints(scanID)
+	if len(eps) == 0 {
+		t.Fatal("no endpoints found")
+	}
+	var shared, featureOnly int
+	for _, ep := range eps {
+		switch ep.FilePath {
+		case "routes/users.py":
+			shared++
+			if !reflect.DeepEqual(ep.Branches, []string{"master", "feature"}) {
+				t.Errorf("%s %s Branches = %v, want both branches", ep.Method, ep.Path, ep.Branches)
+			}
+		case "app.js":
+			featureOnly++
+			if !reflect.DeepEqual(ep.Branches, []string{"feature"}) {
+				t.Errorf("%s %s Branches = %v, want [feature]", ep.Method, ep.Path, ep.Branches)
+			}
+			// Blamed on the feature branch, where app.js exists
+			if ep.LastModified == nil {
+				t.Errorf("%s %s has no blame from its branch", ep.Method, ep.Path)
+			}
+		}
+	}
+	if shared != len(ScanFile("routes/users.py", pythonFastAPI)) || featureOnly != len(ScanFile("app.js", jsFastify)) {
+		t.Errorf("found %d shared and %d feature endpoints, want each route once", shared, featureOnly)
+	}
+}
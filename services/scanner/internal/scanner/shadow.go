@@ -0,0 +1,169 @@
+// Package scanner - Routes shadowed by broader routes declared before them
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// orderedMatchExtensions are languages whose common frameworks match routes
+// in declaration order (Express, Koa, NestJS, FastAPI/Starlette). Flask,
+// Spring, ASP.NET and the Go routers pick the most specific route instead.
+var orderedMatchExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true,
+	".py": true,
+}
+
+// routeReceiverPattern captures the router a route is registered on:
+// router.get(, @app.post(
+var routeReceiverPattern = regexp.MustCompile(`^\s*@?(\w+)\s*\.\s*\w+\s*\(`)
+
+// flagShadowedRoutes warns about routes that can never match because a
+// broader route with the same method, on the same router, is declared
+// earlier in the file: /users/:id before /users/me. It only compares plain
+// paths segment by segment, so optional, wildcard and constrained params
+// never flag anything.
+func flagShadowedRoutes(ext string, found []Endpoint, lines []string) {
+	if !orderedMatchExtensions[ext] {
+		return
+	}
+	for i := range found {
+		later := &found[i]
+		if !shadowCandidate(later) {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			earlier := &found[j]
+			if earlier.LineNumber >= later.LineNumber || !shadowCandidate(earlier) {
+				continue
+			}
+			if !shadowsMethod(earlier.Method, later.Method) || !sameRouter(lines, earlier.LineNumber, later.LineNumber) {
+				continue
+			}
+			if shadowsPath(earlier.Path, later.Path) {
+				later.Warnings = append(later.Warnings, fmt.Sprintf("likely unreachable: shadowed by %s %s declared earlier at %s:%d",
+					strings.ToUpper(earlier.Method), earlier.Path, earlier.FilePath, earlier.LineNumber))
+				break
+			}
+		}
+	}
+}
+
+// flagShadowedAcrossFiles warns about routes that a broader route with the
+// same method, declared in another file of the same directory, can hide.
+// Which file registers first isn't known, so the warning says so. A broad
+// route starting with a parameter isn't compared: routers of different files
+// are usually mounted under different prefixes.
+func flagShadowedAcrossFiles(found []Endpoint) {
+	byDir := make(map[string][]int)
+	for i := range found {
+		ext := extOf(found[i].FilePath)
+		if orderedMatchExtensions[ext] && shadowCandidate(&found[i]) {
+			dir := path.Dir(filepath.ToSlash(found[i].FilePath))
+			byDir[dir] = append(byDir[dir], i)
+		}
+	}
+	for _, group := range byDir {
+		for _, i := range group {
+			specific := &found[i]
+			if shadowWarned(specific) {
+				continue
+			}
+			for _, j := range group {
+				broad := &found[j]
+				if broad.FilePath == specific.FilePath || (extOf(broad.FilePath) == ".py") != (extOf(specific.FilePath) == ".py") {
+					continue
+				}
+				if first, _ := shadowSegment(strings.Split(strings.Trim(broad.Path, "/"), "/")[0]); first {
+					continue
+				}
+				if shadowsMethod(broad.Method, specific.Method) && shadowsPath(broad.Path, specific.Path) {
+					specific.Warnings = append(specific.Warnings, fmt.Sprintf("may be unreachable: shadowed by %s %s at %s:%d if that file registers its routes first",
+						strings.ToUpper(broad.Method), broad.Path, broad.FilePath, broad.LineNumber))
+					break
+				}
+			}
+		}
+	}
+}
+
+// shadowWarned reports whether an endpoint already carries a shadowing warning
+func shadowWarned(ep *Endpoint) bool {
+	for _, w := range ep.Warnings {
+		if strings.Contains(w, "shadowed by") {
+			return true
+		}
+	}
+	return false
+}
+
+// shadowCandidate reports whether an endpoint's path is literal enough to compare
+func shadowCandidate(ep *Endpoint) bool {
+	return ep.Kind == "" && !ep.Dynamic && ep.RawPath == "" && ep.Path != ""
+}
+
+// shadowsMethod reports whether a route with method a receives b's requests
+func shadowsMethod(a, b string) bool {
+	return sameMethod(a, b) || sameMethod(a, "ANY") || sameMethod(a, "ALL")
+}
+
+// sameRouter reports whether two route lines register on the same router.
+// Lines whose receiver can't be told apart (decorators) count as the same.
+func sameRouter(lines []string, a, b int) bool {
+	receiver := func(line int) string {
+		if line < 1 || line > len(lines) {
+			return ""
+		}
+		if m := routeReceiverPattern.FindStringSubmatch(lines[line-1]); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+	ra, rb := receiver(a), receiver(b)
+	return ra == "" || rb == "" || ra == rb
+}
+
+// shadowsPath reports whether every request matching specific also matches
+// broad, which replaces at least one of specific's literal segments with a
+// parameter
+func shadowsPath(broad, specific string) bool {
+	bs := strings.Split(strings.Trim(broad, "/"), "/")
+	ss := strings.Split(strings.Trim(specific, "/"), "/")
+	if len(bs) != len(ss) {
+		return false
+	}
+	broader := false
+	for i := range bs {
+		bParam, bOK := shadowSegment(bs[i])
+		sParam, sOK := shadowSegment(ss[i])
+		if !bOK || !sOK {
+			return false
+		}
+		switch {
+		case bParam && !sParam:
+			broader = true
+		case bParam != sParam, !bParam && bs[i] != ss[i]:
+			return false
+		}
+	}
+	return broader
+}
+
+// shadowSegment classifies a path segment as a parameter (:id, {id}) or a
+// literal; ok is false for segments matching unpredictably: optional,
+// wildcard, regex-constrained or typed params
+func shadowSegment(seg string) (param, ok bool) {
+	if strings.ContainsAny(seg, "*?()<>[]") {
+		return false, false
+	}
+	if name, found := strings.CutPrefix(seg, ":"); found {
+		return true, name != "" && !strings.ContainsAny(name, ":{}")
+	}
+	if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+		return true, !strings.ContainsAny(seg[1:len(seg)-1], ":{}")
+	}
+	return false, !strings.ContainsAny(seg, ":{}")
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestShadowedRoutes verifies broad routes declared first flag the specific
+// routes they hide, and nothing else
+func TestShadowedRoutes(t *testing.T) {
+	express := `const router = express.Router()
+const admin = express.Router()
+
+router.get('/users/:id', getUser)
+router.get('/users/me', getMe)
+router.post('/users/me', updateMe)
+router.get('/users/:id/posts', getPosts)
+router.get('/users/:id(\\d+)/avatar', getAvatar)
+router.get('/users/me/avatar', getMyAvatar)
+admin.get('/users/export', exportUsers)
+router.get('/teams/new', newTeam)
+router.get('/teams/:id', getTeam)
+`
+	endpoints := ScanFile("routes.js", express)
+
+	shadowed := map[string]string{}
+	for _, ep := range endpoints {
+		for _, w := range ep.Warnings {
+			if strings.Contains(w, "shadowed by") {
+				shadowed[ep.Method+" "+ep.Path] = w
+			}
+		}
+	}
+
+	want := "likely unreachable: shadowed by GET /users/:id declared earlier at routes.js:4"
+	if got := shadowed["GET /users/me"]; got != want {
+		t.Errorf("GET /users/me warning = %q, want %q", got, want)
+	}
+	// Different method, constrained param, other router, specific-first order
+	if len(shadowed) != 1 {
+		t.Errorf("shadowed routes = %v, want only GET /users/me", shadowed)
+	}
+
+	// Flask picks the most specific rule, so order doesn't matter there;
+	// FastAPI matches in order
+	fastapi := "@app.get(\"/items/{item_id}\")\ndef read_item(item_id: int): ...\n\n@app.get(\"/items/latest\")\ndef latest(): ...\n"
+	eps := ScanFile("main.py", fastapi)
+	if len(eps) != 2 || len(eps[1].Warnings) == 0 {
+		t.Errorf("FastAPI /items/latest not flagged: %+v", eps)
+	}
+	spring := "@RestController\npublic class A {\n    @GetMapping(\"/users/{id}\")\n    public User get() { return null; }\n    @GetMapping(\"/users/me\")\n    public User me() { return null; }\n}\n"
+	for _, ep := range ScanFile("A.java", spring) {
+		if len(ep.Warnings) != 0 {
+			t.Errorf("Spring route %s flagged: %v", ep.Path, ep.Warnings)
+		}
+	}
+}
+
+// TestShadowedAcrossFiles verifies broad routes in another file of the same
+// directory flag the routes they may hide
+func TestShadowedAcrossFiles(t *testing.T) {
+	found := []Endpoint{
+		{Method: "GET", Path: "/users/:id", FilePath: "routes/users.js", LineNumber: 3},
+		{Method: "GET", Path: "/users/me", FilePath: "routes/me.js", LineNumber: 5},
+		{Method: "POST", Path: "/users/me", FilePath: "routes/me.js", LineNumber: 6},
+		{Method: "GET", Path: "/users/export", FilePath: "admin/export.js", LineNumber: 2},
+		{Method: "GET", Path: "/:id", FilePath: "routes/teams.js", LineNumber: 1},
+		{Method: "GET", Path: "/recent", FilePath: "routes/orders.js", LineNumber: 1},
+		{Method: "GET", Path: "/items/{id}", FilePath: "api/items.py", LineNumber: 1},
+		{Method: "GET", Path: "/items/latest", FilePath: "api/Latest.java", LineNumber: 1},
+	}
+	flagShadowedAcrossFiles(found)
+
+	want := "may be unreachable: shadowed by GET /users/:id at routes/users.js:3 if that file registers its routes first"
+	if len(found[1].Warnings) != 1 || found[1].Warnings[0] != want {
+		t.Errorf("GET /users/me warnings = %v, want %q", found[1].Warnings, want)
+	}
+	// Other method, other directory, parameter-first mount, other framework
+	for _, i := range []int{0, 2, 3, 5, 7} {
+		if len(found[i].Warnings) != 0 {
+			t.Errorf("%s %s flagged: %v", found[i].Method, found[i].Path, found[i].Warnings)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package scanner - Signing scan results for integrity verification
+package scanner
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// SignedResult is what a scan's signature covers: the scan's identity and
+// outcome, and its endpoints as stored. Its JSON encoding is the signed
+// message.
+type SignedResult struct {
+	ScanID    string     `json:"scan_id"`
+	Status    string     `json:"status"`
+	Commit    string     `json:"commit,omitempty"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// SignResult returns the sha256=<hex> HMAC-SHA256 of a result under key
+func SignResult(result SignedResult, key string) (string, error) {
+	if result.Endpoints == nil {
+		result.Endpoints = []Endpoint{}
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyResult reports whether signature is the signature of result under
+// key, comparing in constant time
+func VerifyResult(result SignedResult, key, signature string) bool {
+	expected, err := SignResult(result, key)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// signScan signs a completed scan's result with RESULT_SIGNING_KEY, or
+// returns "" when signing is off or fails
+func signScan(scanID, commit string, eps []Endpoint) string {
+	if config.ResultSigningKey == "" {
+		return ""
+	}
+	signature, err := SignResult(SignedResult{ScanID: scanID, Status: "completed", Commit: commit, Endpoints: eps}, config.ResultSigningKey)
+	if err != nil {
+		return ""
+	}
+	return signature
+}
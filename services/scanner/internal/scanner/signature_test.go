@@ -0,0 +1,66 @@
+package scanner
+
+import "testing"
+
+// TestSignResult verifies a signature verifies only the result it was made
+// for, under the key it was made with
+func TestSignResult(t *testing.T) {
+	result := SignedResult{
+		ScanID: "signed-scan",
+		Status: "completed",
+		Commit: "0123456789abcdef0123456789abcdef01234567",
+		Endpoints: []Endpoint{
+			{ID: "users-py-GET-5", Method: "GET", Path: "/users", FilePath: "users.py", LineNumber: 5},
+			{ID: "users-py-POST-9", Method: "POST", Path: "/users", FilePath: "users.py", LineNumber: 9},
+		},
+	}
+	signature, err := SignResult(result, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyResult(result, "secret", signature) {
+		t.Fatal("signature does not verify its own result")
+	}
+	if VerifyResult(result, "other-secret", signature) {
+		t.Error("signature verified under another key")
+	}
+
+	altered := result
+	altered.Endpoints = append([]Endpoint(nil), result.Endpoints...)
+	altered.Endpoints[1].Path = "/admin/users"
+	if VerifyResult(altered, "secret", signature) {
+		t.Error("signature verified an altered endpoint")
+	}
+	if again, _ := SignResult(altered, "secret"); again == signature {
+		t.Error("altering an endpoint did not change the signature")
+	}
+
+	altered = result
+	altered.Commit = "fedcba9876543210fedcba9876543210fedcba98"
+	if VerifyResult(altered, "secret", signature) {
+		t.Error("signature verified a result with another commit")
+	}
+}
+
+// TestScanStoresSignature verifies completed scans are signed only when
+// RESULT_SIGNING_KEY is set
+func TestScanStoresSignature(t *testing.T) {
+	repoDir, _ := newFixtureRepo(t, map[string]string{"routes/users.py": pythonFastAPI})
+
+	StartScan(ScanJob{ScanID: "unsigned-scan", URL: repoDir})
+	if status, _ := GetStatus("unsigned-scan"); status.Signature != "" {
+		t.Errorf("Signature = %q without a signing key", status.Signature)
+	}
+
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.ResultSigningKey = "secret"
+
+	StartScan(ScanJob{ScanID: "signed-scan", URL: repoDir})
+	status, _ := GetStatus("signed-scan")
+	eps, _ := GetEndpoints("signed-scan")
+	result := SignedResult{ScanID: status.ID, Status: status.Status, Commit: status.Commit, Endpoints: eps}
+	if status.Signature == "" || !VerifyResult(result, "secret", status.Signature) {
+		t.Errorf("Signature = %q does not verify the stored result", status.Signature)
+	}
+}
@@ -0,0 +1,166 @@
+// Package scanner - Flask blueprint prefixes and flask-smorest method views
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// bp = Blueprint("pets", __name__, url_prefix="/pets"), flask_smorest.Blueprint(...)
+	blueprintPattern       = regexp.MustCompile(`^\s*(\w+)\s*=\s*(?:\w+\.)?Blueprint\s*\(`)
+	blueprintPrefixPattern = regexp.MustCompile(`\burl_prefix\s*=\s*["']([^"']*)["']`)
+	// @bp.route(...): the receiver a route decorator is declared on
+	decoratorReceiverPattern = regexp.MustCompile(`^\s*@(\w+)\.`)
+
+	pythonClassPattern      = regexp.MustCompile(`^(\s*)class\s+\w+`)
+	methodViewMethodPattern = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+(get|post|put|patch|delete|head|options)\s*\(`)
+
+	// flask-smorest: @blp.arguments(PetSchema, location="query"),
+	// @blp.response(200, PetSchema(many=True)), @blp.alt_response(404, ...),
+	// @blp.doc(summary="List pets")
+	smorestArgumentsPattern = regexp.MustCompile(`^@\w+\.arguments\s*\(\s*([\w.]+)`)
+	smorestResponsePattern  = regexp.MustCompile(`^@\w+\.(?:alt_)?response\s*\(\s*(?:status_code\s*=\s*)?(\d{3})\s*(?:,\s*(?:schema\s*=\s*)?([\w.]+)(\s*\(\s*many\s*=\s*True)?)?`)
+	smorestSummaryPattern   = regexp.MustCompile(`^@\w+\.doc\s*\(.*?\bsummary\s*=\s*["']([^"']+)["']`)
+)
+
+// refinePythonRoutes expands routes decorating a MethodView class into one
+// endpoint per HTTP method the class defines, composes blueprint url_prefix
+// and captures flask-smorest schemas and summaries
+func refinePythonRoutes(filePath string, found []Endpoint, lines []string) []Endpoint {
+	prefixes := blueprintPrefixes(lines)
+
+	var refined []Endpoint
+	for _, ep := range found {
+		routeIdx := ep.LineNumber - 1
+		receiver := ""
+		if m := decoratorReceiverPattern.FindStringSubmatch(lines[routeIdx]); m != nil {
+			receiver = m[1]
+		}
+
+		expanded := []Endpoint{ep}
+		if target := decoratedLine(lines, routeIdx); target >= 0 && pythonClassPattern.MatchString(lines[target]) {
+			expanded = methodViewEndpoints(filePath, ep, lines, target)
+		}
+
+		for _, e := range expanded {
+			if prefix, ok := prefixes[receiver]; ok {
+				e.Path = joinPathSegments(prefix.path, e.Path)
+				e.PrefixLine = prefix.line
+				e.Tags = []string{extractTag(filePath, e.Path)}
+			}
+			applySmorestDecorators(&e, lines)
+			refined = append(refined, e)
+		}
+	}
+	return refined
+}
+
+// blueprintPrefix is a blueprint's url_prefix and the 1-based line declaring it
+type blueprintPrefix struct {
+	path string
+	line int
+}
+
+// blueprintPrefixes maps blueprint variables to their url_prefix
+func blueprintPrefixes(lines []string) map[string]blueprintPrefix {
+	prefixes := map[string]blueprintPrefix{}
+	for i, line := range lines {
+		m := blueprintPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if p := blueprintPrefixPattern.FindStringSubmatch(balancedLine(lines, i)); p != nil && strings.Trim(p[1], "/") != "" {
+			prefixes[m[1]] = blueprintPrefix{path: p[1], line: i + 1}
+		}
+	}
+	return prefixes
+}
+
+// decoratedLine returns the index of the definition the decorator at
+// lines[i] applies to, skipping the decorators stacked below it, or -1
+func decoratedLine(lines []string, i int) int {
+	for j := i; j < len(lines); j++ {
+		code := strings.TrimSpace(lines[j])
+		switch {
+		case code == "" || strings.HasPrefix(code, "#"):
+		case strings.HasPrefix(code, "@"):
+			for depth := parenDepth(lines[j]); depth > 0 && j+1 < len(lines); {
+				j++
+				depth += parenDepth(lines[j])
+			}
+		default:
+			return j
+		}
+	}
+	return -1
+}
+
+// methodViewEndpoints returns one endpoint per HTTP method defined in the
+// class at lines[classIdx], each declared on its method's def line. A class
+// defining none yields no endpoints.
+func methodViewEndpoints(filePath string, route Endpoint, lines []string, classIdx int) []Endpoint {
+	classIndent := len(pythonClassPattern.FindStringSubmatch(lines[classIdx])[1])
+
+	var found []Endpoint
+	for j := classIdx + 1; j < len(lines); j++ {
+		code := strings.TrimSpace(lines[j])
+		if code == "" || strings.HasPrefix(code, "#") {
+			continue
+		}
+		if len(lines[j])-len(strings.TrimLeft(lines[j], " \t")) <= classIndent {
+			break // end of the class body
+		}
+		m := methodViewMethodPattern.FindStringSubmatch(lines[j])
+		if m == nil {
+			continue
+		}
+		method := strings.ToUpper(m[2])
+		ep := route
+		ep.ID = fmt.Sprintf("%s-%s-%d", scanID(filePath), method, j+1)
+		ep.Method = method
+		ep.LineNumber = j + 1
+		found = append(found, ep)
+	}
+	return found
+}
+
+// applySmorestDecorators captures the flask-smorest arguments schema,
+// response schemas and status codes, and doc summary from the decorators
+// stacked around an endpoint's declaration
+func applySmorestDecorators(ep *Endpoint, lines []string) {
+	for _, decorator := range decoratorBlock(lines, ep.LineNumber-1) {
+		if m := smorestArgumentsPattern.FindStringSubmatch(decorator); m != nil && ep.InputModel == "" {
+			ep.ValidatedInput = true
+			ep.InputModel = m[1]
+		}
+		if m := smorestResponsePattern.FindStringSubmatch(decorator); m != nil {
+			code, _ := strconv.Atoi(m[1])
+			if !containsInt(ep.ResponseCodes, code) {
+				ep.ResponseCodes = append(ep.ResponseCodes, code)
+			}
+			// The success response's schema is the response model
+			if m[2] != "" && ep.ResponseType == "" && code < 300 {
+				ep.ResponseType = m[2]
+				if m[3] != "" {
+					ep.ResponseType = "List[" + m[2] + "]"
+				}
+			}
+		}
+		if m := smorestSummaryPattern.FindStringSubmatch(decorator); m != nil && ep.Summary == "" {
+			ep.Summary = m[1]
+		}
+	}
+}
+
+// containsInt reports whether list contains n
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFlaskSmorestRoutes verifies method views expand per method under the
+// blueprint prefix and carry their smorest schemas and summaries
+func TestFlaskSmorestRoutes(t *testing.T) {
+	code := `from flask.views import MethodView
+from flask_smorest import Blueprint, abort
+
+from .schemas import PetSchema, PetQueryArgsSchema
+
+blp = Blueprint("pets", __name__, url_prefix="/pets", description="Operations on pets")
+
+
+@blp.route("/")
+class Pets(MethodView):
+    @blp.arguments(PetQueryArgsSchema, location="query")
+    @blp.response(200, PetSchema(many=True))
+    def get(self, args):
+        """List pets"""
+        return Pet.get(filters=args)
+
+    @blp.arguments(PetSchema)
+    @blp.response(
+        201,
+        PetSchema,
+    )
+    def post(self, new_data):
+        """Add a new pet"""
+        return Pet.create(**new_data)
+
+
+@blp.route("/<pet_id>", methods=["DELETE"])
+@blp.doc(summary="Delete a pet")
+@blp.response(204)
+@blp.alt_response(404, description="Pet not found")
+def delete_pet(pet_id):
+    Pet.delete(pet_id)
+`
+	want := []struct {
+		method, path, input, response, summary string
+		codes                                  []int
+		line                                   int
+	}{
+		{"GET", "/pets", "PetQueryArgsSchema", "List[PetSchema]", "List pets", []int{200}, 13},
+		{"POST", "/pets", "PetSchema", "PetSchema", "Add a new pet", []int{201}, 22},
+		{"DELETE", "/pets/{pet_id}", "", "", "Delete a pet", []int{204, 404}, 27},
+	}
+
+	endpoints := ScanFile("app/resources/pets.py", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("Expected %d endpoints, got %d: %+v", len(want), len(endpoints), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.LineNumber != w.line {
+			t.Errorf("Endpoint %d: got %s %s at line %d, want %s %s at line %d", i, ep.Method, ep.Path, ep.LineNumber, w.method, w.path, w.line)
+		}
+		if ep.InputModel != w.input || ep.ValidatedInput != (w.input != "") {
+			t.Errorf("%s %s: input model %q (validated %v), want %q", ep.Method, ep.Path, ep.InputModel, ep.ValidatedInput, w.input)
+		}
+		if ep.ResponseType != w.response {
+			t.Errorf("%s %s: response type %q, want %q", ep.Method, ep.Path, ep.ResponseType, w.response)
+		}
+		if ep.Summary != w.summary {
+			t.Errorf("%s %s: summary %q, want %q", ep.Method, ep.Path, ep.Summary, w.summary)
+		}
+		if !reflect.DeepEqual(ep.ResponseCodes, w.codes) {
+			t.Errorf("%s %s: response codes %v, want %v", ep.Method, ep.Path, ep.ResponseCodes, w.codes)
+		}
+		if ep.PrefixLine != 6 {
+			t.Errorf("%s %s: prefix line %d, want 6", ep.Method, ep.Path, ep.PrefixLine)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package scanner - Handler source hashes for telling moved from modified
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// pythonDefPattern matches a function or class definition line
+var pythonDefPattern = regexp.MustCompile(`^\s*(?:async\s+def|def|class)\s`)
+
+// applySourceHashes sets each endpoint's SourceHash from the source of its
+// handler: the function body when the route declares one, otherwise the
+// route's own declaration
+func applySourceHashes(ext string, found []Endpoint, lines []string) {
+	for i := range found {
+		idx := found[i].LineNumber - 1
+		if idx < 0 || idx >= len(lines) {
+			continue
+		}
+		start, end := handlerSpan(ext, lines, idx)
+		found[i].SourceHash = sourceHash(lines[start : end+1])
+	}
+}
+
+// sourceHash hashes lines ignoring indentation and trailing whitespace, so
+// re-indenting or moving a handler keeps its hash
+func sourceHash(lines []string) string {
+	h := sha256.New()
+	for _, line := range lines {
+		h.Write([]byte(strings.TrimSpace(line)))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// handlerSpan returns the first and last line indexes of the handler for the
+// route declared at lines[idx]
+func handlerSpan(ext string, lines []string, idx int) (int, int) {
+	switch {
+	case ext == ".py":
+		return pythonHandlerSpan(lines, idx)
+	case braceLanguages[ext]:
+		return braceHandlerSpan(lines, idx)
+	}
+	return idx, idx
+}
+
+// braceLanguages delimit function bodies with braces
+var braceLanguages = map[string]bool{
+	".go": true, ".java": true, ".kt": true, ".cs": true, ".php": true,
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true,
+}
+
+// pythonHandlerSpan spans a decorated function through the end of its
+// indented body, or a route statement such as path(...) on its own
+func pythonHandlerSpan(lines []string, idx int) (int, int) {
+	def := decoratedLine(lines, idx)
+	if def < 0 || !pythonDefPattern.MatchString(lines[def]) {
+		return idx, statementEnd(lines, idx)
+	}
+
+	// The signature may continue over several lines
+	bodyStart := statementEnd(lines, def) + 1
+	indent := indentation(lines[def])
+	end := bodyStart - 1
+	for j := bodyStart; j < len(lines); j++ {
+		code := strings.TrimSpace(lines[j])
+		if code == "" {
+			continue
+		}
+		if indentation(lines[j]) <= indent {
+			break
+		}
+		end = j
+	}
+	return idx, end
+}
+
+// statementEnd returns the index of the line closing the parentheses opened
+// on lines[i]
+func statementEnd(lines []string, i int) int {
+	depth := parenDepth(lines[i])
+	for depth > 0 && i+1 < len(lines) {
+		i++
+		depth += parenDepth(lines[i])
+	}
+	return i
+}
+
+// braceHandlerSpan spans a route through the closing brace of the function
+// body it declares, e.g. router.get("/x", (req, res) => { ... }) or an
+// annotated method, or just the route statement when it names a handler
+// defined elsewhere: r.GET("/x", listUsers)
+func braceHandlerSpan(lines []string, idx int) (int, int) {
+	parens, braces := 0, 0
+	opened, annotation := false, false
+	for j := idx; j < len(lines); j++ {
+		line := lines[j]
+		code := strings.TrimSpace(line)
+		// Annotations and attributes stacked above the method
+		if !opened && parens == 0 && (strings.HasPrefix(code, "@") || strings.HasPrefix(code, "[") || strings.HasPrefix(code, "#[")) {
+			parens = max(parenDepth(line), 0)
+			annotation = parens > 0
+			continue
+		}
+		if annotation {
+			// An annotation's arguments continue until its parentheses close
+			parens += parenDepth(line)
+			annotation = parens > 0
+			continue
+		}
+
+		var quote byte
+	scan:
+		for k := 0; k < len(line); k++ {
+			c := line[k]
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					k++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'' || c == '`':
+				quote = c
+			case c == '/' && k+1 < len(line) && line[k+1] == '/':
+				break scan
+			case c == '(':
+				parens++
+			case c == ')':
+				parens--
+			case c == '{':
+				braces++
+				opened = true
+			case c == '}':
+				braces--
+				if opened && braces == 0 {
+					return idx, j
+				}
+			case c == ';' && parens <= 0 && braces == 0:
+				return idx, j
+			}
+		}
+
+		if !opened && parens <= 0 && !nextLineOpensBody(lines, j) {
+			return idx, j
+		}
+	}
+	return idx, len(lines) - 1
+}
+
+// nextLineOpensBody reports whether the next non-blank line after lines[j]
+// starts a body or continues a call chain, as in Allman-style braces
+func nextLineOpensBody(lines []string, j int) bool {
+	for k := j + 1; k < len(lines); k++ {
+		code := strings.TrimSpace(lines[k])
+		if code == "" {
+			continue
+		}
+		return strings.HasPrefix(code, "{") || strings.HasPrefix(code, ".")
+	}
+	return false
+}
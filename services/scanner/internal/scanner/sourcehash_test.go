@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSourceHash verifies a handler's hash follows its body: it changes when
+// the body does, but not when the handler only moves
+func TestSourceHash(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.SourceHashes = true
+
+	tests := []struct {
+		name, filePath, code string
+		// edit changes the first handler's body; shift only adds lines above it
+		edit, shift func(string) string
+	}{
+		{
+			name:     "Express",
+			filePath: "routes.js",
+			code: `const router = express.Router();
+
+router.get('/users/:id', async (req, res) => {
+  const user = await User.findById(req.params.id);
+  res.json(user);
+});
+
+router.get('/health', health);
+`,
+			edit: func(s string) string { return strings.Replace(s, "res.json(user)", "res.json(user.public())", 1) },
+			shift: func(s string) string {
+				return strings.Replace(s, "const router = express.Router();\n", "const router = express.Router();\nrouter.use(auth);\n\n", 1)
+			},
+		},
+		{
+			name:     "FastAPI",
+			filePath: "main.py",
+			code: `app = FastAPI()
+
+@app.get("/users/{user_id}")
+def get_user(user_id: int):
+    user = db.get(user_id)
+    return user
+
+@app.get("/health")
+def health():
+    return {"ok": True}
+`,
+			edit: func(s string) string { return strings.Replace(s, "    return user\n", "    return user.public()\n", 1) },
+			shift: func(s string) string {
+				return strings.Replace(s, "app = FastAPI()\n", "import logging\n\napp = FastAPI()\n", 1)
+			},
+		},
+		{
+			name:     "Spring",
+			filePath: "UserController.java",
+			code: `@RestController
+public class UserController {
+    @GetMapping(
+        value = "/users/{id}",
+        produces = { "application/json" }
+    )
+    public User get(@PathVariable Long id) {
+        return repo.findById(id);
+    }
+
+    @GetMapping("/health")
+    public String health() { return "ok"; }
+}
+`,
+			edit: func(s string) string {
+				return strings.Replace(s, "repo.findById(id)", "repo.findById(id).orElseThrow()", 1)
+			},
+			shift: func(s string) string {
+				return strings.Replace(s, "public class UserController {\n", "public class UserController {\n    private final UserRepository repo;\n\n", 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashes := func(code string) []string {
+				var out []string
+				for _, ep := range ScanFile(tt.filePath, code) {
+					if ep.SourceHash == "" {
+						t.Fatalf("%s %s has no source hash", ep.Method, ep.Path)
+					}
+					out = append(out, ep.SourceHash)
+				}
+				if len(out) != 2 {
+					t.Fatalf("ScanFile() found %d endpoints, want 2", len(out))
+				}
+				return out
+			}
+			base := hashes(tt.code)
+
+			if shifted := hashes(tt.shift(tt.code)); shifted[0] != base[0] || shifted[1] != base[1] {
+				t.Errorf("hashes changed when handlers only moved: %v -> %v", base, shifted)
+			}
+			edited := hashes(tt.edit(tt.code))
+			if edited[0] == base[0] {
+				t.Error("hash unchanged after editing the handler body")
+			}
+			if edited[1] != base[1] {
+				t.Error("editing one handler changed another's hash")
+			}
+		})
+	}
+
+	config.SourceHashes = false
+	if ep := ScanFile("main.py", tests[1].code)[0]; ep.SourceHash != "" {
+		t.Errorf("SourceHash = %q with SOURCE_HASHES off", ep.SourceHash)
+	}
+}
@@ -0,0 +1,112 @@
+// Package scanner - $ref resolution across OpenAPI documents split into files
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// specResolver follows $refs from an OpenAPI document into itself and into
+// the repository files it references, parsing each file once
+type specResolver struct {
+	load FileLoader
+	docs map[string]any // parsed documents by path relative to the repo root
+}
+
+// newSpecResolver returns a resolver for the parsed document at filePath
+func newSpecResolver(filePath string, doc map[string]any, load FileLoader) *specResolver {
+	return &specResolver{load: load, docs: map[string]any{filepath.ToSlash(filePath): doc}}
+}
+
+// deref follows v while it is a {$ref: ...} object, returning the value
+// finally referenced and the document holding it. Refs inside v are relative
+// to the document at base. A chain of refs leading back to itself fails.
+func (r *specResolver) deref(v any, base string) (any, string, error) {
+	base = filepath.ToSlash(base)
+	seen := make(map[string]bool)
+	for {
+		obj, _ := v.(map[string]any)
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return v, base, nil
+		}
+		target, pointer, err := r.locate(ref, base)
+		if err != nil {
+			return nil, "", err
+		}
+		if seen[target+"#"+pointer] {
+			return nil, "", fmt.Errorf("$ref cycle through %s", ref)
+		}
+		seen[target+"#"+pointer] = true
+
+		if v, err = r.resolve(target, pointer); err != nil {
+			return nil, "", fmt.Errorf("$ref %s: %w", ref, err)
+		}
+		base = target
+	}
+}
+
+// locate splits a $ref into the repository path of its document and its
+// JSON pointer. Only relative references to files within the repository
+// are followed.
+func (r *specResolver) locate(ref, base string) (string, string, error) {
+	file, pointer, _ := strings.Cut(ref, "#")
+	if file == "" {
+		return base, pointer, nil
+	}
+	if strings.Contains(file, "://") {
+		return "", "", fmt.Errorf("$ref %s: only relative file references are followed", ref)
+	}
+	target := path.Join(path.Dir(base), file)
+	if strings.HasPrefix(file, "/") || !filepath.IsLocal(target) {
+		return "", "", fmt.Errorf("$ref %s: outside the repository", ref)
+	}
+	return target, pointer, nil
+}
+
+// resolve returns the value at pointer in the document at target, loading
+// the document if it hasn't been yet
+func (r *specResolver) resolve(target, pointer string) (any, error) {
+	doc, ok := r.docs[target]
+	if !ok {
+		if r.load == nil {
+			return nil, fmt.Errorf("can't read %s outside a repository scan", target)
+		}
+		data, err := r.load(target)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid document %s: %w", target, err)
+		}
+		r.docs[target] = doc
+	}
+
+	v := doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := v.(type) {
+		case map[string]any:
+			if v, ok = node[token]; !ok {
+				return nil, fmt.Errorf("%s has no %s", target, pointer)
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("%s has no %s", target, pointer)
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("%s has no %s", target, pointer)
+		}
+	}
+	return v, nil
+}
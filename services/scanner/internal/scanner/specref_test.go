@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAPIGatewaySplitSpec verifies path items $ref-ed into other files of
+// the repository are resolved, nested refs relative to their own file, and
+// ref cycles skip only the paths caught in them
+func TestAPIGatewaySplitSpec(t *testing.T) {
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"api/openapi.yaml": `openapi: "3.0.1"
+paths:
+  /health:
+    get:
+      x-amazon-apigateway-integration:
+        type: mock
+  /users:
+    $ref: './paths/users.yaml'
+  /users/{id}:
+    $ref: 'paths/index.yaml#/~1users~1{id}'
+  /loop:
+    $ref: './paths/loop-a.yaml'
+  /escape:
+    $ref: '../../etc/passwd'
+`,
+		"api/paths/users.yaml": `get:
+  summary: List users
+  x-amazon-apigateway-integration:
+    type: http_proxy
+    uri: https://users.internal.example.com/users
+post:
+  x-amazon-apigateway-integration:
+    type: http_proxy
+    uri: https://users.internal.example.com/users
+`,
+		"api/paths/index.yaml": `/users/{id}:
+  $ref: './items/user.yaml'
+`,
+		"api/paths/items/user.yaml": `delete:
+  x-amazon-apigateway-integration:
+    type: http_proxy
+    uri: https://users.internal.example.com/users/{id}
+`,
+		"api/paths/loop-a.yaml": `$ref: './loop-b.yaml'
+`,
+		"api/paths/loop-b.yaml": `$ref: './loop-a.yaml'
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(rootDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanID := "split-spec"
+	mu.Lock()
+	scans[scanID] = &ScanStatus{ID: scanID, Status: "scanning", StartedAt: time.Now()}
+	endpoints[scanID] = []Endpoint{}
+	mu.Unlock()
+	scanCheckout(scanID, rootDir)
+
+	eps, _ := GetEndpoints(scanID)
+	byRoute := make(map[string]Endpoint)
+	for _, ep := range eps {
+		byRoute[ep.Method+" "+ep.Path] = ep
+	}
+	if len(byRoute) != 4 {
+		t.Fatalf("found %d routes, want 4: %v", len(byRoute), byRoute)
+	}
+
+	list, ok := byRoute["GET /users"]
+	if !ok || list.Summary != "List users" || list.Upstream != "https://users.internal.example.com/users" {
+		t.Errorf("GET /users = %+v, want the referenced operation", list)
+	}
+	// Referenced operations are reported at their path's $ref
+	if list.FilePath != "api/openapi.yaml" || list.LineNumber != 7 {
+		t.Errorf("GET /users at %s:%d, want api/openapi.yaml:7", list.FilePath, list.LineNumber)
+	}
+	if _, ok := byRoute["POST /users"]; !ok {
+		t.Error("POST /users not resolved")
+	}
+	if del, ok := byRoute["DELETE /users/{id}"]; !ok || del.Upstream != "https://users.internal.example.com/users/{id}" {
+		t.Errorf("DELETE /users/{id} = %+v, want the nested ref resolved", del)
+	}
+	if _, ok := byRoute["GET /health"]; !ok {
+		t.Error("inline GET /health missing")
+	}
+}
+
+// TestSpecRefWithoutRepository verifies external refs of a file scanned on
+// its own are skipped while in-document refs still resolve
+func TestSpecRefWithoutRepository(t *testing.T) {
+	content := `openapi: "3.0.1"
+paths:
+  /orders:
+    $ref: '#/x-paths/orders'
+  /users:
+    $ref: './paths/users.yaml'
+x-paths:
+  orders:
+    get:
+      x-amazon-apigateway-integration:
+        type: mock
+`
+	eps := ScanFile("openapi.yaml", content)
+	if len(eps) != 1 || eps[0].Method != "GET" || eps[0].Path != "/orders" {
+		t.Errorf("ScanFile() = %+v, want only GET /orders", eps)
+	}
+}
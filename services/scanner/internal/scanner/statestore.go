@@ -0,0 +1,155 @@
+// Package scanner - Scan state persisted across restarts
+package scanner
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrorInterrupted is the error of a scan cut short by a restart
+const ErrorInterrupted = "Scan was interrupted by a restart"
+
+// scanRecord is a scan as saved under STATE_DIR, one file per scan
+type scanRecord struct {
+	Status    ScanStatus `json:"status"`
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+	Checkout  string     `json:"checkout,omitempty"` // checkout being scanned, to resume from
+
+	CallbackURL string `json:"callback_url,omitempty"` // not part of the status JSON
+}
+
+// statePath is the file a scan's record is saved to
+func statePath(scanID string) string {
+	return filepath.Join(config.StateDir, filepath.Base(scanID)+".json")
+}
+
+// saveScanState writes a scan's status and endpoints to the state directory,
+// with the checkout it is scanning while it runs. It does nothing without a
+// state directory; failures are logged, never failing the scan.
+func saveScanState(scanID, checkout string) {
+	if config.StateDir == "" {
+		return
+	}
+	mu.RLock()
+	status, exists := scans[scanID]
+	if !exists {
+		mu.RUnlock()
+		return
+	}
+	record := scanRecord{Status: *status, Endpoints: endpoints[scanID], Checkout: checkout, CallbackURL: status.CallbackURL}
+	data, err := json.Marshal(record)
+	mu.RUnlock()
+	if err != nil {
+		log.Printf("⚠️  Failed to save state of scan %s: %v", scanID, err)
+		return
+	}
+
+	// Written aside and renamed, so a crash never leaves a torn record
+	tmp := statePath(scanID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Printf("⚠️  Failed to save state of scan %s: %v", scanID, err)
+		return
+	}
+	if err := os.Rename(tmp, statePath(scanID)); err != nil {
+		log.Printf("⚠️  Failed to save state of scan %s: %v", scanID, err)
+	}
+}
+
+// removeScanState deletes a scan's saved record
+func removeScanState(scanID string) {
+	if config.StateDir != "" {
+		os.Remove(statePath(scanID))
+	}
+}
+
+// RestoreScans loads the scans saved in the state directory. Scans still
+// queued when the server stopped are queued again with their saved
+// priority; since tokens aren't saved they clone with GIT_CREDENTIALS.
+// Scans cut short while scanning are failed, unless RESUME_INTERRUPTED_SCANS
+// is on and their checkout is still on disk: those are queued to be
+// re-extracted from it. StartWorkers must have been called first.
+func RestoreScans() error {
+	if config.StateDir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(config.StateDir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var record scanRecord
+		if err := json.Unmarshal(data, &record); err != nil || record.Status.ID == "" {
+			log.Printf("⚠️  Skipping unreadable scan state %s: %v", file, err)
+			continue
+		}
+		status := record.Status
+		status.CallbackURL = record.CallbackURL
+		mu.Lock()
+		scans[status.ID] = &status
+		endpoints[status.ID] = record.Endpoints
+		if endpoints[status.ID] == nil {
+			endpoints[status.ID] = []Endpoint{}
+		}
+		mu.Unlock()
+		restored++
+
+		if status.Status != "queued" && status.Status != "scanning" {
+			continue
+		}
+		job := ScanJob{ScanID: status.ID, URL: status.URL, Priority: Priority(status.Priority)}
+		if _, err := os.Stat(record.Checkout); config.ResumeInterrupted && record.Checkout != "" && err == nil {
+			log.Printf("🔁 Queueing interrupted scan %s to resume from %s", status.ID, record.Checkout)
+			job.Checkout = record.Checkout
+		} else if status.Status == "queued" && record.Checkout == "" {
+			log.Printf("🔁 Re-queueing scan %s", status.ID)
+			job.Branch = status.Branch
+			job.Commit = status.Commit
+			job.MinConfidence = status.MinConfidence
+			job.AllBranches = status.AllBranches
+			job.DropPaths = status.DropPaths
+			job.CallbackURL = status.CallbackURL
+		} else {
+			log.Printf("⚠️  Scan %s was interrupted by a restart", status.ID)
+			failScan(status.ID, ErrorCategoryInternal, ErrorInterrupted, nil)
+			continue
+		}
+		mu.Lock()
+		scans[status.ID].Status = "queued"
+		mu.Unlock()
+		saveScanState(status.ID, job.Checkout)
+		scanQueue.Push(job)
+	}
+	if restored > 0 {
+		log.Printf("📂 Restored %d scan(s) from %s", restored, config.StateDir)
+	}
+	return nil
+}
+
+// resumeScan re-runs extraction of an interrupted scan against its checkout,
+// then caches the checkout or removes it, as a finished scan would
+func resumeScan(scanID, checkout string) {
+	mu.Lock()
+	status := scans[scanID]
+	status.Status = "scanning"
+	status.StartedAt = time.Now()
+	commit := status.Commit
+	mu.Unlock()
+	notifyCallback(scanID, CallbackStarted)
+
+	scanCheckout(scanID, checkout)
+
+	if config.CloneCache {
+		cacheClone(scanID, checkout, commit)
+	} else {
+		os.RemoveAll(checkout)
+	}
+}
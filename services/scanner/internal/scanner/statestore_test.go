@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeScanRecord saves a record into the state directory as a previous
+// process would have left it
+func writeScanRecord(t *testing.T, record scanRecord) {
+	t.Helper()
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(statePath(record.Status.ID), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRestoreInterruptedScans verifies scans stuck at scanning by a crash
+// are failed on startup, while finished scans come back with their endpoints
+func TestRestoreInterruptedScans(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.StateDir = t.TempDir()
+
+	writeScanRecord(t, scanRecord{
+		Status:   ScanStatus{ID: "restore-stuck", Status: "scanning", StartedAt: time.Now()},
+		Checkout: filepath.Join(t.TempDir(), "gone"),
+	})
+	writeScanRecord(t, scanRecord{
+		Status:    ScanStatus{ID: "restore-done", Status: "completed", Endpoints: 1},
+		Endpoints: []Endpoint{{Method: "GET", Path: "/users"}},
+	})
+
+	if err := RestoreScans(); err != nil {
+		t.Fatal(err)
+	}
+
+	stuck, err := GetStatus("restore-stuck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stuck.Status != "failed" || stuck.Error != ErrorInterrupted {
+		t.Errorf("stuck scan = %s (%q), want failed as interrupted", stuck.Status, stuck.Error)
+	}
+	// The reconciled status is saved, so it isn't reconciled again
+	data, _ := os.ReadFile(statePath("restore-stuck"))
+	var saved scanRecord
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Status.Status != "failed" {
+		t.Errorf("saved stuck scan = %+v (%v), want failed", saved.Status, err)
+	}
+
+	eps, err := GetEndpoints("restore-done")
+	if err != nil || len(eps) != 1 || eps[0].Path != "/users" {
+		t.Errorf("restored endpoints = %+v (%v), want GET /users", eps, err)
+	}
+}
+
+// TestResumeInterruptedScan verifies an interrupted scan whose checkout is
+// still on disk is queued with its priority and re-extracted when resuming
+// is on
+func TestResumeInterruptedScan(t *testing.T) {
+	prev, prevQueue := config, scanQueue
+	t.Cleanup(func() { config, scanQueue = prev, prevQueue })
+	config.StateDir = t.TempDir()
+	config.ResumeInterrupted = true
+
+	ran := make(chan ScanJob, 1)
+	scanQueue = NewScanQueue(1, func(job ScanJob) {
+		runJob(job)
+		ran <- job
+	})
+
+	checkout := t.TempDir()
+	if err := os.WriteFile(filepath.Join(checkout, "main.py"), []byte(pythonFastAPI), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeScanRecord(t, scanRecord{
+		Status:   ScanStatus{ID: "restore-resume", Status: "scanning", Priority: "high", StartedAt: time.Now()},
+		Checkout: checkout,
+	})
+
+	if err := RestoreScans(); err != nil {
+		t.Fatal(err)
+	}
+	job := <-ran
+	if job.Checkout != checkout || job.Priority != PriorityHigh {
+		t.Errorf("queued job = %+v, want a high priority resume from %s", job, checkout)
+	}
+
+	status, err := GetStatus("restore-resume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "completed" {
+		t.Fatalf("resumed scan = %s (%q), want completed", status.Status, status.Error)
+	}
+	if status.Endpoints == 0 {
+		t.Error("resumed scan found no endpoints")
+	}
+}
+
+// TestRestoreQueuedScan verifies a scan still queued at a restart is queued
+// again with the options it was submitted with
+func TestRestoreQueuedScan(t *testing.T) {
+	prev, prevQueue := config, scanQueue
+	t.Cleanup(func() { config, scanQueue = prev, prevQueue })
+	config.StateDir = t.TempDir()
+
+	ran := make(chan ScanJob, 1)
+	scanQueue = NewScanQueue(1, func(job ScanJob) { ran <- job })
+
+	Enqueue(ScanJob{
+		ScanID:        "restore-queued",
+		URL:           "https://github.com/acme/api",
+		Branch:        "develop",
+		Priority:      PriorityLow,
+		MinConfidence: 0.5,
+		DropPaths:     []string{"/debug/*"},
+		CallbackURL:   "https://hooks.example.com/scan",
+	})
+	<-ran
+	mu.Lock()
+	delete(scans, "restore-queued")
+	mu.Unlock()
+
+	if err := RestoreScans(); err != nil {
+		t.Fatal(err)
+	}
+	job := <-ran
+	want := ScanJob{
+		ScanID:        "restore-queued",
+		URL:           "https://github.com/acme/api",
+		Branch:        "develop",
+		Priority:      PriorityLow,
+		MinConfidence: 0.5,
+		DropPaths:     []string{"/debug/*"},
+		CallbackURL:   "https://hooks.example.com/scan",
+	}
+	if !reflect.DeepEqual(job, want) {
+		t.Errorf("re-queued job = %+v, want %+v", job, want)
+	}
+	if status, err := GetStatus("restore-queued"); err != nil || status.Status != "queued" {
+		t.Errorf("restored status = %+v (%v), want queued", status, err)
+	}
+}
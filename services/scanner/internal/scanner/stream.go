@@ -0,0 +1,244 @@
+// Package scanner - Streaming extraction and export
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// DefaultExtractWorkers is the default number of files of one extension
+// extracted at once
+const DefaultExtractWorkers = 2
+
+// streamEndpoints performs Stage 2 over the pre-filtered files, sending each
+// endpoint scoring at least minConfidence on out as soon as its file is
+// extracted, leaving out those whose path matches a drop glob. It returns
+// the number of files that produced endpoints. out is left open.
+//
+// Each extension gets its own pool of workers (see Config.ExtractWorkers),
+// so files in an expensive language don't hold up cheap ones. Endpoints are
+// still sent in file order, and a failed file ends the stream after the
+// files before it. Each pool only runs as many files of its extension ahead
+// of the ones sent as it has workers, so a slow reader of out holds back
+// extraction rather than the results piling up.
+func streamEndpoints(rootDir string, apiFiles []string, minConfidence float64, drop []*regexp.Regexp, out chan<- Endpoint) (int, error) {
+	owners := loadCodeowners(rootDir)
+
+	results := make([]fileExtraction, len(apiFiles))
+	byExt := make(map[string][]int)
+	for i, filePath := range apiFiles {
+		results[i].done = make(chan struct{})
+		ext := extOf(filePath)
+		byExt[ext] = append(byExt[ext], i)
+	}
+	ahead := make(map[string]*lookahead, len(byExt))
+	for ext := range byExt {
+		ahead[ext] = newLookahead(config.extractWorkers(ext))
+		defer ahead[ext].stop()
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	for ext, files := range byExt {
+		jobs := make(chan int)
+		go func() {
+			defer close(jobs)
+			for pos, i := range files {
+				if !ahead[ext].admit(pos) {
+					return
+				}
+				select {
+				case jobs <- i:
+				case <-stop:
+					return
+				}
+			}
+		}()
+		for w := 0; w < config.extractWorkers(ext); w++ {
+			go func() {
+				for i := range jobs {
+					results[i].extract(rootDir, apiFiles[i], minConfidence)
+				}
+			}()
+		}
+	}
+
+	processedFiles := 0
+	for i := range results {
+		r := &results[i]
+		<-r.done
+		// The file was readable during Stage 1, so failing now means the checkout is broken
+		if r.err != nil {
+			return processedFiles, r.err
+		}
+
+		if r.longLines > 0 {
+			log.Printf("   ⚠️  %s: skipped %d line(s) over %d bytes", r.relPath, r.longLines, config.MaxLineLength)
+		}
+		if r.dropped > 0 {
+			log.Printf("   🔻 %s: dropped %d endpoint(s) below confidence %.2f", r.relPath, r.dropped, minConfidence)
+		}
+		if len(r.endpoints) > 0 {
+			processedFiles++
+			log.Printf("   📄 %s → %d endpoint(s)", r.relPath, len(r.endpoints))
+		}
+		fileOwners := owners.ownersOf(r.relPath)
+		for _, ep := range r.endpoints {
+			if droppedPath(ep.Path, drop) {
+				continue
+			}
+			ep.Owners = fileOwners
+			out <- ep
+		}
+		r.endpoints = nil
+		ahead[extOf(apiFiles[i])].advance()
+	}
+
+	return processedFiles, nil
+}
+
+// lookahead bounds how far past the files sent one extension's extraction
+// may run: its file i is admitted once fewer than window files are ahead of it
+type lookahead struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	next    int // the next file to send
+	window  int
+	stopped bool
+}
+
+func newLookahead(window int) *lookahead {
+	l := &lookahead{window: max(window, 1)}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// admit blocks until file i may be extracted, reporting false once the
+// stream has ended
+func (l *lookahead) admit(i int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i >= l.next+l.window && !l.stopped {
+		l.cond.Wait()
+	}
+	return !l.stopped
+}
+
+// advance moves past the file just sent
+func (l *lookahead) advance() {
+	l.mu.Lock()
+	l.next++
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+// stop releases every waiting admit
+func (l *lookahead) stop() {
+	l.mu.Lock()
+	l.stopped = true
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+// fileExtraction is the Stage 2 result for one file; done is closed once
+// it's filled in
+type fileExtraction struct {
+	done      chan struct{}
+	relPath   string
+	endpoints []Endpoint
+	dropped   int
+	longLines int
+	err       error
+}
+
+// extract reads and extracts one file
+func (r *fileExtraction) extract(rootDir, filePath string, minConfidence float64) {
+	defer close(r.done)
+
+	content, err := readFile(filePath)
+	if err != nil {
+		r.err = err
+		return
+	}
+
+	// Extract relative path from repo root
+	r.relPath, _ = filepath.Rel(rootDir, filePath)
+	r.longLines = longLines(string(content))
+	r.endpoints, r.dropped = filterByConfidence(scanFile(r.relPath, string(content), repoFiles(rootDir)), minConfidence)
+}
+
+// repoFiles loads files of the checkout at rootDir
+func repoFiles(rootDir string) FileLoader {
+	return func(relPath string) ([]byte, error) {
+		return readFile(filepath.Join(rootDir, filepath.FromSlash(relPath)))
+	}
+}
+
+// ErrExportPostProcessed is returned by StreamScan when POST_PROCESS_COMMAND
+// is set: the command takes the whole result, so endpoints can't be
+// streamed through it
+var ErrExportPostProcessed = errors.New("streaming export is unavailable while POST_PROCESS_COMMAND is set")
+
+// StreamScan clones a repository and sends its endpoints on out as they are
+// extracted, without recording a scan or holding the full result. out is
+// closed when the scan ends; the error, if any, is returned afterwards.
+// Endpoints below minConfidence are left out, and so are those matching
+// dropGlobs, or the server's DROP_PATHS when nil. With a post-processing
+// command configured nothing is cloned and ErrExportPostProcessed is returned.
+func StreamScan(url, branch, token string, minConfidence float64, dropGlobs []string, out chan<- Endpoint) error {
+	defer close(out)
+	if config.PostProcessCommand != "" {
+		return ErrExportPostProcessed
+	}
+
+	tmpDir, err := cloneOutsideQueue(url, branch, token)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	apiFiles, err := getLikelyAPIFiles(tmpDir)
+	if err != nil {
+		return fmt.Errorf("failed to scan files: %w", err)
+	}
+
+	if dropGlobs == nil {
+		dropGlobs = config.DropPaths
+	}
+	if _, err := streamEndpoints(tmpDir, apiFiles, minConfidence, compileDropPaths(dropGlobs), out); err != nil {
+		return fmt.Errorf("failed to extract endpoints: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSON writes endpoints as newline-delimited JSON as they arrive,
+// flushing after each one when w supports it. It always drains eps so the
+// producer never blocks, and returns the number of endpoints written.
+func WriteNDJSON(w io.Writer, eps <-chan Endpoint) (int, error) {
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	written := 0
+	var writeErr error
+	for ep := range eps {
+		if writeErr != nil {
+			continue
+		}
+		if writeErr = enc.Encode(ep); writeErr != nil {
+			continue
+		}
+		written++
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return written, writeErr
+}
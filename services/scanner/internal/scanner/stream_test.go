@@ -0,0 +1,238 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStreamScanMatchesBuffered verifies the streaming export writes exactly
+// what the buffered scan stores
+func TestStreamScanMatchesBuffered(t *testing.T) {
+	repoDir, _ := newFixtureRepo(t, map[string]string{
+		"routes/users.py": pythonFastAPI,
+		"main.go":         goGin,
+		"app.js":          jsFastify,
+	})
+
+	scanID := "stream-buffered"
+	StartScan(ScanJob{ScanID: scanID, URL: repoDir})
+	buffered, err := GetEndpoints(scanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buffered) == 0 {
+		t.Fatal("buffered scan found no endpoints")
+	}
+
+	var want bytes.Buffer
+	eps := make(chan Endpoint, len(buffered))
+	for _, ep := range buffered {
+		eps <- ep
+	}
+	close(eps)
+	if _, err := WriteNDJSON(&want, eps); err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	stream := make(chan Endpoint)
+	errc := make(chan error, 1)
+	go func() { errc <- StreamScan(repoDir, "", "", 0, nil, stream) }()
+	n, err := WriteNDJSON(&got, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("StreamScan() error = %v", err)
+	}
+
+	if n != len(buffered) {
+		t.Errorf("streamed %d endpoints, buffered %d", n, len(buffered))
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Errorf("streamed export differs from buffered output:\n got: %s\nwant: %s", got.String(), want.String())
+	}
+}
+
+// TestStreamScanDropPaths verifies the streamed export leaves out paths
+// matching the request's drop globs, or else the server's DROP_PATHS
+func TestStreamScanDropPaths(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.DropPaths = []string{"/users/*"}
+	repoDir, _ := newFixtureRepo(t, map[string]string{"routes/users.py": pythonFastAPI})
+
+	paths := func(dropGlobs []string) []string {
+		stream := make(chan Endpoint)
+		errc := make(chan error, 1)
+		go func() { errc <- StreamScan(repoDir, "", "", 0, dropGlobs, stream) }()
+		var got []string
+		for ep := range stream {
+			got = append(got, ep.Method+" "+ep.Path)
+		}
+		if err := <-errc; err != nil {
+			t.Fatalf("StreamScan() error = %v", err)
+		}
+		return got
+	}
+
+	if got := paths(nil); !reflect.DeepEqual(got, []string{"GET /users"}) {
+		t.Errorf("streamed with DROP_PATHS = %v, want only GET /users", got)
+	}
+	if got := paths([]string{"/users"}); !reflect.DeepEqual(got, []string{"POST /users/{user_id}"}) {
+		t.Errorf("streamed with request globs = %v, want only POST /users/{user_id}", got)
+	}
+}
+
+// countingExtractor records how many of its files are extracted at once
+type countingExtractor struct {
+	ext string
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	total    int
+}
+
+func (c *countingExtractor) Name() string                 { return "Counting " + c.ext }
+func (c *countingExtractor) Extensions() []string         { return []string{c.ext} }
+func (c *countingExtractor) Indicators() []*regexp.Regexp { return nil }
+func (c *countingExtractor) Keywords() []string           { return nil }
+
+func (c *countingExtractor) Extract(filePath, content string) []Endpoint {
+	c.mu.Lock()
+	c.inFlight++
+	c.total++
+	c.peak = max(c.peak, c.inFlight)
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond) // an expensive parse
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return []Endpoint{{Method: "GET", Path: "/" + filepath.Base(filePath), FilePath: filePath, LineNumber: 1}}
+}
+
+// TestStreamEndpointsExtensionWorkers verifies each extension is extracted
+// with its configured number of workers and results keep file order
+func TestStreamEndpointsExtensionWorkers(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.ExtractWorkers = 3
+	config.ExtensionWorkers = map[string]int{".slow": 1}
+
+	slow, fast := &countingExtractor{ext: ".slow"}, &countingExtractor{ext: ".fast"}
+	RegisterExtractor(slow)
+	RegisterExtractor(fast)
+	t.Cleanup(func() {
+		for _, ext := range []string{".slow", ".fast"} {
+			delete(extractors, ext)
+			delete(supportedExtensions, ext)
+		}
+	})
+
+	rootDir := t.TempDir()
+	var files []string
+	for i := 0; i < 6; i++ {
+		for _, ext := range []string{".slow", ".fast"} {
+			path := filepath.Join(rootDir, fmt.Sprintf("f%d%s", i, ext))
+			if err := os.WriteFile(path, []byte("route"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, path)
+		}
+	}
+
+	out := make(chan Endpoint)
+	var got []string
+	done := make(chan struct{})
+	go func() {
+		for ep := range out {
+			got = append(got, ep.Path)
+		}
+		close(done)
+	}()
+	if _, err := streamEndpoints(rootDir, files, 0, nil, out); err != nil {
+		t.Fatal(err)
+	}
+	close(out)
+	<-done
+
+	if slow.peak != 1 {
+		t.Errorf(".slow peak concurrency = %d, want 1", slow.peak)
+	}
+	if fast.peak != 3 {
+		t.Errorf(".fast peak concurrency = %d, want 3", fast.peak)
+	}
+	for i, path := range files {
+		if want := "/" + filepath.Base(path); i >= len(got) || got[i] != want {
+			t.Fatalf("endpoints = %v, want file order", got)
+		}
+	}
+
+	if n := (Config{ExtractWorkers: 2, ExtensionWorkers: map[string]int{"go": 1}}).extractWorkers(".go"); n != 1 {
+		t.Errorf("extractWorkers(.go) with a dotless override = %d, want 1", n)
+	}
+}
+
+// TestStreamEndpointsBackpressure verifies extraction waits for a slow
+// reader instead of running through every file
+func TestStreamEndpointsBackpressure(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.ExtractWorkers = 2
+
+	counter := &countingExtractor{ext: ".lazy"}
+	RegisterExtractor(counter)
+	t.Cleanup(func() {
+		delete(extractors, ".lazy")
+		delete(supportedExtensions, ".lazy")
+	})
+
+	rootDir := t.TempDir()
+	var files []string
+	for i := 0; i < 12; i++ {
+		path := filepath.Join(rootDir, fmt.Sprintf("f%02d.lazy", i))
+		if err := os.WriteFile(path, []byte("route"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+
+	out := make(chan Endpoint)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := streamEndpoints(rootDir, files, 0, nil, out)
+		close(out)
+		errs <- err
+	}()
+
+	<-out
+	time.Sleep(200 * time.Millisecond) // long enough to extract everything
+	counter.mu.Lock()
+	extracted := counter.total
+	counter.mu.Unlock()
+	// The file being sent, and at most one more per worker
+	if extracted > 1+config.ExtractWorkers {
+		t.Errorf("extracted %d files while the reader took 1, want at most %d", extracted, 1+config.ExtractWorkers)
+	}
+
+	n := 1
+	for range out {
+		n++
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if n != len(files) {
+		t.Errorf("streamed %d endpoints, want %d", n, len(files))
+	}
+}
@@ -0,0 +1,195 @@
+// Package scanner - swagger-jsdoc (@openapi / @swagger) comment parsing
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+var (
+	jsdocTagPattern = regexp.MustCompile(`^\s*\*?\s*@(openapi|swagger)\b`)
+	// jsdocLinePrefix is the " * " leading each line of a JSDoc block
+	jsdocLinePrefix = regexp.MustCompile(`^\s*\* ?`)
+)
+
+// jsdocOperation is one operation declared in a swagger-jsdoc block
+type jsdocOperation struct {
+	line   int // line number of the method key
+	method string
+	path   string
+	op     map[string]any
+}
+
+// parseJSDocOperations collects the operations declared in /** @openapi */
+// and /** @swagger */ comment blocks. A block holds a YAML paths object,
+// either at its top level or under "paths", and may declare several paths
+// and methods. Blocks that fail to parse are skipped.
+func parseJSDocOperations(lines []string) []jsdocOperation {
+	var ops []jsdocOperation
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "/**") {
+			continue
+		}
+		start := -1
+		var yamlLines []string
+		for ; i < len(lines); i++ {
+			line := lines[i]
+			end := strings.Contains(line, "*/")
+			if end {
+				line = line[:strings.Index(line, "*/")]
+			}
+			switch {
+			case start >= 0:
+				yamlLines = append(yamlLines, jsdocLinePrefix.ReplaceAllString(line, ""))
+			case jsdocTagPattern.MatchString(strings.TrimPrefix(strings.TrimSpace(line), "/**")):
+				start = i + 1
+			}
+			if end {
+				break
+			}
+		}
+		if start >= 0 {
+			ops = append(ops, jsdocBlockOperations(yamlLines, start)...)
+		}
+	}
+	return ops
+}
+
+// jsdocBlockOperations decodes the YAML of one block whose first line is
+// lines[start] of the source
+func jsdocBlockOperations(yamlLines []string, start int) []jsdocOperation {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(strings.Join(yamlLines, "\n")), &doc); err != nil || doc == nil {
+		return nil
+	}
+	paths, ok := doc["paths"].(map[string]any)
+	if !ok {
+		paths = doc
+	}
+
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		if strings.HasPrefix(path, "/") {
+			keys = append(keys, path)
+		}
+	}
+	sort.Strings(keys)
+
+	var ops []jsdocOperation
+	for _, path := range keys {
+		item, _ := paths[path].(map[string]any)
+		pathLine := specKeyLine(yamlLines, path, 0)
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			line := specKeyLine(yamlLines, method, pathLine)
+			if line == 0 {
+				line = pathLine
+			}
+			ops = append(ops, jsdocOperation{
+				line:   start + line,
+				method: strings.ToUpper(method),
+				path:   path,
+				op:     op,
+			})
+		}
+	}
+	return ops
+}
+
+// applyJSDocAnnotations enriches endpoints with the swagger-jsdoc operation
+// declared for their method and path. Annotations are authoritative: an
+// operation without a matching registration still yields an endpoint.
+func applyJSDocAnnotations(filePath string, found []Endpoint, lines []string) []Endpoint {
+	for _, doc := range parseJSDocOperations(lines) {
+		idx := -1
+		for i := range found {
+			if found[i].Method == doc.method && canonicalPath(found[i].Path) == canonicalPath(doc.path) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			found = append(found, Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), doc.method, doc.line),
+				Path:       doc.path,
+				Method:     doc.method,
+				FilePath:   filePath,
+				LineNumber: doc.line,
+				Tags:       []string{extractTag(filePath, doc.path)},
+			})
+			idx = len(found) - 1
+		}
+
+		ep := &found[idx]
+		if summary, _ := doc.op["summary"].(string); summary != "" {
+			ep.Summary = summary
+		}
+		if description, _ := doc.op["description"].(string); description != "" {
+			ep.Description = strings.TrimSpace(description)
+		}
+		if tags := jsdocStrings(doc.op["tags"]); len(tags) > 0 {
+			ep.Tags = tags
+		}
+		if params := jsdocQueryParams(doc.op["parameters"]); len(params) > 0 {
+			ep.QueryParams = params
+		}
+		if codes := jsdocResponseCodes(doc.op["responses"]); len(codes) > 0 {
+			ep.ResponseCodes = codes
+		}
+		input, response := specOperationModels(doc.op)
+		if input != "" {
+			ep.InputModel = input
+			ep.ValidatedInput = true
+		}
+		if response != "" {
+			ep.ResponseType = response
+		}
+	}
+	return found
+}
+
+// jsdocStrings returns the strings of a YAML sequence
+func jsdocStrings(v any) []string {
+	items, _ := v.([]any)
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// jsdocQueryParams returns the names of an operation's query parameters
+func jsdocQueryParams(v any) []string {
+	params, _ := v.([]any)
+	var names []string
+	for _, p := range params {
+		param, _ := p.(map[string]any)
+		if name, _ := param["name"].(string); name != "" && param["in"] == "query" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// jsdocResponseCodes returns an operation's numeric response codes in order
+func jsdocResponseCodes(v any) []int {
+	responses, _ := v.(map[string]any)
+	var codes []int
+	for key := range responses {
+		if code, err := strconv.Atoi(key); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
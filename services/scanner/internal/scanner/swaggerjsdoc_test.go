@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+const expressSwaggerJSDoc = `const express = require('express');
+const router = express.Router();
+
+/**
+ * @openapi
+ * /users:
+ *   get:
+ *     summary: List users
+ *     tags: [users]
+ *     parameters:
+ *       - in: query
+ *         name: page
+ *       - in: header
+ *         name: X-Org
+ *     responses:
+ *       200:
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: array
+ *               items:
+ *                 $ref: '#/components/schemas/User'
+ *       401:
+ *         description: Unauthorized
+ *   post:
+ *     summary: Create a user
+ *     requestBody:
+ *       content:
+ *         application/json:
+ *           schema:
+ *             $ref: '#/components/schemas/NewUser'
+ *     responses:
+ *       201:
+ *         description: Created
+ */
+router.get('/users', listUsers);
+router.post('/users', createUser);
+
+/**
+ * @swagger
+ * /users/{id}:
+ *   delete:
+ *     summary: Delete a user
+ *     description: >
+ *       Removes the user permanently.
+ *     responses:
+ *       204:
+ *         description: Deleted
+ */
+
+// Not an OpenAPI block
+/**
+ * Helper for pagination.
+ * /ignored:
+ *   get:
+ */
+
+module.exports = router;
+`
+
+// TestSwaggerJSDoc verifies @openapi blocks enrich the Express routes below
+// them and define the routes they document without a registration
+func TestSwaggerJSDoc(t *testing.T) {
+	endpoints := ScanFile("routes/users.js", expressSwaggerJSDoc)
+	if len(endpoints) != 3 {
+		t.Fatalf("ScanFile() found %d endpoints, want 3: %+v", len(endpoints), endpoints)
+	}
+
+	list, create, del := endpoints[0], endpoints[1], endpoints[2]
+	if list.Method != "GET" || list.Path != "/users" || list.LineNumber != 36 {
+		t.Errorf("first endpoint = %s %s:%d, want the registered GET /users:36", list.Method, list.Path, list.LineNumber)
+	}
+	if list.Summary != "List users" || !reflect.DeepEqual(list.Tags, []string{"users"}) {
+		t.Errorf("list Summary = %q, Tags = %v", list.Summary, list.Tags)
+	}
+	if !reflect.DeepEqual(list.QueryParams, []string{"page"}) {
+		t.Errorf("list QueryParams = %v, want [page]", list.QueryParams)
+	}
+	if !reflect.DeepEqual(list.ResponseCodes, []int{200, 401}) || list.ResponseType != "User[]" {
+		t.Errorf("list ResponseCodes = %v, ResponseType = %q", list.ResponseCodes, list.ResponseType)
+	}
+
+	if create.Method != "POST" || create.Summary != "Create a user" {
+		t.Errorf("second endpoint = %s %q, want annotated POST", create.Method, create.Summary)
+	}
+	if !create.ValidatedInput || create.InputModel != "NewUser" {
+		t.Errorf("create InputModel = %q, ValidatedInput = %v", create.InputModel, create.ValidatedInput)
+	}
+
+	// Documented but never registered: defined at its method key
+	if del.Method != "DELETE" || del.Path != "/users/{id}" || del.LineNumber != 42 {
+		t.Errorf("third endpoint = %s %s:%d, want DELETE /users/{id}:42", del.Method, del.Path, del.LineNumber)
+	}
+	if del.Summary != "Delete a user" || del.Description != "Removes the user permanently." {
+		t.Errorf("delete Summary = %q, Description = %q", del.Summary, del.Description)
+	}
+	if !reflect.DeepEqual(del.ResponseCodes, []int{204}) {
+		t.Errorf("delete ResponseCodes = %v", del.ResponseCodes)
+	}
+}
@@ -0,0 +1,134 @@
+// Package scanner - swaggo (Go Swagger) annotation parsing
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// swaggo annotation patterns
+var (
+	swagAnnotationPattern = regexp.MustCompile(`^\s*//\s*@(\w+)\s*(.*)$`)
+	swagRouterPattern     = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]`)
+	swagParamPattern      = regexp.MustCompile(`^(\S+)\s+(\w+)`)
+	swagResponsePattern   = regexp.MustCompile(`^(\d{3})\b`)
+)
+
+// swagBlock is one parsed swaggo comment block
+type swagBlock struct {
+	line          int // line number of the @Router annotation
+	method        string
+	path          string
+	summary       string
+	description   string
+	tags          []string
+	queryParams   []string
+	responseCodes []int
+}
+
+// parseSwagBlocks collects swaggo comment blocks that declare a @Router
+func parseSwagBlocks(lines []string) []swagBlock {
+	var blocks []swagBlock
+	var current *swagBlock
+
+	for i, line := range lines {
+		m := swagAnnotationPattern.FindStringSubmatch(line)
+		if m == nil {
+			// Any non-comment line ends the block
+			if !strings.HasPrefix(strings.TrimSpace(line), "//") {
+				if current != nil && current.path != "" {
+					blocks = append(blocks, *current)
+				}
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			current = &swagBlock{}
+		}
+
+		value := strings.TrimSpace(m[2])
+		switch strings.ToLower(m[1]) {
+		case "summary":
+			current.summary = value
+		case "description":
+			if current.description != "" {
+				current.description += " "
+			}
+			current.description += value
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					current.tags = append(current.tags, tag)
+				}
+			}
+		case "param":
+			if p := swagParamPattern.FindStringSubmatch(value); p != nil && p[2] == "query" {
+				current.queryParams = append(current.queryParams, p[1])
+			}
+		case "success", "failure", "response":
+			if r := swagResponsePattern.FindStringSubmatch(value); r != nil {
+				code, _ := strconv.Atoi(r[1])
+				current.responseCodes = append(current.responseCodes, code)
+			}
+		case "router":
+			if r := swagRouterPattern.FindStringSubmatch(value); r != nil {
+				current.path = r[1]
+				current.method = strings.ToUpper(r[2])
+				current.line = i + 1
+			}
+		}
+	}
+	if current != nil && current.path != "" {
+		blocks = append(blocks, *current)
+	}
+
+	return blocks
+}
+
+// applySwagAnnotations enriches regex-detected endpoints with swaggo metadata.
+// Annotations are authoritative: a @Router without a matching registration
+// still yields an endpoint.
+func applySwagAnnotations(found []Endpoint, lines []string, filePath string) []Endpoint {
+	for _, block := range parseSwagBlocks(lines) {
+		idx := -1
+		for i := range found {
+			if found[i].Method == block.method && canonicalPath(found[i].Path) == canonicalPath(block.path) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			found = append(found, Endpoint{
+				ID:         fmt.Sprintf("%s-%s-%d", scanID(filePath), block.method, block.line),
+				Path:       block.path,
+				Method:     block.method,
+				FilePath:   filePath,
+				LineNumber: block.line,
+				Tags:       []string{extractTag(filePath, block.path)},
+			})
+			idx = len(found) - 1
+		}
+
+		ep := &found[idx]
+		if block.summary != "" {
+			ep.Summary = block.summary
+		}
+		if block.description != "" {
+			ep.Description = block.description
+		}
+		if len(block.tags) > 0 {
+			ep.Tags = block.tags
+		}
+		if len(block.queryParams) > 0 {
+			ep.QueryParams = block.queryParams
+		}
+		if len(block.responseCodes) > 0 {
+			ep.ResponseCodes = block.responseCodes
+		}
+	}
+
+	return found
+}
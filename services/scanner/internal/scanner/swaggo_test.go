@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+const goSwaggo = `package handlers
+
+import "github.com/gin-gonic/gin"
+
+// ListUsers godoc
+// @Summary      List users
+// @Description  Returns a page of users
+// @Description  ordered by creation date.
+// @Tags         users, admin
+// @Param        page   query  int     false  "Page number"
+// @Param        limit  query  int     false  "Page size"
+// @Param        X-Org  header string  true   "Organisation"
+// @Success      200  {array}   User
+// @Failure      401  {object}  ErrorResponse
+// @Router       /users [get]
+func ListUsers(c *gin.Context) {}
+
+// GetUser godoc
+// @Summary  Get a user
+// @Success  200  {object}  User
+// @Failure  404  {object}  ErrorResponse
+// @Router   /users/{id} [get]
+func GetUser(c *gin.Context) {}
+
+func Register(r *gin.Engine) {
+	r.GET("/users/:id", GetUser)
+}
+`
+
+// TestSwagAnnotations verifies a full swaggo block enriches the endpoint
+func TestSwagAnnotations(t *testing.T) {
+	if !hasAPIIndicators("handlers/users.go", goSwaggo) {
+		t.Fatal("swaggo file should have API indicators")
+	}
+
+	endpoints := ScanFile("handlers/users.go", goSwaggo)
+	if len(endpoints) != 2 {
+		t.Fatalf("ScanFile() found %d endpoints, want 2: %+v", len(endpoints), endpoints)
+	}
+
+	list := endpoints[0]
+	if list.Path != "/users" || list.Method != "GET" {
+		t.Errorf("first endpoint = %s %s, want GET /users", list.Method, list.Path)
+	}
+	if list.Summary != "List users" {
+		t.Errorf("Summary = %q", list.Summary)
+	}
+	if list.Description != "Returns a page of users ordered by creation date." {
+		t.Errorf("Description = %q", list.Description)
+	}
+	if !reflect.DeepEqual(list.Tags, []string{"users", "admin"}) {
+		t.Errorf("Tags = %v", list.Tags)
+	}
+	if !reflect.DeepEqual(list.QueryParams, []string{"page", "limit"}) {
+		t.Errorf("QueryParams = %v", list.QueryParams)
+	}
+	if !reflect.DeepEqual(list.ResponseCodes, []int{200, 401}) {
+		t.Errorf("ResponseCodes = %v", list.ResponseCodes)
+	}
+
+	// The registered gin route is matched to its annotation block
+	get := endpoints[1]
+	if get.Path != "/users/{id}" || get.Summary != "Get a user" {
+		t.Errorf("second endpoint = %+v, want annotated /users/{id}", get)
+	}
+	if !reflect.DeepEqual(get.ResponseCodes, []int{200, 404}) {
+		t.Errorf("ResponseCodes = %v", get.ResponseCodes)
+	}
+}
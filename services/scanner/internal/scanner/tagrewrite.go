@@ -0,0 +1,57 @@
+// Package scanner - Rewriting endpoint tags to canonical names
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TagRewrite maps the tags matching Pattern to Tag. Tag may refer to the
+// pattern's groups, e.g. ${1}.
+type TagRewrite struct {
+	Pattern *regexp.Regexp
+	Tag     string
+}
+
+// parseTagRewrites parses "controllers=api,ctrl=api,v\d+-(\w+)=$1" into
+// rewrites in order. Sources are regular expressions matched against the
+// whole tag; invalid ones are dropped.
+func parseTagRewrites(s string) []TagRewrite {
+	var rewrites []TagRewrite
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(`^(?:` + from + `)$`)
+		if err != nil {
+			continue
+		}
+		rewrites = append(rewrites, TagRewrite{Pattern: pattern, Tag: to})
+	}
+	return rewrites
+}
+
+// applyTagRewrites replaces each tag with the canonical tag of the first
+// rewrite matching it, dropping duplicates. Unmatched tags are kept as-is.
+func applyTagRewrites(found []Endpoint, rewrites []TagRewrite) {
+	if len(rewrites) == 0 {
+		return
+	}
+	for i := range found {
+		var tags []string
+		for _, tag := range found[i].Tags {
+			for _, r := range rewrites {
+				if r.Pattern.MatchString(tag) {
+					tag = r.Pattern.ReplaceAllString(tag, r.Tag)
+					break
+				}
+			}
+			if !containsString(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		found[i].Tags = tags
+	}
+}
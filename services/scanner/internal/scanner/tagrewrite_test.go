@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTagRewrites verifies inconsistent directory tags are mapped to one
+// canonical tag, and unmapped tags are kept
+func TestTagRewrites(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	config.TagRewrites = parseTagRewrites(`controllers=api, ctrl=api, v\d+-(\w+)=$1, [invalid=x`)
+	if len(config.TagRewrites) != 3 {
+		t.Fatalf("parseTagRewrites() = %d rewrites, want 3 without the invalid pattern", len(config.TagRewrites))
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"app/controllers/users.py", []string{"api"}},
+		{"app/ctrl/users.py", []string{"api"}},
+		{"app/v2-billing/users.py", []string{"billing"}},
+		{"app/routes/users.py", []string{"routes"}},
+		{"app/my-controllers/users.py", []string{"my-controllers"}}, // matched whole
+	}
+	for _, tt := range tests {
+		for _, ep := range ScanFile(tt.file, pythonFastAPI) {
+			if !reflect.DeepEqual(ep.Tags, tt.want) {
+				t.Errorf("%s: %s %s tags = %v, want %v", tt.file, ep.Method, ep.Path, ep.Tags, tt.want)
+			}
+		}
+	}
+
+	eps := []Endpoint{{Tags: []string{"ctrl", "controllers", "admin"}}}
+	applyTagRewrites(eps, config.TagRewrites)
+	if want := []string{"api", "admin"}; !reflect.DeepEqual(eps[0].Tags, want) {
+		t.Errorf("tags = %v, want %v with duplicates dropped", eps[0].Tags, want)
+	}
+}
@@ -0,0 +1,69 @@
+// Package scanner - HTTP transport customisation for git clones
+package scanner
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// headerTransport sets a custom user agent and extra headers on every request
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newCloneTransport wraps base with the configured user agent and headers
+func newCloneTransport(base http.RoundTripper, cfg Config) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if cfg.CloneUserAgent == "" && len(cfg.CloneHeaders) == 0 {
+		return base
+	}
+	return &headerTransport{
+		base:      base,
+		userAgent: cfg.CloneUserAgent,
+		headers:   cfg.CloneHeaders,
+	}
+}
+
+// installCloneTransport registers the git HTTP(S) transport used by clones,
+// sharing the provider client
+func installCloneTransport(cfg Config) {
+	providerClient = &http.Client{
+		Transport: newRateLimitTransport(newCloneTransport(http.DefaultTransport, cfg)),
+	}
+	transport := githttp.NewClient(providerClient)
+	client.InstallProtocol("http", transport)
+	client.InstallProtocol("https", transport)
+}
+
+// parseHeaderList parses "Name: value; Other: value" into a header map
+func parseHeaderList(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(value)
+	}
+	return headers
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCloneTransportHeaders verifies the configured user agent and headers reach the git host
+func TestCloneTransportHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.CloneUserAgent = "autodoc-scanner/2.0"
+	cfg.CloneHeaders = parseHeaderList("X-Corp-Gateway: scanner; x-trace-id : abc123")
+
+	httpClient := &http.Client{Transport: newCloneTransport(http.DefaultTransport, cfg)}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/org/repo.git/info/refs", nil)
+	req.Header.Set("User-Agent", "git/go-git")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if ua := got.Get("User-Agent"); ua != "autodoc-scanner/2.0" {
+		t.Errorf("User-Agent = %q, want configured agent", ua)
+	}
+	if v := got.Get("X-Corp-Gateway"); v != "scanner" {
+		t.Errorf("X-Corp-Gateway = %q, want scanner", v)
+	}
+	if v := got.Get("X-Trace-Id"); v != "abc123" {
+		t.Errorf("X-Trace-Id = %q, want abc123", v)
+	}
+}
+
+// TestCloneTransportDefault verifies nothing is wrapped when unconfigured
+func TestCloneTransportDefault(t *testing.T) {
+	if rt := newCloneTransport(http.DefaultTransport, DefaultConfig()); rt != http.DefaultTransport {
+		t.Errorf("newCloneTransport() = %T, want the base transport", rt)
+	}
+}
@@ -0,0 +1,76 @@
+// Package scanner - Hierarchical view of the API surface
+package scanner
+
+import (
+	"sort"
+	"strings"
+)
+
+// PathNode is one path segment in the API tree
+type PathNode struct {
+	Segment  string      `json:"segment"`
+	Path     string      `json:"path"`
+	Count    int         `json:"count"`             // endpoints at or below this node
+	Methods  []string    `json:"methods,omitempty"` // methods registered on exactly this path
+	Children []*PathNode `json:"children,omitempty"`
+
+	index map[string]*PathNode
+}
+
+// BuildPathTree builds a trie of endpoint paths rooted at "/"
+func BuildPathTree(eps []Endpoint) *PathNode {
+	root := &PathNode{Segment: "/", Path: "/"}
+
+	for _, ep := range eps {
+		node := root
+		node.Count++
+		for _, segment := range strings.Split(strings.Trim(ep.Path, "/"), "/") {
+			if segment == "" {
+				continue
+			}
+			node = node.child(segment)
+			node.Count++
+		}
+		node.addMethod(ep.Method)
+	}
+
+	root.sort()
+	return root
+}
+
+// child returns the named child, creating it if needed
+func (n *PathNode) child(segment string) *PathNode {
+	if n.index == nil {
+		n.index = make(map[string]*PathNode)
+	}
+	if c, ok := n.index[segment]; ok {
+		return c
+	}
+
+	path := strings.TrimSuffix(n.Path, "/") + "/" + segment
+	c := &PathNode{Segment: segment, Path: path}
+	n.index[segment] = c
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// addMethod records a method on this node once
+func (n *PathNode) addMethod(method string) {
+	for _, m := range n.Methods {
+		if sameMethod(m, method) {
+			return
+		}
+	}
+	n.Methods = append(n.Methods, method)
+}
+
+// sort orders children and methods for stable output
+func (n *PathNode) sort() {
+	sort.Strings(n.Methods)
+	sort.Slice(n.Children, func(i, j int) bool {
+		return n.Children[i].Segment < n.Children[j].Segment
+	})
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
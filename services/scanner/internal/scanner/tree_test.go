@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBuildPathTree verifies nesting, counts and leaf methods
+func TestBuildPathTree(t *testing.T) {
+	eps := []Endpoint{
+		{Method: "GET", Path: "/api/v1/users"},
+		{Method: "POST", Path: "/api/v1/users"},
+		{Method: "GET", Path: "/api/v1/users/{id}"},
+		{Method: "GET", Path: "/api/v2/orders"},
+		{Method: "GET", Path: "/health"},
+	}
+
+	root := BuildPathTree(eps)
+	if root.Count != 5 || len(root.Children) != 2 {
+		t.Fatalf("root = %d endpoints, %d children; want 5, 2", root.Count, len(root.Children))
+	}
+
+	api := root.Children[0]
+	if api.Path != "/api" || api.Count != 4 || len(api.Methods) != 0 {
+		t.Errorf("api node = %+v", api)
+	}
+
+	v1 := api.Children[0]
+	if v1.Path != "/api/v1" || v1.Count != 3 {
+		t.Errorf("v1 node = %+v", v1)
+	}
+
+	users := v1.Children[0]
+	if users.Path != "/api/v1/users" || users.Count != 3 || !reflect.DeepEqual(users.Methods, []string{"GET", "POST"}) {
+		t.Errorf("users node = %+v", users)
+	}
+	if len(users.Children) != 1 || users.Children[0].Segment != "{id}" || users.Children[0].Count != 1 {
+		t.Errorf("users children = %+v", users.Children)
+	}
+
+	health := root.Children[1]
+	if health.Path != "/health" || !reflect.DeepEqual(health.Methods, []string{"GET"}) {
+		t.Errorf("health node = %+v", health)
+	}
+}
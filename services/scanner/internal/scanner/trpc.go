@@ -0,0 +1,308 @@
+// Package scanner - tRPC router procedures
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tRPC procedure kinds, reported as the endpoint method
+const (
+	TRPCQuery        = "QUERY"
+	TRPCMutation     = "MUTATION"
+	TRPCSubscription = "SUBSCRIPTION"
+)
+
+// KindProcedure marks RPC procedures, which have no HTTP method or path of their own
+const KindProcedure = "procedure"
+
+// TRPCTag tags tRPC procedures
+const TRPCTag = "trpc"
+
+var (
+	// const userRouter = router({, export const appRouter = t.router({
+	trpcRouterPattern = regexp.MustCompile(`\b(?:const|let|var)\s+(\w+)\s*=\s*(?:\w+\.)?(?:router|createTRPCRouter)\s*\(\s*\{`)
+	// const appRouter = mergeRouters(userRouter, postRouter)
+	trpcMergePattern = regexp.MustCompile(`\b(?:const|let|var)\s+(\w+)\s*=\s*(?:\w+\.)?mergeRouters\s*\(([^)]*)\)`)
+	// An inline nested router value: router({ or t.router({
+	trpcInlineRouterPattern = regexp.MustCompile(`^(?:\w+\.)?(?:router|createTRPCRouter)\s*\(\s*\{`)
+	// The procedure's terminal call: .query(, .mutation(, .subscription(
+	trpcProcedurePattern = regexp.MustCompile(`^\.\s*(query|mutation|subscription)\s*\(`)
+	trpcKeyPattern       = regexp.MustCompile(`^\s*(?:(\w+)|["']([^"']+)["'])\s*(?::|$)`)
+	identPattern         = regexp.MustCompile(`^\w+$`)
+)
+
+// maxTRPCNesting bounds router references followed, guarding against cycles
+const maxTRPCNesting = 8
+
+// trpcExtractor resolves the routers declared in one file
+type trpcExtractor struct {
+	filePath string
+	src      string
+	routers  map[string][2]int   // object body span by router name
+	merges   map[string][]string // merged router names by router name
+	found    []Endpoint
+}
+
+// extractTRPCRoutes emits one endpoint per procedure of the file's root
+// routers, those no other router in the file nests or merges. Nested
+// routers namespace their procedures: user.getUser.
+func extractTRPCRoutes(filePath string, lines []string) []Endpoint {
+	src := strings.Join(lines, "\n")
+	if !strings.Contains(src, "router") && !strings.Contains(src, "Router") {
+		return nil
+	}
+	x := &trpcExtractor{
+		filePath: filePath,
+		src:      src,
+		routers:  make(map[string][2]int),
+		merges:   make(map[string][]string),
+	}
+
+	var order []string
+	for _, m := range trpcRouterPattern.FindAllStringSubmatchIndex(src, -1) {
+		open := m[1] - 1
+		end := jsBlockEnd(src, open)
+		if end < 0 {
+			continue
+		}
+		name := src[m[2]:m[3]]
+		x.routers[name] = [2]int{open + 1, end}
+		order = append(order, name)
+	}
+	for _, m := range trpcMergePattern.FindAllStringSubmatch(src, -1) {
+		for _, arg := range strings.Split(m[2], ",") {
+			if arg = strings.TrimSpace(arg); identPattern.MatchString(arg) {
+				x.merges[m[1]] = append(x.merges[m[1]], arg)
+			}
+		}
+		order = append(order, m[1])
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	// Routers referenced by another router are reached through it
+	referenced := make(map[string]bool)
+	for _, span := range x.routers {
+		for _, e := range x.entries(span[0], span[1]) {
+			if identPattern.MatchString(e.value) {
+				referenced[e.value] = true
+			}
+		}
+	}
+	for _, names := range x.merges {
+		for _, name := range names {
+			referenced[name] = true
+		}
+	}
+
+	for _, name := range order {
+		if !referenced[name] {
+			x.resolve(name, "", 0)
+		}
+	}
+	return x.found
+}
+
+// resolve emits the procedures of a named router under prefix
+func (x *trpcExtractor) resolve(name, prefix string, depth int) {
+	if depth > maxTRPCNesting {
+		return
+	}
+	if span, ok := x.routers[name]; ok {
+		x.resolveBody(span[0], span[1], prefix, depth)
+	}
+	for _, merged := range x.merges[name] {
+		x.resolve(merged, prefix, depth+1)
+	}
+}
+
+// resolveBody emits the procedures in a router's object body
+func (x *trpcExtractor) resolveBody(start, end int, prefix string, depth int) {
+	for _, e := range x.entries(start, end) {
+		path := prefix + e.key
+		switch {
+		case trpcInlineRouterPattern.MatchString(e.value):
+			open := e.valueAt + strings.Index(e.value, "{")
+			if close := jsBlockEnd(x.src, open); close > 0 {
+				x.resolveBody(open+1, close, path+".", depth+1)
+			}
+		case identPattern.MatchString(e.value):
+			x.resolve(e.value, path+".", depth+1)
+		default:
+			if method := trpcProcedureMethod(e.value); method != "" {
+				x.add(method, path, e.keyAt)
+			}
+		}
+	}
+}
+
+// add records a procedure declared at offset
+func (x *trpcExtractor) add(method, path string, offset int) {
+	line := strings.Count(x.src[:offset], "\n") + 1
+	x.found = append(x.found, Endpoint{
+		ID:         fmt.Sprintf("%s-%s-%d", scanID(x.filePath), method, line),
+		Path:       path,
+		Method:     method,
+		FilePath:   x.filePath,
+		LineNumber: line,
+		Tags:       []string{TRPCTag},
+		Kind:       KindProcedure,
+	})
+}
+
+// trpcEntry is one property of a router object: key: value
+type trpcEntry struct {
+	key     string
+	keyAt   int
+	value   string
+	valueAt int
+}
+
+// entries splits an object body into its top-level properties. Shorthand
+// properties ({ user }) reference the router of the same name.
+func (x *trpcExtractor) entries(start, end int) []trpcEntry {
+	var out []trpcEntry
+	for _, part := range splitTopLevel(x.src, start, end) {
+		// Skip comments ahead of the key
+		for {
+			trimmed := strings.TrimLeft(x.src[part[0]:part[1]], " \t\r\n")
+			part[0] = part[1] - len(trimmed)
+			if !strings.HasPrefix(trimmed, "//") && !strings.HasPrefix(trimmed, "/*") {
+				break
+			}
+			part[0] = min(skipJSComment(x.src, part[0])+1, part[1])
+		}
+		text := x.src[part[0]:part[1]]
+		m := trpcKeyPattern.FindStringSubmatchIndex(text)
+		if m == nil {
+			continue // spreads, methods and computed keys
+		}
+		e := trpcEntry{}
+		if m[2] >= 0 {
+			e.key, e.keyAt = text[m[2]:m[3]], part[0]+m[2]
+		} else {
+			e.key, e.keyAt = text[m[4]:m[5]], part[0]+m[4]
+		}
+		rest := text[m[1]:]
+		if !strings.HasSuffix(text[:m[1]], ":") {
+			rest = e.key // shorthand
+		}
+		trimmed := strings.TrimLeft(rest, " \t\r\n")
+		e.valueAt = part[0] + m[1] + len(rest) - len(trimmed)
+		e.value = strings.TrimSpace(trimmed)
+		out = append(out, e)
+	}
+	return out
+}
+
+// trpcProcedureMethod returns the kind of a procedure expression from the
+// call ending its builder chain, ignoring calls nested in its arguments
+// such as ctx.db.query() inside a resolver
+func trpcProcedureMethod(value string) string {
+	depth := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '"', '\'', '`':
+			i = skipJSString(value, i)
+		case '.':
+			if depth != 0 {
+				continue
+			}
+			if m := trpcProcedurePattern.FindStringSubmatch(value[i:]); m != nil {
+				return strings.ToUpper(m[1])
+			}
+		}
+	}
+	return ""
+}
+
+// splitTopLevel splits src[start:end] at commas outside brackets, strings
+// and comments, returning the spans of the parts
+func splitTopLevel(src string, start, end int) [][2]int {
+	var parts [][2]int
+	depth, from := 0, start
+	for i := start; i < end; i++ {
+		switch src[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '"', '\'', '`':
+			i = skipJSString(src, i)
+		case '/':
+			i = skipJSComment(src, i)
+		case ',':
+			if depth == 0 {
+				parts = append(parts, [2]int{from, i})
+				from = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(src[from:end]) != "" {
+		parts = append(parts, [2]int{from, end})
+	}
+	return parts
+}
+
+// jsBlockEnd returns the index of the brace closing the one at open, or -1
+func jsBlockEnd(src string, open int) int {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '{', '(', '[':
+			depth++
+		case '}', ')', ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '\'', '`':
+			i = skipJSString(src, i)
+		case '/':
+			i = skipJSComment(src, i)
+		}
+	}
+	return -1
+}
+
+// skipJSString returns the index of the quote closing the string opened at i
+func skipJSString(src string, i int) int {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case quote:
+			return j
+		}
+	}
+	return len(src) - 1
+}
+
+// skipJSComment returns the last index of a comment starting at i, or i
+// when the slash doesn't open one
+func skipJSComment(src string, i int) int {
+	if i+1 >= len(src) {
+		return i
+	}
+	switch src[i+1] {
+	case '/':
+		if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(src) - 1
+	case '*':
+		if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 1
+		}
+		return len(src) - 1
+	}
+	return i
+}
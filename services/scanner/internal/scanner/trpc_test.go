@@ -0,0 +1,72 @@
+package scanner
+
+import "testing"
+
+// TestTRPCRouters verifies procedures are extracted with nested and merged router namespaces
+func TestTRPCRouters(t *testing.T) {
+	code := `import { initTRPC } from '@trpc/server';
+import { z } from 'zod';
+
+const t = initTRPC.create();
+const router = t.router;
+const publicProcedure = t.procedure;
+
+const userRouter = router({
+  getUser: publicProcedure
+    .input(z.object({ id: z.string() }))
+    .query(({ input }) => db.user.find(input.id)),
+  // Handlers may call query() themselves
+  createUser: publicProcedure.input(z.object({ name: z.string() })).mutation(async ({ ctx, input }) => {
+    return ctx.db.query('insert', input);
+  }),
+});
+
+const postRouter = router({
+  list: publicProcedure.query(() => posts),
+  admin: router({
+    purge: publicProcedure.mutation(() => purge()),
+  }),
+  onAdd: publicProcedure.subscription(() => observable()),
+});
+
+const healthRouter = router({
+  'health.ping': publicProcedure.query(() => 'pong'),
+});
+
+const apiRouter = router({
+  user: userRouter,
+  post: postRouter,
+});
+
+export const appRouter = mergeRouters(apiRouter, healthRouter);
+export type AppRouter = typeof appRouter;
+`
+	want := []struct {
+		method, path string
+		line         int
+	}{
+		{"QUERY", "user.getUser", 9},
+		{"MUTATION", "user.createUser", 13},
+		{"QUERY", "post.list", 19},
+		{"MUTATION", "post.admin.purge", 21},
+		{"SUBSCRIPTION", "post.onAdd", 23},
+		{"QUERY", "health.ping", 27},
+	}
+
+	if !hasAPIIndicators("server/router.ts", code) {
+		t.Fatal("tRPC router rejected by the pre-filter")
+	}
+	endpoints := ScanFile("server/router.ts", code)
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.Method != want[i].method || ep.Path != want[i].path || ep.LineNumber != want[i].line {
+			t.Errorf("endpoint %d = %s %s (line %d), want %s %s (line %d)",
+				i, ep.Method, ep.Path, ep.LineNumber, want[i].method, want[i].path, want[i].line)
+		}
+		if len(ep.Tags) != 1 || ep.Tags[0] != TRPCTag || ep.Kind != KindProcedure {
+			t.Errorf("endpoint %d tags = %v kind = %q, want [%s] %q", i, ep.Tags, ep.Kind, TRPCTag, KindProcedure)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package scanner - Endpoints grouped by API version
+package scanner
+
+// UnversionedGroup holds the endpoints without a detected API version
+const UnversionedGroup = "unversioned"
+
+// GroupByVersion buckets endpoints by their detected APIVersion, keeping
+// their order within each bucket
+func GroupByVersion(eps []Endpoint) map[string][]Endpoint {
+	groups := make(map[string][]Endpoint)
+	for _, ep := range eps {
+		version := ep.APIVersion
+		if version == "" {
+			version = UnversionedGroup
+		}
+		groups[version] = append(groups[version], ep)
+	}
+	return groups
+}
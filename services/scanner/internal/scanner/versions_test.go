@@ -0,0 +1,49 @@
+package scanner
+
+import "testing"
+
+// TestGroupByVersion verifies a mixed-version file's endpoints are bucketed
+// by their detected API version, with unversioned ones kept apart
+func TestGroupByVersion(t *testing.T) {
+	content := `from fastapi import FastAPI
+app = FastAPI()
+
+@app.get("/api/v1/users")
+def list_users_v1():
+    return []
+
+@app.get("/api/v2/users")
+def list_users_v2():
+    return []
+
+@app.post("/api/v2/users")
+def create_user_v2():
+    return {}
+
+@app.get("/health")
+def health():
+    return "ok"
+`
+	groups := GroupByVersion(ScanFile("main.py", content))
+
+	want := map[string][]string{
+		"v1":             {"GET /api/v1/users"},
+		"v2":             {"GET /api/v2/users", "POST /api/v2/users"},
+		UnversionedGroup: {"GET /health"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("GroupByVersion() = %d groups, want %d: %+v", len(groups), len(want), groups)
+	}
+	for version, routes := range want {
+		got := groups[version]
+		if len(got) != len(routes) {
+			t.Errorf("group %s has %d endpoints, want %d", version, len(got), len(routes))
+			continue
+		}
+		for i, route := range routes {
+			if g := got[i].Method + " " + got[i].Path; g != route {
+				t.Errorf("group %s endpoint %d = %s, want %s", version, i, g, route)
+			}
+		}
+	}
+}
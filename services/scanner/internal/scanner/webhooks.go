@@ -0,0 +1,141 @@
+// Package scanner - OpenAPI webhooks and operation callbacks
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Endpoint kinds for requests an API sends to its consumers rather than serves
+const (
+	// KindWebhook marks OpenAPI 3.1 webhooks, reported with the webhook's
+	// name as their path
+	KindWebhook = "webhook"
+	// KindCallback marks operation callbacks, reported with their runtime
+	// expression, e.g. {$request.body#/callbackUrl}, as their path
+	KindCallback = "callback"
+)
+
+// isEventKind reports whether kind is a webhook or callback
+func isEventKind(kind string) bool {
+	return kind == KindWebhook || kind == KindCallback
+}
+
+// specWebhooks reads the operations of an OpenAPI 3.1 document's webhooks.
+// Webhooks whose refs can't be resolved are logged and skipped.
+func specWebhooks(filePath string, doc map[string]any, refs *specResolver, lines []string) []Endpoint {
+	webhooks, _ := doc["webhooks"].(map[string]any)
+	names := make([]string, 0, len(webhooks))
+	for name := range webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	from := specKeyLine(lines, "webhooks", 0)
+	var found []Endpoint
+	for _, name := range names {
+		value, base, err := refs.deref(webhooks[name], filePath)
+		if err != nil {
+			log.Printf("⚠️  %s: skipping webhook %s: %v", filePath, name, err)
+			continue
+		}
+		line := from
+		if n := specKeyLine(lines, name, from); from > 0 && n > 0 {
+			line = n
+		}
+		item, _ := value.(map[string]any)
+		found = append(found, specEventOperations(filePath, item, eventSite{
+			kind: KindWebhook, name: name, path: name, line: line, inline: !isSpecRef(webhooks[name]),
+		}, refs, base, lines)...)
+	}
+	return found
+}
+
+// specCallbacks reads the callbacks of an operation declared at line. Each
+// callback maps runtime expressions to the path items the API calls, and
+// both the callback and its path items may be $refs.
+func specCallbacks(filePath string, op map[string]any, refs *specResolver, base string, lines []string, line int, inline bool) []Endpoint {
+	callbacks, _ := op["callbacks"].(map[string]any)
+	names := make([]string, 0, len(callbacks))
+	for name := range callbacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var found []Endpoint
+	for _, name := range names {
+		value, callbackBase, err := refs.deref(callbacks[name], base)
+		if err != nil {
+			log.Printf("⚠️  %s: skipping callback %s: %v", filePath, name, err)
+			continue
+		}
+		expressions, _ := value.(map[string]any)
+		keys := make([]string, 0, len(expressions))
+		for expr := range expressions {
+			keys = append(keys, expr)
+		}
+		sort.Strings(keys)
+
+		for _, expr := range keys {
+			itemValue, itemBase, err := refs.deref(expressions[expr], callbackBase)
+			if err != nil {
+				log.Printf("⚠️  %s: skipping callback %s %s: %v", filePath, name, expr, err)
+				continue
+			}
+			site := eventSite{kind: KindCallback, name: name, path: expr, line: line}
+			if inline && !isSpecRef(callbacks[name]) && !isSpecRef(expressions[expr]) {
+				if n := specKeyLine(lines, expr, line); n > 0 {
+					site.line, site.inline = n, true
+				}
+			}
+			item, _ := itemValue.(map[string]any)
+			found = append(found, specEventOperations(filePath, item, site, refs, itemBase, lines)...)
+		}
+	}
+	return found
+}
+
+// eventSite is where a webhook or callback path item is declared
+type eventSite struct {
+	kind, name, path string
+	line             int
+	inline           bool // the path item's method keys are in this file below line
+}
+
+// specEventOperations reads the operations of a webhook or callback path
+// item, and the callbacks those operations declare in turn
+func specEventOperations(filePath string, item map[string]any, site eventSite, refs *specResolver, base string, lines []string) []Endpoint {
+	var found []Endpoint
+	for _, key := range openAPIMethods {
+		op, ok := item[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		method := strings.ToUpper(key)
+		line := site.line
+		if site.inline && line > 0 {
+			if n := specKeyLine(lines, key, line); n > 0 {
+				line = n
+			}
+		}
+		ep := Endpoint{
+			ID:         fmt.Sprintf("%s-%s-%s-%d", scanID(filePath), site.kind, method, line),
+			Path:       site.path,
+			Method:     method,
+			FilePath:   filePath,
+			LineNumber: line,
+			Kind:       site.kind,
+			Event:      site.name,
+			Tags:       jsdocStrings(op["tags"]),
+		}
+		ep.Summary, _ = op["summary"].(string)
+		ep.Description, _ = op["description"].(string)
+		ep.InputModel, ep.ResponseType = specOperationModels(op)
+		ep.ValidatedInput = ep.InputModel != ""
+		found = append(found, ep)
+		found = append(found, specCallbacks(filePath, op, refs, base, lines, line, site.inline)...)
+	}
+	return found
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSpecWebhooksAndCallbacks verifies an OpenAPI 3.1 document's webhooks
+// and operation callbacks, inline and $ref-ed, are reported with their own
+// kinds alongside the routes the API serves
+func TestSpecWebhooksAndCallbacks(t *testing.T) {
+	content := `openapi: "3.1.0"
+info:
+  title: pets-api
+paths:
+  /subscriptions:
+    post:
+      summary: Subscribe to pet events
+      x-amazon-apigateway-integration:
+        type: http_proxy
+        uri: https://pets.internal.example.com/subscriptions
+      callbacks:
+        onEvent:
+          '{$request.body#/callbackUrl}':
+            post:
+              summary: Pet event notification
+        onCancel:
+          $ref: '#/components/callbacks/cancelled'
+webhooks:
+  newPet:
+    post:
+      summary: A pet was added
+      tags: [pets]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+components:
+  callbacks:
+    cancelled:
+      '{$request.body#/cancelUrl}':
+        delete:
+          summary: Subscription cancelled
+  schemas:
+    Pet:
+      type: object
+`
+	endpoints := ScanFile("openapi.yaml", content)
+	want := []struct {
+		method, path, kind, event string
+		line                      int
+	}{
+		{"POST", "/subscriptions", "", "", 6},
+		{"DELETE", "{$request.body#/cancelUrl}", KindCallback, "onCancel", 6},
+		{"POST", "{$request.body#/callbackUrl}", KindCallback, "onEvent", 14},
+		{"POST", "newPet", KindWebhook, "newPet", 20},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		ep := endpoints[i]
+		if ep.Method != w.method || ep.Path != w.path || ep.Kind != w.kind || ep.Event != w.event || ep.LineNumber != w.line {
+			t.Errorf("endpoint %d = %s %s kind %q event %q (line %d), want %s %s kind %q event %q (line %d)",
+				i, ep.Method, ep.Path, ep.Kind, ep.Event, ep.LineNumber, w.method, w.path, w.kind, w.event, w.line)
+		}
+	}
+
+	webhook := endpoints[3]
+	if webhook.Summary != "A pet was added" || webhook.InputModel != "Pet" {
+		t.Errorf("webhook summary %q input %q, want %q and Pet", webhook.Summary, webhook.InputModel, "A pet was added")
+	}
+	if webhook.Confidence < 0.8 {
+		t.Errorf("webhook confidence = %v, want a declared spec operation's", webhook.Confidence)
+	}
+
+	spec := BuildOpenAPI(&ScanStatus{ID: "s"}, endpoints)
+	if paths, _ := spec["paths"].(map[string]any); len(paths) != 1 {
+		t.Errorf("OpenAPI export paths = %v, want only /subscriptions", paths)
+	}
+}
+
+// TestPlainSpecWebhooks verifies an OpenAPI document without API Gateway
+// extensions passes the pre-filter for its webhooks and callbacks, and
+// reports only those: its paths are served by code scanned separately
+func TestPlainSpecWebhooks(t *testing.T) {
+	content := `openapi: "3.1.0"
+info:
+  title: pets-api
+paths:
+  /subscriptions:
+    post:
+      summary: Subscribe to pet events
+      callbacks:
+        onEvent:
+          '{$request.body#/callbackUrl}':
+            post:
+              summary: Pet event notification
+webhooks:
+  newPet:
+    post:
+      summary: A pet was added
+`
+	plain := `openapi: "3.1.0"
+paths:
+  /pets:
+    get:
+      summary: List pets
+`
+	root := t.TempDir()
+	for name, body := range map[string]string{"openapi.yaml": content, "docs/api.yaml": plain} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := getLikelyAPIFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "openapi.yaml" {
+		t.Fatalf("getLikelyAPIFiles() = %v, want only the spec with webhooks", files)
+	}
+
+	endpoints := ScanFile("openapi.yaml", content)
+	want := []struct{ method, path, kind string }{
+		{"POST", "{$request.body#/callbackUrl}", KindCallback},
+		{"POST", "newPet", KindWebhook},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("ScanFile() found %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, w := range want {
+		if ep := endpoints[i]; ep.Method != w.method || ep.Path != w.path || ep.Kind != w.kind {
+			t.Errorf("endpoint %d = %s %s kind %q, want %s %s kind %q", i, ep.Method, ep.Path, ep.Kind, w.method, w.path, w.kind)
+		}
+	}
+}